@@ -0,0 +1,294 @@
+package batch
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diillson/chatcli/llm/client"
+	"github.com/diillson/chatcli/llm/manager"
+	"go.uber.org/zap"
+)
+
+const (
+	DefaultConcurrency = 4
+	DefaultTimeout     = 2 * time.Minute
+	// maxLineSize limita o tamanho de cada linha do arquivo de entrada
+	maxLineSize = 10 * 1024 * 1024
+)
+
+// Item é uma linha do arquivo de entrada do modo batch
+type Item struct {
+	ID       string `json:"id"`
+	Prompt   string `json:"prompt"`
+	Provider string `json:"provider,omitempty"`
+	Model    string `json:"model,omitempty"`
+}
+
+// Result é uma linha do arquivo de saída, com a resposta ou o erro de um item
+type Result struct {
+	ID         string `json:"id"`
+	Prompt     string `json:"prompt"`
+	Provider   string `json:"provider"`
+	Model      string `json:"model,omitempty"`
+	Response   string `json:"response"`
+	Tokens     int    `json:"tokens"`
+	DurationMS int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+	ErrorType  string `json:"error_type,omitempty"`
+	// Race lista os provedores que disputaram o item no modo --race; Provider é o vencedor
+	Race []string `json:"race,omitempty"`
+}
+
+// Options controla a execução do batch
+type Options struct {
+	Concurrency     int
+	Timeout         time.Duration
+	DefaultProvider string
+	// Race, se tiver dois ou mais provedores, envia cada item sem provedor explícito a todos eles
+	// ao mesmo tempo e fica com a primeira resposta bem-sucedida
+	Race []string
+}
+
+// Summary resume o resultado de uma execução
+type Summary struct {
+	Total  int
+	Failed int
+}
+
+// ReadItems lê os itens de um arquivo JSONL, ignorando linhas em branco
+func ReadItems(r io.Reader) ([]Item, error) {
+	var items []Item
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var item Item
+		if err := json.Unmarshal([]byte(line), &item); err != nil {
+			return nil, fmt.Errorf("linha %d: JSON inválido: %w", lineNumber, err)
+		}
+		if strings.TrimSpace(item.Prompt) == "" {
+			return nil, fmt.Errorf("linha %d: campo 'prompt' ausente", lineNumber)
+		}
+		if item.ID == "" {
+			item.ID = fmt.Sprintf("%d", lineNumber)
+		}
+		items = append(items, item)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao ler a entrada: %w", err)
+	}
+	return items, nil
+}
+
+// Run processa os itens com concorrência limitada e tempo limite por item. Falhas individuais são
+// registradas no resultado do item sem interromper o restante. Os resultados são escritos em out
+// na mesma ordem da entrada.
+func Run(ctx context.Context, mgr manager.LLMManager, items []Item, out io.Writer, opts Options, logger *zap.Logger) (Summary, error) {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultConcurrency
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+
+	results := make([]Result, len(items))
+	sem := make(chan struct{}, opts.Concurrency)
+	var wg sync.WaitGroup
+
+	for i, item := range items {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, item Item) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = processItem(ctx, mgr, item, opts)
+			if results[i].Error != "" {
+				logger.Warn("Falha ao processar item do batch", zap.String("id", item.ID), zap.String("erro", results[i].Error))
+			}
+		}(i, item)
+	}
+	wg.Wait()
+
+	summary := Summary{Total: len(results)}
+	encoder := json.NewEncoder(out)
+	encoder.SetEscapeHTML(false)
+	for _, result := range results {
+		if result.Error != "" {
+			summary.Failed++
+		}
+		if err := encoder.Encode(result); err != nil {
+			return summary, fmt.Errorf("erro ao escrever o resultado %s: %w", result.ID, err)
+		}
+	}
+	return summary, nil
+}
+
+// processItem envia o prompt de um item ao provedor e monta o resultado
+func processItem(ctx context.Context, mgr manager.LLMManager, item Item, opts Options) Result {
+	if item.Provider == "" && len(opts.Race) > 1 {
+		return raceItem(ctx, mgr, item, opts)
+	}
+	provider := strings.ToUpper(item.Provider)
+	if provider == "" {
+		provider = opts.DefaultProvider
+	}
+	model := item.Model
+	if model == "" {
+		model = ModelFromEnv(provider)
+	}
+	result := Result{ID: item.ID, Prompt: item.Prompt, Provider: provider, Model: model}
+
+	start := time.Now()
+	llmClient, err := mgr.GetClient(provider, model)
+	if err != nil {
+		result.Error = err.Error()
+		result.ErrorType = "config"
+		return result
+	}
+	result.Model = llmClient.GetModelName()
+
+	itemCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+	response, err := llmClient.SendPrompt(itemCtx, item.Prompt, nil)
+	result.DurationMS = time.Since(start).Milliseconds()
+	if err != nil {
+		if itemCtx.Err() == context.DeadlineExceeded && !errors.Is(err, client.ErrTimeout) {
+			err = fmt.Errorf("%w: %v", client.ErrTimeout, err)
+		}
+		result.Error = err.Error()
+		result.ErrorType = ErrorType(err)
+		return result
+	}
+
+	result.Response = response
+	result.Tokens = estimateTokens(item.Prompt) + estimateTokens(response)
+	return result
+}
+
+// raceOutcome é a resposta (ou o erro) de um dos provedores da disputa
+type raceOutcome struct {
+	provider string
+	model    string
+	response string
+	err      error
+}
+
+// raceItem envia o prompt a todos os provedores de opts.Race simultaneamente e usa a primeira resposta
+// bem-sucedida, cancelando as demais requisições. Se todos falharem, o resultado reúne os erros.
+func raceItem(ctx context.Context, mgr manager.LLMManager, item Item, opts Options) Result {
+	result := Result{ID: item.ID, Prompt: item.Prompt, Race: opts.Race}
+
+	start := time.Now()
+	raceCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	// O canal tem espaço para todos os provedores, de modo que as goroutines dos perdedores
+	// terminam assim que suas requisições são canceladas, sem ficar bloqueadas
+	outcomes := make(chan raceOutcome, len(opts.Race))
+	for _, provider := range opts.Race {
+		go func(provider string) {
+			llmClient, err := mgr.GetClient(provider, ModelFromEnv(provider))
+			if err != nil {
+				outcomes <- raceOutcome{provider: provider, err: err}
+				return
+			}
+			response, err := llmClient.SendPrompt(raceCtx, item.Prompt, nil)
+			outcomes <- raceOutcome{provider: provider, model: llmClient.GetModelName(), response: response, err: err}
+		}(provider)
+	}
+
+	var errs []error
+	for range opts.Race {
+		outcome := <-outcomes
+		if outcome.err == nil {
+			cancel()
+			result.Provider = outcome.provider
+			result.Model = outcome.model
+			result.Response = outcome.response
+			result.DurationMS = time.Since(start).Milliseconds()
+			result.Tokens = estimateTokens(item.Prompt) + estimateTokens(outcome.response)
+			return result
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", outcome.provider, outcome.err))
+	}
+
+	err := errors.Join(errs...)
+	if raceCtx.Err() == context.DeadlineExceeded && !errors.Is(err, client.ErrTimeout) {
+		err = fmt.Errorf("%w: %v", client.ErrTimeout, err)
+	}
+	result.DurationMS = time.Since(start).Milliseconds()
+	result.Error = err.Error()
+	result.ErrorType = ErrorType(err)
+	return result
+}
+
+// Compare envia o prompt a todos os provedores simultaneamente, cada um com seu próprio tempo limite,
+// e aguarda todas as respostas. Ao contrário da disputa de --race, nenhuma requisição é cancelada;
+// os resultados seguem a ordem dos provedores informados.
+func Compare(ctx context.Context, mgr manager.LLMManager, prompt string, providers []string, timeout time.Duration) []Result {
+	results := make([]Result, len(providers))
+	var wg sync.WaitGroup
+	for i, provider := range providers {
+		wg.Add(1)
+		go func(i int, provider string) {
+			defer wg.Done()
+			results[i] = processItem(ctx, mgr, Item{ID: provider, Prompt: prompt, Provider: provider}, Options{Timeout: timeout})
+		}(i, provider)
+	}
+	wg.Wait()
+	return results
+}
+
+// ErrorType classifica o erro a partir dos erros tipados dos provedores
+func ErrorType(err error) string {
+	switch {
+	case errors.Is(err, client.ErrAuth):
+		return "auth"
+	case errors.Is(err, client.ErrRateLimited):
+		return "rate_limited"
+	case errors.Is(err, client.ErrContextTooLong):
+		return "context_too_long"
+	case errors.Is(err, client.ErrTimeout):
+		return "timeout"
+	case errors.Is(err, client.ErrContentFiltered):
+		return "content_filtered"
+	default:
+		return "unknown"
+	}
+}
+
+// ModelFromEnv retorna o modelo configurado por variável de ambiente para o provedor
+func ModelFromEnv(provider string) string {
+	switch provider {
+	case "OPENAI":
+		return os.Getenv("OPENAI_MODEL")
+	case "CLAUDEAI":
+		return os.Getenv("CLAUDEAI_MODEL")
+	case "OLLAMA":
+		return os.Getenv("OLLAMA_MODEL")
+	default:
+		return ""
+	}
+}
+
+// estimateTokens faz uma estimativa grosseira (cerca de 4 caracteres por token), já que os
+// clientes não expõem a contagem de tokens informada pelos provedores
+func estimateTokens(text string) int {
+	if text == "" {
+		return 0
+	}
+	return len([]rune(text))/4 + 1
+}
@@ -0,0 +1,235 @@
+package batch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diillson/chatcli/llm/client"
+	"github.com/diillson/chatcli/llm/token"
+	"github.com/diillson/chatcli/models"
+	"go.uber.org/zap"
+)
+
+// echoClient responde com o próprio prompt, falha quando o prompt pede e demora quando solicitado
+type echoClient struct{ model string }
+
+func (c *echoClient) GetModelName() string { return c.model }
+
+func (c *echoClient) SendPrompt(ctx context.Context, prompt string, history []models.Message) (string, error) {
+	switch prompt {
+	case "limite":
+		return "", client.NewHTTPError("OpenAI", 429, nil, []byte("rate limit"))
+	case "lento":
+		<-ctx.Done()
+		return "", ctx.Err()
+	}
+	return "eco: " + prompt, nil
+}
+
+type fakeManager struct{}
+
+func (m *fakeManager) GetClient(provider, model string) (client.LLMClient, error) {
+	if provider != "OPENAI" {
+		return nil, fmt.Errorf("provedor %s não configurado", provider)
+	}
+	if model == "" {
+		model = "padrao"
+	}
+	return &echoClient{model: model}, nil
+}
+
+func (m *fakeManager) GetAvailableProviders() []string { return []string{"OPENAI"} }
+
+func (m *fakeManager) GetTokenManager() (*token.TokenManager, bool) { return nil, false }
+
+func TestRun(t *testing.T) {
+	input := `{"id": "a", "prompt": "olá"}
+
+{"id": "b", "prompt": "limite"}
+{"id": "c", "prompt": "lento", "model": "gpt-x"}
+{"id": "d", "prompt": "oi", "provider": "claudeai"}
+{"prompt": "sem id"}
+`
+	items, err := ReadItems(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Erro ao ler itens: %v", err)
+	}
+	if len(items) != 5 || items[4].ID != "6" {
+		t.Fatalf("Itens inesperados: %+v", items)
+	}
+
+	var out bytes.Buffer
+	summary, err := Run(context.Background(), &fakeManager{}, items, &out,
+		Options{Concurrency: 2, Timeout: 50 * time.Millisecond, DefaultProvider: "OPENAI"}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if summary.Total != 5 || summary.Failed != 3 {
+		t.Errorf("Resumo inesperado: %+v", summary)
+	}
+
+	var results []Result
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var r Result
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			t.Fatalf("Linha de saída inválida: %s", line)
+		}
+		results = append(results, r)
+	}
+
+	expected := []struct{ id, response, errorType string }{
+		{"a", "eco: olá", ""},
+		{"b", "", "rate_limited"},
+		{"c", "", "timeout"},
+		{"d", "", "config"},
+		{"6", "eco: sem id", ""},
+	}
+	for i, e := range expected {
+		r := results[i]
+		if r.ID != e.id || r.Response != e.response || r.ErrorType != e.errorType {
+			t.Errorf("Resultado %d inesperado: %+v", i, r)
+		}
+	}
+	if results[0].Tokens == 0 || results[2].Model != "gpt-x" {
+		t.Errorf("Tokens ou modelo inesperados: %+v %+v", results[0], results[2])
+	}
+}
+
+func TestReadItemsErrors(t *testing.T) {
+	for _, input := range []string{`{"id": "a"}`, `não é json`} {
+		if _, err := ReadItems(strings.NewReader(input)); err == nil {
+			t.Errorf("Esperado erro para %q", input)
+		}
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	parsed, err := parseArgs([]string{"prompts.jsonl", "--output", "out.jsonl", "--concurrency", "8", "--timeout", "30s"})
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if parsed.input != "prompts.jsonl" || parsed.output != "out.jsonl" || parsed.concurrency != 8 || parsed.timeout != 30*time.Second {
+		t.Errorf("Argumentos inesperados: %+v", parsed)
+	}
+	if parsed, _ := parseArgs([]string{"a.jsonl", "--race", "openai, claudeai"}); len(parsed.race) != 2 || parsed.race[1] != "CLAUDEAI" {
+		t.Errorf("Provedores de --race inesperados: %v", parsed.race)
+	}
+	for _, args := range [][]string{{}, {"a.jsonl", "b.jsonl"}, {"a.jsonl", "--concurrency", "0"}, {"a.jsonl", "--race", "OPENAI"}, {"a.jsonl", "--notify-webhook", "hooks.local"}} {
+		if _, err := parseArgs(args); err == nil {
+			t.Errorf("Esperado erro para %v", args)
+		}
+	}
+}
+
+// slowClient só responde quando a requisição é cancelada, registrando o cancelamento
+type slowClient struct{ cancelled chan struct{} }
+
+func (c *slowClient) GetModelName() string { return "lento" }
+
+func (c *slowClient) SendPrompt(ctx context.Context, prompt string, history []models.Message) (string, error) {
+	<-ctx.Done()
+	close(c.cancelled)
+	return "", ctx.Err()
+}
+
+type raceManager struct {
+	fakeManager
+	slow *slowClient
+}
+
+func (m *raceManager) GetClient(provider, model string) (client.LLMClient, error) {
+	if provider == "CLAUDEAI" {
+		return m.slow, nil
+	}
+	return m.fakeManager.GetClient(provider, model)
+}
+
+func TestRun_race(t *testing.T) {
+	mgr := &raceManager{slow: &slowClient{cancelled: make(chan struct{})}}
+	items := []Item{{ID: "a", Prompt: "olá"}}
+
+	var out bytes.Buffer
+	_, err := Run(context.Background(), mgr, items, &out,
+		Options{Timeout: time.Second, DefaultProvider: "OPENAI", Race: []string{"CLAUDEAI", "OPENAI"}}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	var r Result
+	if err := json.Unmarshal(out.Bytes(), &r); err != nil {
+		t.Fatalf("Saída inválida: %s", out.String())
+	}
+	if r.Provider != "OPENAI" || r.Response != "eco: olá" || len(r.Race) != 2 {
+		t.Errorf("Resultado inesperado: %+v", r)
+	}
+
+	select {
+	case <-mgr.slow.cancelled:
+	case <-time.After(time.Second):
+		t.Error("A requisição do provedor perdedor deveria ter sido cancelada")
+	}
+
+	// Quando todos falham, os erros são reunidos no resultado
+	out.Reset()
+	items = []Item{{ID: "b", Prompt: "limite"}}
+	_, _ = Run(context.Background(), &fakeManager{}, items, &out,
+		Options{Timeout: time.Second, Race: []string{"OPENAI", "GROQ"}}, zap.NewNop())
+	if err := json.Unmarshal(out.Bytes(), &r); err != nil {
+		t.Fatalf("Saída inválida: %s", out.String())
+	}
+	if r.ErrorType != "rate_limited" || !strings.Contains(r.Error, "GROQ") {
+		t.Errorf("Erro inesperado: %+v", r)
+	}
+}
+
+func TestNotify(t *testing.T) {
+	var received Completion
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer server.Close()
+
+	marker := filepath.Join(t.TempDir(), "notificado")
+	parsed := commandArgs{input: "prompts.jsonl", notify: notifyOptions{
+		webhook: server.URL,
+		command: `echo "$CHATCLI_BATCH_STATUS $CHATCLI_BATCH_FAILED" > ` + marker,
+	}}
+	completion := newCompletion(parsed, Summary{Total: 3, Failed: 1}, time.Second, nil)
+	notify(parsed.notify, completion, zap.NewNop())
+
+	if received.Status != "failure" || received.Total != 3 || received.Input != "prompts.jsonl" {
+		t.Errorf("Resumo recebido pelo webhook inesperado: %+v", received)
+	}
+	if data, err := os.ReadFile(marker); err != nil || strings.TrimSpace(string(data)) != "failure 1" {
+		t.Errorf("Comando de notificação não recebeu o resultado: %q (erro: %v)", data, err)
+	}
+
+	// Um webhook inacessível não interrompe nem altera o resultado
+	notify(notifyOptions{webhook: "http://127.0.0.1:1"}, completion, zap.NewNop())
+}
+
+func TestCompare(t *testing.T) {
+	mgr := &raceManager{slow: &slowClient{cancelled: make(chan struct{})}}
+	results := Compare(context.Background(), mgr, "olá", []string{"CLAUDEAI", "OPENAI", "OLLAMA"}, 50*time.Millisecond)
+
+	if len(results) != 3 {
+		t.Fatalf("Esperado um resultado por provedor, obtido: %+v", results)
+	}
+	if results[0].Provider != "CLAUDEAI" || results[0].ErrorType != "timeout" {
+		t.Errorf("Esperado timeout do provedor lento, obtido: %+v", results[0])
+	}
+	if results[1].Provider != "OPENAI" || results[1].Response != "eco: olá" {
+		t.Errorf("Resultado inesperado: %+v", results[1])
+	}
+	if results[2].ErrorType != "config" {
+		t.Errorf("Esperado erro de configuração, obtido: %+v", results[2])
+	}
+}
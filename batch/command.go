@@ -0,0 +1,163 @@
+package batch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diillson/chatcli/llm/manager"
+	"go.uber.org/zap"
+)
+
+const commandUsage = `Uso: chatcli batch <entrada.jsonl> [--output <resultados.jsonl>] [--concurrency N] [--timeout 2m]
+                    [--race OPENAI,CLAUDEAI]
+                    [--notify-webhook <url>] [--notify-command '<comando>']
+
+Cada linha da entrada deve ter {"id", "prompt", "provider"?, "model"?}. Cada linha da saída
+repete o item e adiciona {"response", "tokens", "duration_ms", "error"?}.
+
+Com --race, os itens sem "provider" são enviados a todos os provedores listados ao mesmo tempo;
+vale a primeira resposta bem-sucedida e o vencedor é indicado em "provider".
+
+Ao terminar, com sucesso ou falha, --notify-webhook envia um resumo em JSON por POST e
+--notify-command executa o comando com o resultado nas variáveis CHATCLI_BATCH_*. Falhas na
+notificação são apenas relatadas e não alteram o código de saída.`
+
+// commandArgs são os argumentos de 'chatcli batch' já interpretados
+type commandArgs struct {
+	input       string
+	output      string
+	concurrency int
+	timeout     time.Duration
+	race        []string
+	notify      notifyOptions
+}
+
+// parseArgs interpreta os argumentos de 'chatcli batch', aceitando as flags antes ou depois do arquivo
+func parseArgs(args []string) (commandArgs, error) {
+	parsed := commandArgs{concurrency: DefaultConcurrency, timeout: DefaultTimeout}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case "--output", "-o", "--concurrency", "--timeout", "--race", "--notify-webhook", "--notify-command":
+			if i+1 >= len(args) {
+				return parsed, fmt.Errorf("valor ausente para %s", arg)
+			}
+			value := args[i+1]
+			i++
+			switch arg {
+			case "--output", "-o":
+				parsed.output = value
+			case "--concurrency":
+				n, err := strconv.Atoi(value)
+				if err != nil || n < 1 {
+					return parsed, fmt.Errorf("valor inválido para --concurrency: %s", value)
+				}
+				parsed.concurrency = n
+			case "--timeout":
+				d, err := time.ParseDuration(value)
+				if err != nil || d <= 0 {
+					return parsed, fmt.Errorf("valor inválido para --timeout: %s", value)
+				}
+				parsed.timeout = d
+			case "--race":
+				providers, err := parseRace(value)
+				if err != nil {
+					return parsed, err
+				}
+				parsed.race = providers
+			case "--notify-webhook":
+				if u, err := url.Parse(value); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+					return parsed, fmt.Errorf("valor inválido para --notify-webhook: %s (use uma URL http:// ou https://)", value)
+				}
+				parsed.notify.webhook = value
+			case "--notify-command":
+				parsed.notify.command = value
+			}
+		case "-h", "--help":
+			return parsed, fmt.Errorf("%s", commandUsage)
+		default:
+			if parsed.input != "" {
+				return parsed, fmt.Errorf("argumento inesperado: %s\n\n%s", arg, commandUsage)
+			}
+			parsed.input = arg
+		}
+	}
+	if parsed.input == "" {
+		return parsed, fmt.Errorf("arquivo de entrada ausente\n\n%s", commandUsage)
+	}
+	return parsed, nil
+}
+
+// parseRace interpreta a lista de provedores de --race, separados por vírgula
+func parseRace(value string) ([]string, error) {
+	var providers []string
+	seen := make(map[string]bool)
+	for _, p := range strings.Split(value, ",") {
+		p = strings.ToUpper(strings.TrimSpace(p))
+		if p == "" || seen[p] {
+			continue
+		}
+		seen[p] = true
+		providers = append(providers, p)
+	}
+	if len(providers) < 2 {
+		return nil, fmt.Errorf("--race requer ao menos dois provedores separados por vírgula (ex: OPENAI,CLAUDEAI)")
+	}
+	return providers, nil
+}
+
+// RunCommand executa o subcomando 'batch'. Os resultados vão para o arquivo de --output ou para stdout,
+// e o resumo é escrito em stderr. Ao final, com sucesso ou falha, dispara as notificações pedidas.
+func RunCommand(ctx context.Context, args []string, mgr manager.LLMManager, defaultProvider string, logger *zap.Logger) error {
+	parsed, err := parseArgs(args)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	summary, err := run(ctx, parsed, mgr, defaultProvider, logger)
+	notify(parsed.notify, newCompletion(parsed, summary, time.Since(start), err), logger)
+	return err
+}
+
+// run lê os itens, processa o batch e grava os resultados
+func run(ctx context.Context, parsed commandArgs, mgr manager.LLMManager, defaultProvider string, logger *zap.Logger) (Summary, error) {
+	in, err := os.Open(parsed.input)
+	if err != nil {
+		return Summary{}, fmt.Errorf("erro ao abrir %s: %w", parsed.input, err)
+	}
+	defer in.Close()
+
+	items, err := ReadItems(in)
+	if err != nil {
+		return Summary{}, fmt.Errorf("%s: %w", parsed.input, err)
+	}
+
+	var out io.Writer = os.Stdout
+	if parsed.output != "" {
+		f, err := os.Create(parsed.output)
+		if err != nil {
+			return Summary{}, fmt.Errorf("erro ao criar %s: %w", parsed.output, err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	summary, err := Run(ctx, mgr, items, out, Options{
+		Concurrency:     parsed.concurrency,
+		Timeout:         parsed.timeout,
+		DefaultProvider: defaultProvider,
+		Race:            parsed.race,
+	}, logger)
+	if err != nil {
+		return summary, err
+	}
+	fmt.Fprintf(os.Stderr, "Batch concluído: %d item(ns), %d falha(s).\n", summary.Total, summary.Failed)
+	return summary, nil
+}
@@ -0,0 +1,127 @@
+package batch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/diillson/chatcli/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	// notifyWebhookTimeout limita a chamada ao webhook de notificação, independente do batch
+	notifyWebhookTimeout = 10 * time.Second
+	// notifyCommandTimeout limita a execução do comando de notificação
+	notifyCommandTimeout = time.Minute
+)
+
+// notifyOptions são os destinos das notificações de término do batch
+type notifyOptions struct {
+	webhook string
+	command string
+}
+
+// Completion é o resumo enviado nas notificações de término do batch
+type Completion struct {
+	Status     string `json:"status"`
+	Input      string `json:"input"`
+	Output     string `json:"output,omitempty"`
+	Total      int    `json:"total"`
+	Failed     int    `json:"failed"`
+	DurationMS int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// newCompletion monta o resumo da execução; o status é "failure" se o batch falhou ou algum item falhou
+func newCompletion(parsed commandArgs, summary Summary, duration time.Duration, err error) Completion {
+	completion := Completion{
+		Status:     "success",
+		Input:      parsed.input,
+		Output:     parsed.output,
+		Total:      summary.Total,
+		Failed:     summary.Failed,
+		DurationMS: duration.Milliseconds(),
+	}
+	if err != nil {
+		completion.Error = err.Error()
+	}
+	if err != nil || summary.Failed > 0 {
+		completion.Status = "failure"
+	}
+	return completion
+}
+
+// env retorna as variáveis de ambiente com o resultado, repassadas ao comando de notificação
+func (c Completion) env() []string {
+	return []string{
+		"CHATCLI_BATCH_STATUS=" + c.Status,
+		"CHATCLI_BATCH_INPUT=" + c.Input,
+		"CHATCLI_BATCH_OUTPUT=" + c.Output,
+		"CHATCLI_BATCH_TOTAL=" + strconv.Itoa(c.Total),
+		"CHATCLI_BATCH_FAILED=" + strconv.Itoa(c.Failed),
+		"CHATCLI_BATCH_DURATION_MS=" + strconv.FormatInt(c.DurationMS, 10),
+		"CHATCLI_BATCH_ERROR=" + c.Error,
+	}
+}
+
+// notify dispara as notificações configuradas. Falhas são relatadas em stderr, sem alterar o resultado do batch.
+func notify(opts notifyOptions, completion Completion, logger *zap.Logger) {
+	if opts.webhook != "" {
+		client := utils.NewHTTPClient(logger, notifyWebhookTimeout)
+		if err := postWebhook(client, opts.webhook, completion); err != nil {
+			logger.Warn("Falha ao notificar o webhook", zap.Error(err))
+			fmt.Fprintln(os.Stderr, "Aviso: falha ao notificar o webhook:", err)
+		}
+	}
+	if opts.command != "" {
+		if err := runNotifyCommand(opts.command, completion); err != nil {
+			logger.Warn("Falha ao executar o comando de notificação", zap.Error(err))
+			fmt.Fprintln(os.Stderr, "Aviso: falha ao executar o comando de notificação:", err)
+		}
+	}
+}
+
+// postWebhook envia o resumo em JSON para a URL, com tempo limite próprio
+func postWebhook(client *http.Client, url string, completion Completion) error {
+	payload, err := json.Marshal(completion)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), notifyWebhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("o webhook retornou o status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// runNotifyCommand executa o comando com sh, com o resultado nas variáveis CHATCLI_BATCH_*. A saída do
+// comando vai para stderr para não se misturar aos resultados escritos em stdout.
+func runNotifyCommand(command string, completion Completion) error {
+	ctx, cancel := context.WithTimeout(context.Background(), notifyCommandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Env = append(os.Environ(), completion.env()...)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
@@ -2,43 +2,102 @@ package cli
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"strings"
 	"sync"
 	"time"
+
+	"golang.org/x/term"
+)
+
+const (
+	defaultThinkingText = "está pensando..."
+	spinnerInterval     = 100 * time.Millisecond
 )
 
+// spinnerThemes são os estilos de animação aceitos em CHATCLI_SPINNER
+var spinnerThemes = map[string][]string{
+	"line": {"|", "/", "-", "\\"},
+	"dots": {"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"},
+	"moon": {"🌑", "🌒", "🌓", "🌔", "🌕", "🌖", "🌗", "🌘"},
+}
+
 type AnimationManager struct {
-	wg   sync.WaitGroup
-	done chan bool
+	mu      sync.Mutex
+	wg      sync.WaitGroup
+	done    chan struct{}
+	out     io.Writer
+	frames  []string
+	text    string
+	enabled bool
 }
 
+// NewAnimationManager cria o gerenciador da animação "pensando", lendo o estilo de CHATCLI_SPINNER
+// e o texto de CHATCLI_THINKING_TEXT. A animação é desativada quando a saída não é um terminal.
 func NewAnimationManager() *AnimationManager {
-	return &AnimationManager{}
+	frames, ok := spinnerThemes[strings.ToLower(os.Getenv("CHATCLI_SPINNER"))]
+	if !ok {
+		frames = spinnerThemes["line"]
+	}
+	text := os.Getenv("CHATCLI_THINKING_TEXT")
+	if text == "" {
+		text = defaultThinkingText
+	}
+	return &AnimationManager{
+		out:     os.Stdout,
+		frames:  frames,
+		text:    text,
+		enabled: term.IsTerminal(int(os.Stdout.Fd())),
+	}
 }
 
 func (am *AnimationManager) ShowThinkingAnimation(clientName string) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+	if !am.enabled || am.done != nil {
+		return
+	}
+
+	done := make(chan struct{})
+	am.done = done
 	am.wg.Add(1)
-	am.done = make(chan bool)
 
 	go func() {
 		defer am.wg.Done()
-		spinner := []string{"|", "/", "-", "\\"}
-		i := 0
-		for {
+		start := time.Now()
+		ticker := time.NewTicker(spinnerInterval)
+		defer ticker.Stop()
+		for i := 0; ; i++ {
+			fmt.Fprintf(am.out, "\r%s %s %s%s", clientName, am.text, am.frames[i%len(am.frames)], formatElapsed(time.Since(start)))
 			select {
-			case <-am.done:
-				fmt.Printf("\r\033[K") // Limpa a linha corretamente
+			case <-done:
+				fmt.Fprint(am.out, "\r\033[K") // Limpa a linha corretamente
 				return
-			default:
-				fmt.Printf("\r%s está pensando... %s", clientName, spinner[i%len(spinner)])
-				time.Sleep(100 * time.Millisecond)
-				i++
+			case <-ticker.C:
 			}
 		}
 	}()
 }
 
+// StopThinkingAnimation encerra a animação e só retorna depois que a goroutine terminou de escrever,
+// para que nenhuma saída da animação apareça misturada à resposta
 func (am *AnimationManager) StopThinkingAnimation() {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+	if am.done == nil {
+		return
+	}
 	close(am.done)
 	am.wg.Wait()
-	fmt.Printf("\n") // Garante que a próxima saída comece em uma nova linha
+	am.done = nil
+	fmt.Fprint(am.out, "\n") // Garante que a próxima saída comece em uma nova linha
+}
+
+// formatElapsed retorna o contador de tempo exibido ao lado da animação a partir do primeiro segundo
+func formatElapsed(elapsed time.Duration) string {
+	if elapsed < time.Second {
+		return ""
+	}
+	return fmt.Sprintf(" (%ds)", int(elapsed.Seconds()))
 }
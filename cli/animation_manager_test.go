@@ -1,6 +1,8 @@
 package cli
 
 import (
+	"bytes"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -20,3 +22,36 @@ func TestAnimationManager(t *testing.T) {
 	am.StopThinkingAnimation()
 	wg.Wait()
 }
+
+func TestAnimationManager_themeAndText(t *testing.T) {
+	t.Setenv("CHATCLI_SPINNER", "dots")
+	t.Setenv("CHATCLI_THINKING_TEXT", "processando")
+	am := NewAnimationManager()
+
+	var out bytes.Buffer
+	am.out = &out
+	am.enabled = true
+
+	am.ShowThinkingAnimation("modelo")
+	time.Sleep(150 * time.Millisecond)
+	am.StopThinkingAnimation()
+	am.StopThinkingAnimation() // parar novamente não deve causar pânico
+
+	if !strings.Contains(out.String(), "modelo processando ⠋") {
+		t.Errorf("Saída inesperada da animação: %q", out.String())
+	}
+	written := out.Len()
+	time.Sleep(150 * time.Millisecond)
+	if out.Len() != written {
+		t.Error("A animação não deveria escrever após ser parada")
+	}
+}
+
+func TestFormatElapsed(t *testing.T) {
+	if got := formatElapsed(500 * time.Millisecond); got != "" {
+		t.Errorf("Esperado vazio antes de 1s, obtido %q", got)
+	}
+	if got := formatElapsed(3200 * time.Millisecond); got != " (3s)" {
+		t.Errorf("Esperado ' (3s)', obtido %q", got)
+	}
+}
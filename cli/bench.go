@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/diillson/chatcli/batch"
+)
+
+const (
+	// defaultBenchTimeout é o tempo limite de cada provedor quando --timeout não é informado
+	defaultBenchTimeout = 2 * time.Minute
+	// benchPreviewChars limita a prévia de cada resposta exibida na comparação
+	benchPreviewChars = 120
+)
+
+const benchUsage = `Uso: /bench "<prompt>" [--providers OPENAI,CLAUDEAI] [--timeout 2m] | /bench show <N>`
+
+// benchRequest representa um '/bench "<prompt>" [--providers P1,P2] [--timeout 2m]'
+type benchRequest struct {
+	prompt    string
+	providers []string
+	timeout   time.Duration
+}
+
+// parseBenchArgs interpreta os argumentos de /bench. Sem --providers, compara todos os provedores
+// disponíveis.
+func parseBenchArgs(userInput string, available []string) (benchRequest, error) {
+	req := benchRequest{providers: available, timeout: defaultBenchTimeout}
+	fields, err := parseFields(userInput)
+	if err != nil {
+		return req, err
+	}
+
+	var prompt []string
+	for i := 1; i < len(fields); i++ {
+		switch fields[i] {
+		case "--providers", "--timeout":
+			if i+1 >= len(fields) {
+				return req, fmt.Errorf("valor ausente para %s", fields[i])
+			}
+			value := fields[i+1]
+			if fields[i] == "--timeout" {
+				if req.timeout, err = parseCommandTimeout(value); err != nil {
+					return req, err
+				}
+			} else {
+				req.providers = nil
+				for _, p := range strings.Split(value, ",") {
+					if p = strings.ToUpper(strings.TrimSpace(p)); p != "" {
+						req.providers = append(req.providers, p)
+					}
+				}
+			}
+			i++
+		default:
+			prompt = append(prompt, fields[i])
+		}
+	}
+
+	req.prompt = strings.TrimSpace(strings.Join(prompt, " "))
+	if req.prompt == "" {
+		return req, fmt.Errorf("prompt ausente")
+	}
+	if len(req.providers) == 0 {
+		return req, fmt.Errorf("nenhum provedor para comparar")
+	}
+	return req, nil
+}
+
+// handleBenchCommand trata /bench, que envia o mesmo prompt a vários provedores e compara as respostas,
+// e /bench show N, que exibe a resposta completa de um deles
+func (cli *ChatCLI) handleBenchCommand(userInput string) {
+	args := strings.Fields(userInput)
+	if len(args) >= 2 && args[1] == "show" {
+		cli.showBenchResponse(args[2:])
+		return
+	}
+
+	req, err := parseBenchArgs(userInput, cli.manager.GetAvailableProviders())
+	if err != nil {
+		fmt.Printf("Erro: %v\n%s\n", err, benchUsage)
+		return
+	}
+
+	cli.animation.ShowThinkingAnimation(strings.Join(req.providers, ", "))
+	results := batch.Compare(context.Background(), cli.manager, req.prompt, req.providers, req.timeout)
+	cli.animation.StopThinkingAnimation()
+
+	cli.benchResults = results
+	printBenchResults(results)
+}
+
+// printBenchResults exibe a tabela comparativa e uma prévia de cada resposta
+func printBenchResults(results []batch.Result) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tPROVEDOR\tMODELO\tLATÊNCIA\tTOKENS (estim.)\tRESULTADO")
+	for i, r := range results {
+		outcome := "ok"
+		if r.Error != "" {
+			outcome = "erro: " + r.ErrorType
+		}
+		latency := (time.Duration(r.DurationMS) * time.Millisecond).Round(10 * time.Millisecond)
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\t%s\n", i+1, r.Provider, r.Model, latency, r.Tokens, outcome)
+	}
+	w.Flush()
+
+	fmt.Println()
+	for i, r := range results {
+		preview := r.Response
+		if r.Error != "" {
+			preview = r.Error
+		}
+		preview = strings.Join(strings.Fields(preview), " ")
+		fmt.Printf("[%d] %s: %s\n", i+1, r.Provider, trimText(preview, benchPreviewChars))
+	}
+	fmt.Println("\nUse /bench show <N> para ver a resposta completa.")
+}
+
+// showBenchResponse exibe a resposta completa do N-ésimo provedor da última comparação
+func (cli *ChatCLI) showBenchResponse(args []string) {
+	if len(cli.benchResults) == 0 {
+		fmt.Println("Nenhuma comparação nesta sessão. Use /bench \"<prompt>\" primeiro.")
+		return
+	}
+	n := 0
+	if len(args) == 1 {
+		n, _ = strconv.Atoi(args[0])
+	}
+	if n < 1 || n > len(cli.benchResults) {
+		fmt.Printf("Uso: /bench show <N> - N deve estar entre 1 e %d.\n", len(cli.benchResults))
+		return
+	}
+
+	r := cli.benchResults[n-1]
+	if r.Error != "" {
+		fmt.Printf("%s (%s) falhou: %s\n", r.Provider, r.Model, r.Error)
+		return
+	}
+	fmt.Printf("\n%s (%s):\n%s\n", r.Provider, r.Model, cli.renderMarkdown(r.Response))
+}
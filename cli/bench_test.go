@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseBenchArgs(t *testing.T) {
+	req, err := parseBenchArgs(`/bench "explique goroutines" --providers openai,claudeai --timeout 30s`, []string{"OPENAI"})
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if req.prompt != "explique goroutines" || len(req.providers) != 2 || req.providers[1] != "CLAUDEAI" || req.timeout != 30*time.Second {
+		t.Errorf("Requisição inesperada: %+v", req)
+	}
+
+	req, err = parseBenchArgs("/bench olá", []string{"OPENAI", "OLLAMA"})
+	if err != nil || len(req.providers) != 2 || req.timeout != defaultBenchTimeout {
+		t.Errorf("Esperado todos os provedores disponíveis, obtido: %+v (erro: %v)", req, err)
+	}
+
+	for _, input := range []string{"/bench", "/bench olá --providers", "/bench olá --timeout x", `/bench "olá`} {
+		if _, err := parseBenchArgs(input, []string{"OPENAI"}); err == nil {
+			t.Errorf("Esperado erro para %q", input)
+		}
+	}
+}
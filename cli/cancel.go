@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"go.uber.org/zap"
+)
+
+// interruptDispatcher é o único destinatário de SIGINT e SIGTERM. Com uma operação em andamento, Ctrl+C
+// cancela apenas ela; sem operação, ou com SIGTERM, cancela o contexto raiz e encerra o programa. Com
+// dois destinatários do mesmo sinal, o Ctrl+C que interrompe uma resposta também encerraria o ChatCLI.
+type interruptDispatcher struct {
+	mu         sync.Mutex
+	nextID     int
+	operations map[int]context.CancelFunc
+	shutdown   context.CancelFunc
+}
+
+var interrupts = &interruptDispatcher{operations: make(map[int]context.CancelFunc)}
+
+// register marca uma operação em andamento, que passa a receber o Ctrl+C, até a chamada de unregister
+func (d *interruptDispatcher) register(cancel context.CancelFunc) (unregister func()) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	id := d.nextID
+	d.nextID++
+	d.operations[id] = cancel
+	return func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		delete(d.operations, id)
+	}
+}
+
+// dispatch trata um sinal e indica se ele encerrou o programa
+func (d *interruptDispatcher) dispatch(sig os.Signal) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, cancel := range d.operations {
+		cancel()
+	}
+	if sig == os.Interrupt && len(d.operations) > 0 {
+		return false
+	}
+	if d.shutdown != nil {
+		d.shutdown()
+	}
+	return true
+}
+
+// HandleSignals passa o tratamento de SIGINT e SIGTERM ao ChatCLI: shutdown é chamado para encerrar o
+// programa, e o Ctrl+C durante uma requisição ou um @command cancela apenas a operação
+func HandleSignals(shutdown context.CancelFunc, logger *zap.Logger) {
+	interrupts.mu.Lock()
+	interrupts.shutdown = shutdown
+	interrupts.mu.Unlock()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		for sig := range signals {
+			if interrupts.dispatch(sig) {
+				logger.Info("Recebido sinal para finalizar a aplicação", zap.String("sinal", sig.String()))
+				return
+			}
+			logger.Debug("Ctrl+C cancelou a operação em andamento")
+		}
+	}()
+}
+
+// interruptible retorna um contexto cancelado quando o usuário pressiona Ctrl+C durante a requisição
+// ou a exibição da resposta. Sem isso, o sinal encerraria o programa no meio de uma sequência de
+// escape, deixando cores e cursor do terminal alterados.
+func interruptible(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	unregister := interrupts.register(cancel)
+	return ctx, func() {
+		unregister()
+		cancel()
+	}
+}
+
+// cancelOperation encerra a animação, reseta os atributos do terminal e termina a linha atual, para
+// que o próximo prompt não apareça colorido ou emendado na resposta interrompida
+func (cli *ChatCLI) cancelOperation(out io.Writer, message string) {
+	cli.animation.StopThinkingAnimation()
+	fmt.Fprint(out, terminalResetSequence+"\n")
+	fmt.Fprintln(out, message)
+}
@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var testSignalsOnce sync.Once
+
+// testRootContext instala o tratamento de sinais uma única vez no processo de teste e retorna o contexto
+// raiz que um Ctrl+C sem operação em andamento cancelaria
+func testRootContext(t *testing.T) context.Context {
+	root, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	testSignalsOnce.Do(func() { HandleSignals(cancel, zap.NewNop()) })
+	interrupts.mu.Lock()
+	interrupts.shutdown = cancel
+	interrupts.mu.Unlock()
+	return root
+}
+
+// sendInterrupt envia SIGINT ao próprio processo, como o Ctrl+C no terminal
+func sendInterrupt(t *testing.T) {
+	p, err := os.FindProcess(os.Getpid())
+	if err == nil {
+		err = p.Signal(os.Interrupt)
+	}
+	if err != nil {
+		t.Skipf("Não foi possível enviar SIGINT nesta plataforma: %v", err)
+	}
+}
+
+func TestWriteTypewriterStopsOnCancel(t *testing.T) {
+	var out bytes.Buffer
+	if !writeTypewriter(context.Background(), &out, "\033[1mOlá\033[0m", 0) || out.String() != "\033[1mOlá\033[0m" {
+		t.Errorf("Esperado o texto completo, obteve %q", out.String())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	out.Reset()
+	if writeTypewriter(ctx, &out, "\033[31mtexto vermelho", time.Millisecond) {
+		t.Error("Esperado interromper a escrita com o contexto cancelado")
+	}
+	// A sequência de escape é escrita inteira e a escrita para no primeiro caractere visível
+	if out.String() != "\033[31mt" {
+		t.Errorf("Saída inesperada após o cancelamento: %q", out.String())
+	}
+}
+
+func TestCancelOperationResetsTerminal(t *testing.T) {
+	cli := &ChatCLI{animation: &AnimationManager{}}
+	var out bytes.Buffer
+	cli.cancelOperation(&out, "Requisição cancelada.")
+
+	if !strings.HasPrefix(out.String(), terminalResetSequence+"\n") {
+		t.Errorf("Esperado resetar o terminal e terminar a linha, obteve %q", out.String())
+	}
+	if !strings.HasSuffix(out.String(), "Requisição cancelada.\n") {
+		t.Errorf("Esperado a mensagem de cancelamento, obteve %q", out.String())
+	}
+}
+
+func TestInterruptCancelsOnlyTheOperation(t *testing.T) {
+	root := testRootContext(t)
+
+	op, stop := interruptible(root)
+	defer stop()
+	sendInterrupt(t)
+
+	select {
+	case <-op.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("Ctrl+C deveria cancelar a operação em andamento")
+	}
+	if root.Err() != nil {
+		t.Error("Ctrl+C durante uma operação não deveria encerrar o programa")
+	}
+}
+
+func TestInterruptDispatcherShutdown(t *testing.T) {
+	shutdowns := 0
+	d := &interruptDispatcher{operations: make(map[int]context.CancelFunc), shutdown: func() { shutdowns++ }}
+
+	if !d.dispatch(os.Interrupt) || shutdowns != 1 {
+		t.Error("Ctrl+C sem operação em andamento deveria encerrar o programa")
+	}
+
+	canceled := false
+	unregister := d.register(func() { canceled = true })
+	if !d.dispatch(syscall.SIGTERM) || !canceled || shutdowns != 2 {
+		t.Error("SIGTERM deveria cancelar a operação e encerrar o programa")
+	}
+	unregister()
+	if len(d.operations) != 0 {
+		t.Error("A operação deveria ser removida ao terminar")
+	}
+}
+
+func TestInterruptCancelsOnlyTheCommand(t *testing.T) {
+	root := testRootContext(t)
+	cli := &ChatCLI{logger: zap.NewNop(), animation: &AnimationManager{}}
+
+	done := make(chan error, 1)
+	go func() { done <- cli.runDirectCommand("sleep 5", commandOptions{quiet: true}, "") }()
+	time.Sleep(200 * time.Millisecond)
+	sendInterrupt(t)
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("Esperado erro do comando interrompido")
+		}
+	case <-time.After(4 * time.Second):
+		t.Fatal("Ctrl+C deveria interromper o @command")
+	}
+	if root.Err() != nil {
+		t.Error("Ctrl+C durante um @command não deveria encerrar o programa")
+	}
+	if cli.lastCommandResult == nil || !cli.lastCommandResult.result.canceled {
+		t.Errorf("Esperado o comando registrado como cancelado: %+v", cli.lastCommandResult)
+	}
+}
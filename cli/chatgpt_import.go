@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/diillson/chatcli/models"
+	"github.com/diillson/chatcli/utils"
+	"github.com/peterh/liner"
+	"go.uber.org/zap"
+)
+
+// chatGPTConversation é uma conversa do conversations.json exportado pelo ChatGPT. As mensagens formam
+// uma árvore (cada edição cria um ramo) e current_node aponta para a última mensagem do ramo exibido.
+type chatGPTConversation struct {
+	Title       string                 `json:"title"`
+	CreateTime  float64                `json:"create_time"`
+	CurrentNode string                 `json:"current_node"`
+	Mapping     map[string]chatGPTNode `json:"mapping"`
+}
+
+type chatGPTNode struct {
+	Parent  string `json:"parent"`
+	Message *struct {
+		Author struct {
+			Role string `json:"role"`
+		} `json:"author"`
+		Content struct {
+			ContentType string            `json:"content_type"`
+			Parts       []json.RawMessage `json:"parts"`
+		} `json:"content"`
+	} `json:"message"`
+}
+
+// importedConversation é uma conversa já convertida para o histórico do ChatCLI
+type importedConversation struct {
+	title    string
+	messages []models.Message
+}
+
+// parseChatGPTExport converte as conversas exportadas, mantendo apenas as mensagens de texto do
+// usuário e do assistente do ramo exibido. Conversas sem mensagens são ignoradas.
+func parseChatGPTExport(data []byte) ([]importedConversation, error) {
+	var raw []chatGPTConversation
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("o arquivo não é uma exportação de conversas do ChatGPT (conversations.json): %w", err)
+	}
+	sort.SliceStable(raw, func(i, j int) bool { return raw[i].CreateTime > raw[j].CreateTime })
+
+	var conversations []importedConversation
+	for _, c := range raw {
+		messages := linearizeChatGPT(c)
+		if len(messages) == 0 {
+			continue
+		}
+		title := strings.TrimSpace(c.Title)
+		if title == "" {
+			title = "(sem título)"
+		}
+		conversations = append(conversations, importedConversation{title: title, messages: messages})
+	}
+	return conversations, nil
+}
+
+// linearizeChatGPT percorre o ramo exibido de current_node até a raiz e o devolve em ordem cronológica
+func linearizeChatGPT(c chatGPTConversation) []models.Message {
+	var messages []models.Message
+	visited := make(map[string]bool)
+	for id := c.CurrentNode; id != "" && !visited[id]; id = c.Mapping[id].Parent {
+		visited[id] = true
+		node, ok := c.Mapping[id]
+		if !ok || node.Message == nil {
+			continue
+		}
+		role := node.Message.Author.Role
+		if role != "user" && role != "assistant" {
+			continue
+		}
+		if node.Message.Content.ContentType != "text" {
+			continue
+		}
+		var parts []string
+		for _, part := range node.Message.Content.Parts {
+			var text string
+			if json.Unmarshal(part, &text) == nil && strings.TrimSpace(text) != "" {
+				parts = append(parts, text)
+			}
+		}
+		if len(parts) == 0 {
+			continue
+		}
+		messages = append(messages, models.Message{Role: role, Content: strings.Join(parts, "\n")})
+	}
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+	return messages
+}
+
+// handleImportOpenAICommand trata /import-openai <conversations.json>: lista as conversas exportadas
+// e carrega a escolhida no histórico da sessão
+func (cli *ChatCLI) handleImportOpenAICommand(userInput string) {
+	args, err := parseFields(userInput)
+	if err != nil || len(args) != 2 {
+		fmt.Println("Uso: /import-openai <conversations.json>")
+		return
+	}
+	path, err := utils.ExpandPath(args[1])
+	if err != nil {
+		fmt.Println("Erro:", err)
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Println("Erro ao ler a exportação:", err)
+		return
+	}
+	conversations, err := parseChatGPTExport(data)
+	if err != nil {
+		fmt.Println("Erro:", err)
+		return
+	}
+	if len(conversations) == 0 {
+		fmt.Println("A exportação não contém conversas com mensagens de texto.")
+		return
+	}
+
+	fmt.Println("Conversas encontradas (da mais recente para a mais antiga):")
+	for i, c := range conversations {
+		fmt.Printf("%d. %s (%d mensagens)\n", i+1, c.title, len(c.messages))
+	}
+	choice, err := cli.line.Prompt("Selecione a conversa pelo número: ")
+	if err != nil {
+		if err == liner.ErrPromptAborted {
+			fmt.Println("\nImportação cancelada.")
+			return
+		}
+		cli.logger.Error("Erro ao ler a escolha", zap.Error(err))
+		return
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(choice))
+	if err != nil || n < 1 || n > len(conversations) {
+		fmt.Println("Escolha inválida.")
+		return
+	}
+
+	selected := conversations[n-1]
+	if len(cli.history) > 0 && !cli.confirm(fmt.Sprintf("Substituir as %d mensagens do histórico atual? (s/N): ", len(cli.history))) {
+		fmt.Println("Nada foi importado.")
+		return
+	}
+	cli.history = selected.messages
+	cli.redoStack = nil
+	cli.logger.Info("Conversa do ChatGPT importada", zap.String("titulo", selected.title), zap.Int("mensagens", len(selected.messages)))
+	fmt.Printf("Conversa \"%s\" importada com %d mensagens (~%d tokens). Continue a conversa normalmente.\n",
+		selected.title, len(selected.messages), estimateTokens(selected.messages))
+}
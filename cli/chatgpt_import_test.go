@@ -0,0 +1,59 @@
+package cli
+
+import "testing"
+
+const chatGPTExportFixture = `[
+  {
+    "title": "Antiga",
+    "create_time": 1700000000.5,
+    "current_node": "a2",
+    "mapping": {
+      "a1": {"parent": null, "message": {"author": {"role": "user"}, "content": {"content_type": "text", "parts": ["oi"]}}},
+      "a2": {"parent": "a1", "message": {"author": {"role": "assistant"}, "content": {"content_type": "text", "parts": ["olá"]}}}
+    }
+  },
+  {
+    "title": "Recente",
+    "create_time": 1710000000,
+    "current_node": "n5",
+    "mapping": {
+      "root": {"parent": null, "message": null},
+      "n1": {"parent": "root", "message": {"author": {"role": "system"}, "content": {"content_type": "text", "parts": [""]}}},
+      "n2": {"parent": "n1", "message": {"author": {"role": "user"}, "content": {"content_type": "text", "parts": ["primeira versão"]}}},
+      "n3": {"parent": "n1", "message": {"author": {"role": "user"}, "content": {"content_type": "text", "parts": ["pergunta editada"]}}},
+      "n4": {"parent": "n3", "message": {"author": {"role": "tool"}, "content": {"content_type": "code", "parts": ["x"]}}},
+      "n5": {"parent": "n4", "message": {"author": {"role": "assistant"}, "content": {"content_type": "text", "parts": ["resposta", {"asset": "imagem"}]}}}
+    }
+  },
+  {"title": "Vazia", "create_time": 1720000000, "current_node": "", "mapping": {}}
+]`
+
+func TestParseChatGPTExport(t *testing.T) {
+	conversations, err := parseChatGPTExport([]byte(chatGPTExportFixture))
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if len(conversations) != 2 {
+		t.Fatalf("esperava 2 conversas, obteve %d", len(conversations))
+	}
+	if conversations[0].title != "Recente" || conversations[1].title != "Antiga" {
+		t.Errorf("ordem inesperada: %q, %q", conversations[0].title, conversations[1].title)
+	}
+
+	messages := conversations[0].messages
+	if len(messages) != 2 {
+		t.Fatalf("esperava 2 mensagens do ramo exibido, obteve %d: %+v", len(messages), messages)
+	}
+	if messages[0].Role != "user" || messages[0].Content != "pergunta editada" {
+		t.Errorf("primeira mensagem inesperada: %+v", messages[0])
+	}
+	if messages[1].Role != "assistant" || messages[1].Content != "resposta" {
+		t.Errorf("segunda mensagem inesperada: %+v", messages[1])
+	}
+}
+
+func TestParseChatGPTExport_invalid(t *testing.T) {
+	if _, err := parseChatGPTExport([]byte(`{"title": "não é uma lista"}`)); err == nil {
+		t.Error("esperava erro para um arquivo que não é uma lista de conversas")
+	}
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// citationPattern reconhece as referências às fontes na resposta, como [S1] ou [S1, S3]
+var citationPattern = regexp.MustCompile(`S(\d+)`)
+
+// citationRefPattern reconhece os trechos entre colchetes que contêm referências
+var citationRefPattern = regexp.MustCompile(`\[(S\d+(?:\s*,\s*S\d+)*)\]`)
+
+// contextSource é uma fonte de contexto injetada no prompt (arquivo ou diretório), identificada
+// por um id curto que o modelo usa para citá-la no modo /cite
+type contextSource struct {
+	id     string
+	origin string
+}
+
+// tagSource registra a fonte quando o modo /cite está ativo e retorna a linha que identifica o bloco
+// de contexto no prompt; com o modo desativado, retorna vazio
+func (cli *ChatCLI) tagSource(origin string) string {
+	if !cli.citeMode {
+		return ""
+	}
+	id := fmt.Sprintf("S%d", len(cli.contextSources)+1)
+	cli.contextSources = append(cli.contextSources, contextSource{id: id, origin: origin})
+	return sourceTag(id)
+}
+
+// sourceTag é a linha que identifica a fonte no contexto, reconhecida por sourceTagPattern
+func sourceTag(id string) string {
+	return fmt.Sprintf("\n[Fonte %s]", id)
+}
+
+// citationInstruction pede ao modelo que cite os ids das fontes usadas na resposta
+func citationInstruction(sources []contextSource) string {
+	ids := make([]string, len(sources))
+	for i, s := range sources {
+		ids[i] = "[" + s.id + "]"
+	}
+	return fmt.Sprintf("\nAo usar informações das fontes acima (%s), cite-as no texto com o identificador entre colchetes, por exemplo [%s].\n",
+		strings.Join(ids, ", "), sources[0].id)
+}
+
+// appendCitationFootnotes acrescenta à resposta a lista das fontes citadas, na ordem da primeira citação,
+// com o caminho original de cada uma. Ids desconhecidos são ignorados.
+func appendCitationFootnotes(response string, sources []contextSource) string {
+	byID := make(map[string]string, len(sources))
+	for _, s := range sources {
+		byID[s.id] = s.origin
+	}
+
+	var cited []string
+	seen := make(map[string]bool)
+	for _, ref := range citationRefPattern.FindAllStringSubmatch(response, -1) {
+		for _, m := range citationPattern.FindAllString(ref[1], -1) {
+			if _, ok := byID[m]; ok && !seen[m] {
+				seen[m] = true
+				cited = append(cited, m)
+			}
+		}
+	}
+	if len(cited) == 0 {
+		return response
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.TrimRight(response, "\n"))
+	b.WriteString("\n\n---\nFontes:\n")
+	for _, id := range cited {
+		fmt.Fprintf(&b, "- [%s] %s\n", id, byID[id])
+	}
+	return b.String()
+}
+
+// handleCiteCommand trata /cite [on|off], que ativa ou desativa as citações de fontes nas respostas
+func (cli *ChatCLI) handleCiteCommand(userInput string) {
+	args := strings.Fields(userInput)
+	if len(args) > 1 {
+		switch args[1] {
+		case "on":
+			cli.citeMode = true
+		case "off":
+			cli.citeMode = false
+		default:
+			fmt.Println("Uso: /cite [on|off]")
+			return
+		}
+	}
+	if cli.citeMode {
+		fmt.Println("Citações ativadas: os arquivos adicionados com @file recebem um id e as respostas listam as fontes citadas.")
+	} else {
+		fmt.Println("Citações desativadas. Use '/cite on' para ativar.")
+	}
+}
@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAppendCitationFootnotes(t *testing.T) {
+	sources := []contextSource{{id: "S1", origin: "main.go"}, {id: "S2", origin: "cli/cli.go"}, {id: "S3", origin: "README.md"}}
+
+	got := appendCitationFootnotes("O loop fica em [S2], iniciado por [S1, S2]. Veja também [S9].", sources)
+	if !strings.HasSuffix(got, "Fontes:\n- [S2] cli/cli.go\n- [S1] main.go\n") {
+		t.Errorf("Notas de rodapé inesperadas:\n%s", got)
+	}
+	if strings.Contains(got, "README.md") {
+		t.Error("Fontes não citadas não deveriam ser listadas")
+	}
+
+	if got := appendCitationFootnotes("Sem citações.", sources); got != "Sem citações." {
+		t.Errorf("Resposta sem citações não deveria mudar: %q", got)
+	}
+}
+
+func TestTagSource(t *testing.T) {
+	cli := &ChatCLI{}
+	if tag := cli.tagSource("main.go"); tag != "" || len(cli.contextSources) != 0 {
+		t.Errorf("Com /cite desativado nada deveria ser registrado: %q", tag)
+	}
+
+	cli.citeMode = true
+	cli.tagSource("main.go")
+	if tag := cli.tagSource("go.mod"); tag != "\n[Fonte S2]" || cli.contextSources[1].origin != "go.mod" {
+		t.Errorf("Tag inesperada: %q (%+v)", tag, cli.contextSources)
+	}
+}
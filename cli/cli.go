@@ -1,16 +1,20 @@
 package cli
 
 import (
-	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"github.com/diillson/chatcli/batch"
+	"github.com/diillson/chatcli/config"
 	"github.com/diillson/chatcli/llm/client"
 	"github.com/diillson/chatcli/llm/manager"
 	"github.com/joho/godotenv"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -29,6 +33,7 @@ const (
 	defaultTenantName    = "zup"
 	defaultClaudeAIModel = "claude-3-5-sonnet-20241022"
 	defaultOpenAIModel   = "gpt-4o-mini"
+	defaultOllamaModel   = "llama3.1"
 )
 
 // Logger interface para facilitar a testabilidade
@@ -62,7 +67,34 @@ type ChatCLI struct {
 	historyManager    *HistoryManager
 	animation         *AnimationManager
 	commandHandler    *CommandHandler
-	lastCommandOutput string
+	lastCommandResult *executedCommand
+	project           *config.ProjectConfig
+	redoStack         [][]models.Message
+	generationParams  models.GenerationParams
+	memory            *MemoryStore
+	executedCommands  []recordedCommand
+	citeMode          bool
+	autoRouting       bool
+	offline           bool
+	offlineQueue      []string
+	lastRequest       []models.Message
+	responseFormat    string
+	contextSources    []contextSource
+	vars              map[string]sessionVar
+	historyStrategy   historyStrategy
+	lastPrompt        string
+	benchResults      []batch.Result
+	primingMessages   []models.Message
+	sessionContext    []string
+	attachedFiles     map[string]bool
+	// promptFileUsage são os arquivos e bytes de @file do prompt atual, e flagContextLimits os limites
+	// globais informados por flags
+	promptFileUsage   contextUsage
+	flagContextLimits ContextLimits
+	latencies         []latencySample
+	systemFile        string
+	systemFileContent string
+	formatters        map[string][]string
 }
 
 // reconfigureLogger reconfigura o logger após o reload das variáveis de ambiente
@@ -91,8 +123,22 @@ func (cli *ChatCLI) reloadConfiguration() {
 
 	// Limpar variáveis de ambiente
 	variablesToUnset := []string{
-		"LOG_LEVEL", "ENV", "LLM_PROVIDER", "LOG_FILE", "OPENAI_API_KEY", "OPENAI_MODEL",
-		"CLAUDEAI_API_KEY", "CLAUDEAI_MODEL", "CLIENT_ID", "CLIENT_SECRET", "SLUG_NAME", "TENANT_NAME",
+		"LOG_LEVEL", "ENV", "LLM_PROVIDER", "LOG_FILE", "OPENAI_API_KEY", "OPENAI_API_KEYS", "OPENAI_MODEL",
+		"CLAUDEAI_API_KEY", "CLAUDEAI_MODEL", "OPENAI_BASE_URL", "CLAUDEAI_BASE_URL",
+		"OLLAMA_HOST", "OLLAMA_MODEL", "OLLAMA_ENABLED", "CLIENT_ID", "CLIENT_SECRET", "SLUG_NAME", "TENANT_NAME",
+		"CHATCLI_CONNECT_TIMEOUT", "CHATCLI_IDLE_TIMEOUT", "CHATCLI_AUTO_SUMMARIZE", "CHATCLI_CA_BUNDLE", "CHATCLI_DEBUG_HTTP", "CHATCLI_ENCRYPTION_KEY", "CHATCLI_HISTORY_STRATEGY", "CHATCLI_HISTORY_LAST_N", "GITHUB_TOKEN", "GITHUB_API_URL", "CHATCLI_THEME", "CHATCLI_TEMPLATES_DIR", "CHATCLI_SYSTEM_FILE", "CHATCLI_FORMATTERS_FILE", "CHATCLI_ROUTING_FILE", "CHATCLI_ALIASES_FILE", "CHATCLI_PROVIDERS_FILE", "CHATCLI_INPUT_HISTORY_FILE", "CHATCLI_HISTORY_EXCLUDE_SECRETS", "CHATCLI_SERVER_TOKEN", "CHATCLI_SCRUB_SECRETS", "CHATCLI_SCRUB_ALLOWLIST", "CHATCLI_DEFAULT_CONTEXT", "CHATCLI_DEFAULT_CONTEXT_MAX_TOKENS", "CHATCLI_CONTEXT_MAX_TOKENS", "CHATCLI_MAX_CONTEXT_FILES", "CHATCLI_MAX_CONTEXT_BYTES", "CHATCLI_COMMAND_OUTPUT_LIMIT",
+		"CHATCLI_TEMPERATURE", "CHATCLI_TOP_P", "CHATCLI_PRESENCE_PENALTY", "CHATCLI_FREQUENCY_PENALTY", "CHATCLI_MAX_TOKENS",
+	}
+
+	// As chaves dos provedores registrados com /providers add também são relidas do .env
+	if path, err := config.ProvidersPath(); err == nil {
+		if providers, err := config.LoadCustomProviders(path); err == nil {
+			for _, p := range providers {
+				if p.APIKeyEnv != "" {
+					variablesToUnset = append(variablesToUnset, p.APIKeyEnv)
+				}
+			}
+		}
 	}
 
 	for _, variable := range variablesToUnset {
@@ -107,6 +153,22 @@ func (cli *ChatCLI) reloadConfiguration() {
 
 	cli.reconfigureLogger()
 
+	// Recarregar a memória, que pode depender de CHATCLI_MEMORY_FILE e CHATCLI_ENCRYPTION_KEY
+	cli.memory = NewMemoryStore(os.Getenv("CHATCLI_MEMORY_FILE"), cli.logger)
+	if err := cli.memory.Load(); err != nil {
+		cli.logger.Error("Erro ao carregar a memória", zap.Error(err))
+		fmt.Println("Aviso: a memória não foi carregada:", err)
+	}
+
+	cli.loadHistoryStrategy()
+
+	cli.loadFormattersFromEnv()
+
+	// CHATCLI_SYSTEM_FILE pode ter mudado no .env
+	if err := cli.loadSystemFile(); err != nil {
+		fmt.Println("Aviso: arquivo de prompt de sistema ignorado:", err)
+	}
+
 	// Recarregar a configuração do LLMManager
 	utils.CheckEnvVariables(cli.logger, defaultSlugName, defaultTenantName)
 
@@ -126,6 +188,7 @@ func (cli *ChatCLI) reloadConfiguration() {
 	}
 
 	cli.client = client
+	cli.applyGenerationParams()
 	fmt.Println("Configurações recarregadas com sucesso!")
 }
 
@@ -134,6 +197,16 @@ func (cli *ChatCLI) configureProviderAndModel() {
 	if cli.provider == "" {
 		cli.provider = "STACKSPOT" // Usar padrão se não estiver definido
 	}
+	// A configuração de projeto sobrescreve os padrões das variáveis de ambiente
+	if cli.project != nil && cli.project.Provider != "" {
+		cli.provider = cli.project.Provider
+	}
+	// AUTO ativa o roteamento por prompt, partindo do provedor da rota padrão ou do primeiro disponível
+	cli.autoRouting = strings.EqualFold(cli.provider, autoProvider)
+	if cli.autoRouting {
+		cli.provider = cli.initialAutoProvider()
+	}
+	cli.model = ""
 	if cli.provider == "OPENAI" {
 		cli.model = os.Getenv("OPENAI_MODEL")
 		if cli.model == "" {
@@ -146,6 +219,104 @@ func (cli *ChatCLI) configureProviderAndModel() {
 			cli.model = defaultClaudeAIModel
 		}
 	}
+	if cli.provider == "OLLAMA" {
+		cli.model = utils.GetEnvOrDefault("OLLAMA_MODEL", defaultOllamaModel)
+	}
+	if cli.model == "" {
+		cli.model = modelForProvider(cli.provider)
+	}
+	if cli.project != nil && cli.project.Model != "" && cli.provider != "STACKSPOT" {
+		cli.model = cli.project.Model
+	}
+}
+
+// loadProjectConfig procura o arquivo de configuração de projeto (.chatcli.yaml ou .chatcli.toml)
+// no diretório atual ou no ancestral mais próximo
+func (cli *ChatCLI) loadProjectConfig() {
+	cwd, err := os.Getwd()
+	if err != nil {
+		cli.logger.Warn("Não foi possível obter o diretório atual", zap.Error(err))
+		return
+	}
+
+	project, err := config.LoadProjectConfig(cwd)
+	if err != nil {
+		cli.logger.Error("Erro ao carregar a configuração de projeto", zap.Error(err))
+		fmt.Println("Aviso: configuração de projeto ignorada:", err)
+		cli.project = nil
+		return
+	}
+
+	cli.project = project
+	if project != nil {
+		cli.logger.Info("Configuração de projeto carregada", zap.String("path", project.Path))
+	}
+	if err := cli.loadSystemFile(); err != nil {
+		fmt.Println("Aviso: arquivo de prompt de sistema ignorado:", err)
+	}
+}
+
+// reloadProjectConfig relê a configuração de projeto e reaplica provedor, modelo e prompt de sistema
+func (cli *ChatCLI) reloadProjectConfig() {
+	cli.loadProjectConfig()
+	cli.loadFormattersFromEnv()
+	cli.configureProviderAndModel()
+
+	client, err := cli.manager.GetClient(cli.provider, cli.model)
+	if err != nil {
+		cli.logger.Error("Erro ao obter o cliente LLM", zap.Error(err))
+		fmt.Println("Erro ao aplicar a configuração de projeto:", err)
+		return
+	}
+	cli.client = client
+	cli.applyGenerationParams()
+
+	if cli.project == nil {
+		fmt.Println("Nenhum arquivo de configuração de projeto encontrado.")
+	} else {
+		fmt.Printf("Configuração de projeto recarregada de %s\n", cli.project.Path)
+	}
+	fmt.Printf("Você está conversando com %s (%s)\n", cli.client.GetModelName(), cli.provider)
+}
+
+// handleConfigCommand trata os comandos /config dentro do chat
+func (cli *ChatCLI) handleConfigCommand(userInput string) {
+	args := strings.Fields(userInput)
+	if len(args) > 1 && args[1] == "reload" {
+		cli.reloadProjectConfig()
+		return
+	}
+
+	if cli.project == nil {
+		fmt.Println("Nenhum arquivo de configuração de projeto carregado.")
+	} else {
+		fmt.Printf("Configuração de projeto: %s\n", cli.project.Path)
+	}
+	fmt.Println("Use '/config reload' para reler o arquivo após editá-lo.")
+}
+
+// buildSystemContext monta o contexto de sistema a partir dos fatos memorizados, do prompt de
+// sistema da configuração de projeto e dos arquivos do contexto padrão
+func (cli *ChatCLI) buildSystemContext() string {
+	var builder strings.Builder
+	if memory := cli.memory.SystemContext(); memory != "" {
+		builder.WriteString(memory + "\n\n")
+	}
+	builder.WriteString(cli.systemPrompt())
+	builder.WriteString(cli.scrubSecrets(cli.defaultContextText(), "o contexto padrão"))
+
+	return strings.TrimSpace(builder.String())
+}
+
+// historyForRequest retorna o histórico enviado ao provedor, reduzido conforme a estratégia de histórico
+// e precedido do contexto de sistema, se houver
+func (cli *ChatCLI) historyForRequest() []models.Message {
+	history := applyHistoryStrategy(cli.history, cli.historyStrategy)
+	systemContext := cli.buildSystemContext()
+	if systemContext == "" {
+		return history
+	}
+	return append([]models.Message{{Role: "system", Content: systemContext, SystemPrompt: true}}, history...)
 }
 
 // NewChatCLI cria uma nova instância de ChatCLI
@@ -156,10 +327,25 @@ func NewChatCLI(manager manager.LLMManager, logger *zap.Logger) (*ChatCLI, error
 		history:        make([]models.Message, 0),
 		historyManager: NewHistoryManager(logger),
 		animation:      NewAnimationManager(),
+		memory:         NewMemoryStore(os.Getenv("CHATCLI_MEMORY_FILE"), logger),
+	}
+
+	if err := cli.memory.Load(); err != nil {
+		logger.Error("Erro ao carregar a memória", zap.Error(err))
+		fmt.Println("Aviso: a memória não foi carregada:", err)
 	}
 
+	cli.loadProjectConfig()
 	cli.configureProviderAndModel()
 
+	if params, err := generationParamsFromEnv(); err != nil {
+		logger.Warn("Parâmetros de geração padrão ignorados", zap.Error(err))
+		fmt.Println("Aviso: parâmetros de geração padrão ignorados:", err)
+	} else {
+		cli.generationParams = params
+	}
+	cli.loadHistoryStrategy()
+
 	client, err := manager.GetClient(cli.provider, cli.model)
 	if err != nil {
 		logger.Error("Erro ao obter o cliente LLM", zap.Error(err))
@@ -170,6 +356,7 @@ func NewChatCLI(manager manager.LLMManager, logger *zap.Logger) (*ChatCLI, error
 	line.SetCtrlCAborts(true) // Permite que Ctrl+C aborte o input
 
 	cli.client = client
+	cli.applyGenerationParams()
 	cli.line = line
 	cli.history = []models.Message{}
 	cli.commandHistory = []string{}
@@ -198,6 +385,9 @@ func (cli *ChatCLI) Start(ctx context.Context) {
 
 	fmt.Println("\n\nBem-vindo ao ChatCLI!")
 	fmt.Printf("Você está conversando com %s (%s)\n", cli.client.GetModelName(), cli.provider)
+	if cli.project != nil {
+		fmt.Printf("Configuração de projeto carregada de %s\n", cli.project.Path)
+	}
 	fmt.Println("Digite '/exit', 'exit', '/quit' ou 'quit' para sair.")
 	fmt.Println("Digite '/switch' para trocar de provedor.")
 	fmt.Println("Digite '/switch --slugname <slug>' para trocar o slug.")
@@ -249,53 +439,149 @@ func (cli *ChatCLI) Start(ctx context.Context) {
 				continue
 			}
 
-			// Processar comandos especiais
-			userInput, additionalContext := cli.processSpecialCommands(input)
+			cli.sendPrompt(ctx, input)
+		}
+	}
+}
+
+// sendPrompt processa os comandos especiais da entrada, envia o prompt ao modelo com o histórico e
+// exibe a resposta
+func (cli *ChatCLI) sendPrompt(ctx context.Context, input string) {
+	if cli.offline {
+		cli.queueOffline(input)
+		return
+	}
+	cli.lastPrompt = input
+
+	// @provider escolhe o provedor (ou o apelido de /alias) apenas deste prompt, inclusive no roteamento automático
+	input, override := extractProviderOverride(input)
+	if override != nil {
+		if restore := cli.useRoute(cli.aliasRoute(*override), "escolhido com @provider"); restore != nil {
+			defer restore()
+		}
+	}
+
+	// As imagens de @image são validadas antes de tudo, para não enviar um prompt incompleto
+	images, input, err := extractImages(input)
+	if err != nil {
+		fmt.Println("Erro no comando @image:", err)
+		return
+	}
+	var imageClient client.ImageCapable
+	if len(images) > 0 {
+		if imageClient, err = cli.imageClient(); err != nil {
+			fmt.Println("Erro:", err)
+			return
+		}
+	}
+
+	// Processar comandos especiais
+	userInput, additionalContext := cli.processSpecialCommands(input)
+	additionalContext = cli.scrubSecrets(additionalContext, "o contexto do prompt")
+
+	// Os limites globais de arquivos e bytes valem para a soma de @file, das partes enviadas e do contexto padrão
+	if err := cli.checkContextLimits(); err != nil {
+		cli.logger.Warn("Prompt recusado por exceder os limites de contexto", zap.Error(err))
+		fmt.Println("Erro:", err)
+		cli.primingMessages = nil
+		return
+	}
 
-			// Adicionar a mensagem do usuário ao histórico
-			cli.history = append(cli.history, models.Message{
-				Role:    "user",
-				Content: userInput + additionalContext,
-			})
+	// No modo JSON, o prompt pede explicitamente uma resposta em JSON
+	if cli.responseFormat == client.ResponseFormatJSON {
+		additionalContext += jsonInstruction
+	}
 
-			// Exibir mensagem "Pensando..." com animação
-			cli.animation.ShowThinkingAnimation(cli.client.GetModelName())
+	// No roteamento automático, o provedor é escolhido a partir do prompt já com o contexto. Prompts com
+	// imagens ficam no provedor atual, já validado para visão.
+	if cli.autoRouting && override == nil && len(images) == 0 {
+		if restore := cli.autoRoute(userInput, additionalContext); restore != nil {
+			defer restore()
+		}
+	}
+	for i := range cli.primingMessages {
+		cli.primingMessages[i].Content = cli.scrubSecrets(cli.primingMessages[i].Content, "o arquivo enviado em partes")
+	}
 
-			// Criar um contexto com timeout
-			responseCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
-			defer cancel()
+	// Uma nova mensagem invalida as trocas desfeitas com /undo
+	cli.redoStack = nil
 
-			// Enviar o prompt para o LLM
-			aiResponse, err := cli.client.SendPrompt(responseCtx, userInput+additionalContext, cli.history)
+	// As partes de @file --mode chunked entram no histórico antes da pergunta
+	cli.history = append(cli.history, cli.primingMessages...)
+	cli.primingMessages = nil
 
-			// Parar a animação
-			cli.animation.StopThinkingAnimation()
+	// Adicionar a mensagem do usuário ao histórico
+	cli.history = append(cli.history, models.Message{
+		Role:    "user",
+		Content: userInput + additionalContext + imageNote(images),
+	})
 
-			if err != nil {
-				cli.logger.Error("Erro do LLM", zap.Error(err))
+	// Ctrl+C cancela a requisição ou a exibição da resposta sem encerrar o programa
+	ctx, stop := interruptible(ctx)
+	defer stop()
 
-				// Verifique se o erro contém o código de status 429 explicitamente
-				if strings.Contains(err.Error(), "429") || strings.Contains(err.Error(), "RATE_LIMIT_EXCEEDED") {
-					fmt.Println("Limite de requisições excedido. Por favor, aguarde antes de tentar novamente.")
-				} else {
-					fmt.Println("Ocorreu um erro ao processar a requisição.")
-				}
+	// Exibir mensagem "Pensando..." com animação
+	cli.animation.ShowThinkingAnimation(cli.client.GetModelName())
 
-				continue
-			}
+	// Criar um contexto com timeout
+	responseCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	// Enviar o prompt para o LLM, com as imagens quando houver
+	var aiResponse string
+	history := cli.historyForRequest()
+	cli.recordRequest(history, userInput+additionalContext)
+	start := time.Now()
+	if imageClient != nil {
+		aiResponse, err = imageClient.SendPromptWithImages(responseCtx, userInput+additionalContext, images, history)
+	} else {
+		aiResponse, err = cli.client.SendPrompt(responseCtx, userInput+additionalContext, history)
+	}
+	cli.recordLatency(time.Since(start), err)
+
+	// Parar a animação
+	cli.animation.StopThinkingAnimation()
 
-			// Adicionar a resposta da IA ao histórico
-			cli.history = append(cli.history, models.Message{
-				Role:    "assistant",
-				Content: aiResponse,
-			})
+	if err != nil && ctx.Err() != nil {
+		cli.cancelOperation(os.Stdout, "Requisição cancelada.")
+		return
+	}
+	if err != nil {
+		cli.logger.Error("Erro do LLM", zap.Error(err))
 
-			// Renderizar a resposta da IA
-			renderedResponse := cli.renderMarkdown(aiResponse)
-			// Exibir a resposta da IA com efeito de digitação
-			cli.typewriterEffect(fmt.Sprintf("\n%s:\n%s\n", cli.client.GetModelName(), renderedResponse), 2*time.Millisecond)
+		fmt.Println(llmErrorMessage(err))
+		if errors.Is(err, client.ErrContentFiltered) {
+			cli.offerDropBlockedPrompt()
 		}
+
+		return
+	}
+
+	// No modo JSON, validar (e reparar, se possível) a resposta; senão, formatar os blocos de código
+	if cli.responseFormat == client.ResponseFormatJSON {
+		aiResponse = cli.enforceJSON(aiResponse)
+	} else {
+		aiResponse = cli.applyFormatters(aiResponse)
+	}
+
+	// Adicionar a resposta da IA ao histórico
+	cli.history = append(cli.history, models.Message{
+		Role:    "assistant",
+		Content: aiResponse,
+	})
+
+	// No modo /cite, listar ao final as fontes citadas pelo modelo
+	if cli.citeMode {
+		aiResponse = appendCitationFootnotes(aiResponse, cli.contextSources)
 	}
+
+	// Renderizar a resposta da IA
+	renderedResponse := cli.renderResponse(aiResponse)
+	// Exibir a resposta da IA com efeito de digitação
+	cli.displayResponse(ctx, renderedResponse)
+
+	// Resumir as trocas mais antigas se o histórico ultrapassar o limite configurado
+	cli.autoSummarizeIfNeeded(ctx)
 }
 
 // cleanup realiza a limpeza de recursos ao encerrar o ChatCLI
@@ -329,7 +615,60 @@ func (cli *ChatCLI) handleCommand(userInput string) bool {
 }
 
 func (cli *ChatCLI) handleSwitchCommand(userInput string) {
-	args := strings.Fields(userInput)
+	if strings.Contains(" "+userInput+" ", " --list ") {
+		cli.showProviders()
+		return
+	}
+
+	// --auto ativa o roteamento automático; /switch --auto off o desativa
+	if fields := strings.Fields(userInput); len(fields) > 1 && fields[1] == "--auto" {
+		cli.handleAutoSwitch(len(fields) > 2 && fields[2] == "off")
+		return
+	}
+
+	// --save grava o provedor, o modelo e os parâmetros resultantes como padrão no .env;
+	// --response-format json|text equivale a /format; --model aceita um modelo ou um apelido de /alias
+	var fields []string
+	save := false
+	responseFormat, model := "", ""
+	all := strings.Fields(userInput)
+	for i := 0; i < len(all); i++ {
+		switch {
+		case all[i] == "--save":
+			save = true
+		case all[i] == "--response-format" && i+1 < len(all):
+			responseFormat = all[i+1]
+			i++
+		case all[i] == "--model" && i+1 < len(all):
+			model = all[i+1]
+			i++
+		default:
+			fields = append(fields, all[i])
+		}
+	}
+	if responseFormat != "" {
+		format, err := parseResponseFormat(responseFormat)
+		if err != nil {
+			fmt.Println("Erro:", err)
+			return
+		}
+		cli.setResponseFormat(format)
+		if len(fields) == 1 && model == "" {
+			return
+		}
+	}
+
+	params, args, hasGenerationFlags, err := parseGenerationFlags(fields, cli.generationParams)
+	if err != nil {
+		fmt.Println("Erro:", err)
+		return
+	}
+	if hasGenerationFlags {
+		cli.generationParams = params
+		cli.applyGenerationParams()
+		fmt.Printf("Parâmetros de geração: %s\n", cli.generationParams)
+	}
+
 	var newSlugName, newTenantName string
 	shouldUpdateToken := false
 
@@ -372,17 +711,29 @@ func (cli *ChatCLI) handleSwitchCommand(userInput string) {
 					fmt.Println("Token atualizado com sucesso!")
 				}
 			}
+			if save {
+				cli.saveDefaults(map[string]string{"SLUG_NAME": currentSlugName, "TENANT_NAME": currentTenantName})
+			}
 		} else {
 			fmt.Println("TokenManager não configurado. O provedor STACKSPOT não está disponível.")
 		}
 		return
 	}
 
-	// Se não houver argumentos, processar a troca de provedor
-	cli.switchProvider()
+	// Com --model, troca o modelo; sem argumentos, processa a troca de provedor
+	if model != "" {
+		if !cli.switchModel(model) {
+			return
+		}
+	} else if !hasGenerationFlags && !cli.switchProvider() {
+		return
+	}
+	if save {
+		cli.saveDefaults(nil)
+	}
 }
 
-func (cli *ChatCLI) switchProvider() {
+func (cli *ChatCLI) switchProvider() bool {
 	fmt.Println("Provedores disponíveis:")
 	availableProviders := cli.manager.GetAvailableProviders()
 	for i, provider := range availableProviders {
@@ -393,10 +744,10 @@ func (cli *ChatCLI) switchProvider() {
 	if err != nil {
 		if err == liner.ErrPromptAborted {
 			fmt.Println("\nEntrada abortada!")
-			return
+			return false
 		}
 		cli.logger.Error("Erro ao ler a escolha", zap.Error(err))
-		return
+		return false
 	}
 	choiceInput = strings.TrimSpace(choiceInput)
 
@@ -410,44 +761,99 @@ func (cli *ChatCLI) switchProvider() {
 
 	if choiceIndex == -1 {
 		fmt.Println("Escolha inválida.")
-		return
+		return false
 	}
 
 	newProvider := availableProviders[choiceIndex]
-	var newModel string
-	if newProvider == "OPENAI" {
-		newModel = utils.GetEnvOrDefault("OPENAI_MODEL", defaultOpenAIModel)
-	}
-
-	if newProvider == "CLAUDEAI" {
-		newModel = utils.GetEnvOrDefault("CLAUDEAI_MODEL", defaultClaudeAIModel)
-	}
+	newModel := modelForProvider(newProvider)
 
 	newClient, err := cli.manager.GetClient(newProvider, newModel)
 	if err != nil {
 		cli.logger.Error("Erro ao trocar de provedor", zap.Error(err))
-		return
+		return false
 	}
 
 	cli.client = newClient
+	cli.applyGenerationParams()
 	cli.provider = newProvider
 	cli.model = newModel
-	cli.history = nil // Reiniciar o histórico da conversa
+	cli.autoRouting = false // Escolher um provedor desativa o roteamento automático
+	cli.history = nil       // Reiniciar o histórico da conversa
 	fmt.Printf("Trocado para %s (%s)\n\n", cli.client.GetModelName(), cli.provider)
+	return true
 }
 
 func (cli *ChatCLI) showHelp() {
 	fmt.Println("Comandos disponíveis:")
 	fmt.Println("@history - Adiciona o histórico do shell ao contexto")
 	fmt.Println("@git - Adiciona informações do Git ao contexto")
+	fmt.Println("@git blame <arquivo> [--lines 40:60] - Adiciona o autor, o commit e a data da última alteração de cada linha")
+	fmt.Println("@github <owner/repo#123> [--comments N] [--diff] - Adiciona uma issue ou pull request do GitHub ao contexto")
 	fmt.Println("@env - Adiciona variáveis de ambiente ao contexto")
+	fmt.Println("@image <caminho> [--detail low|high] - Envia uma imagem (PNG, JPEG, GIF ou WebP) para modelos com visão")
 	fmt.Println("@file <caminho_do_arquivo> - Adiciona o conteúdo de um arquivo ao contexto")
+	fmt.Println("@file --lines 40:120 <caminho_do_arquivo> - Adiciona apenas os intervalos de linhas informados (ex: 1:20,100:150)")
+	fmt.Println("@file --tree <diretório> [--depth 3] [--include \"*.go,go.mod\"] - Adiciona a estrutura do diretório e o conteúdo dos arquivos correspondentes")
+	fmt.Println("@file --mode chunked <caminho_do_arquivo> - Envia um arquivo grande em partes, como mensagens anteriores à pergunta")
 	fmt.Println("@command <seu_comando> - para executar um comando diretamente no sistema")
 	fmt.Println("@command --ai <seu_comando> para enviar o ouput para a AI de forma direta e '>' {maior} <seu contexto> para que a AI faça algo.")
 	fmt.Println("@command -i <seu_comando> - para executar um comando interativo")
+	fmt.Println("@command --timeout 2m --dir <diretório> <seu_comando> - define um tempo limite e o diretório de execução")
+	fmt.Println("@command --max-output 200KB --output <arquivo> <seu_comando> - limita a saída enviada à IA e grava a saída completa em um arquivo")
+	fmt.Println("@command --skip-preflight <seu_comando> - executa sem verificar antes se as ferramentas usadas estão instaladas")
+	fmt.Println("@command --quiet <seu_comando> - exibe a saída apenas ao final da execução, de uma só vez, em vez de linha a linha")
+	fmt.Println("@command --shell bash|zsh|sh|pwsh <seu_comando> - executa o comando no shell escolhido em vez do shell padrão")
+	fmt.Println("@command --as <NOME> <seu_comando> - guarda a saída do comando para ser reutilizada com @var <NOME>")
+	fmt.Println("@var <NOME> - adiciona ao contexto a saída guardada com @command --as, sem reexecutar o comando")
+	fmt.Println("@clipboard [--lang <linguagem>] - Adiciona o texto da área de transferência ao contexto")
+	fmt.Println("@docker-logs <contêiner> [--tail 200] [--since 10m] - Adiciona os logs recentes de um contêiner ao contexto")
+	fmt.Println("@docker-inspect <contêiner> - Adiciona o estado e a configuração de um contêiner ao contexto")
+	fmt.Println("@k8s get|describe|logs|top <argumentos> [> pergunta] - Adiciona a saída de um comando de leitura do kubectl ao contexto")
 	fmt.Println("/exit ou /quit - Sai do ChatCLI")
+	fmt.Println("/status - Resume o estado da sessão: provedor, modelo, parâmetros, contexto e tamanho das requisições")
 	fmt.Println("/switch - Troca o provedor de LLM")
+	fmt.Println("/switch --model <modelo|apelido> - Troca o modelo (PROVEDOR/modelo ou um apelido de /alias), mantendo o histórico")
+	fmt.Println("/alias [list] | set <nome> <PROVEDOR/modelo> | remove <nome> - Gerencia apelidos de modelos para /switch --model e @provider")
+	fmt.Println("/switch --list (ou /providers) - Lista os provedores, credenciais e modelo padrão de cada um")
+	fmt.Println("/providers add <nome> --base-url <url> [--api-key-env <VAR>] --model <modelo> | remove <nome> - Registra ou remove um provedor compatível com a OpenAI")
 	fmt.Println("/switch --slugname <slug> --tenantname <tenant> - Define slug e tenant")
+	fmt.Println("/switch --auto [off] - Ativa (ou desativa) o roteamento automático de cada prompt por heurísticas (contexto longo, código, pergunta rápida)")
+	fmt.Println("@provider <NOME>[:<modelo>] - Envia apenas este prompt ao provedor indicado")
+	fmt.Println("/switch --save - Grava no .env o provedor, o modelo e os parâmetros de geração escolhidos como padrão (pode ser combinado com as demais flags)")
+	fmt.Println("/switch --temperature 0.2 --top-p 0.9 - Define os parâmetros de geração da sessão (também --presence-penalty, --frequency-penalty e --max-tokens; use 'default' para remover)")
+	fmt.Println("/undo [N] - Remove as últimas N trocas do histórico da conversa (padrão 1)")
+	fmt.Println("/redo - Restaura a última troca removida com /undo")
+	fmt.Println("/remember <texto> - Memoriza um fato que será incluído no contexto de todas as sessões")
+	fmt.Println("/forget <id> - Remove um fato memorizado")
+	fmt.Println("/history show - Lista as mensagens do histórico da sessão")
+	fmt.Println("/history clear - Apaga o histórico da sessão")
+	fmt.Println("/history search <termo> - Busca nas entradas digitadas nesta e nas sessões anteriores (Ctrl+R busca no prompt)")
+	fmt.Println("/history strategy [full|last-n [N]|summarize] - Define como o histórico é enviado ao provedor")
+	fmt.Println("/template save <nome> [texto] - Salva o texto (ou o último prompt) como template, com placeholders {{nome}}")
+	fmt.Println("/template run <nome> [chave=valor...] - Preenche o template e o envia como prompt")
+	fmt.Println("/template list | delete <nome> - Lista ou remove os templates salvos")
+	fmt.Println("/bench \"<prompt>\" [--providers OPENAI,CLAUDEAI] [--timeout 2m] - Compara latência, tokens e respostas dos provedores")
+	fmt.Println("/bench show <N> - Exibe a resposta completa do N-ésimo provedor da última comparação")
+	fmt.Println("/keys check - Verifica as credenciais de cada provedor com uma chamada mínima, sem exibir as chaves")
+	fmt.Println("/defaultctx show | add <arquivo|glob> | remove <arquivo|glob> - Gerencia os arquivos incluídos automaticamente em todo prompt")
+	fmt.Println("/edit [last] - Compõe o próximo prompt no editor de $EDITOR (com 'last', a partir do último prompt) e o envia após confirmação")
+	fmt.Println("/system show | reload - Mostra o prompt de sistema efetivo ou relê o arquivo de CHATCLI_SYSTEM_FILE/system_prompt_file")
+	fmt.Println("/offline [on|off|flush] - Coloca os prompts em fila enquanto não há conexão e os envia com flush")
+	fmt.Println("/format [json|text] - Pede respostas em JSON (modo nativo da OpenAI e do Ollama; nos demais, instrução e reparo do JSON)")
+	fmt.Println("/import-openai <conversations.json> - Lista as conversas de uma exportação do ChatGPT e carrega a escolhida no histórico")
+	fmt.Println("/explain [pergunta] - Pede à IA que explique o último @command: o comando, o código de saída e a saída")
+	fmt.Println("/doctor - Diagnostica o ambiente: .env, provedores, conexão, ferramentas externas, log e ~/.chatcli")
+	fmt.Println("/trace [prompt|last] - Mostra as mensagens enviadas ao provedor, com os tokens estimados de cada uma, sem enviar nada")
+	fmt.Println("/latency - Mostra a latência mínima, média, p95 e máxima das chamadas da sessão por provedor e modelo")
+	fmt.Println("/vars - Lista as saídas de comandos guardadas na sessão")
+	fmt.Println("/memory list - Lista os fatos memorizados")
+	fmt.Println("/memory encrypt - Criptografa o arquivo de memória com CHATCLI_ENCRYPTION_KEY")
+	fmt.Println("/replay [--to <arquivo.sh>] [--continue] [--skip-preflight] - Reexecuta os comandos @command da sessão (ou grava-os em um script)")
+	fmt.Println("/cite [on|off] - Identifica os arquivos de @file no prompt e lista ao final da resposta as fontes citadas")
+	fmt.Println("/page - Abre a última resposta no pager ($PAGER ou less)")
+	fmt.Println("/save [N] [caminho] - Lista os blocos de código da última resposta ou grava o bloco N (no arquivo sugerido em ```go:main.go ou no caminho informado)")
+	fmt.Println("/summarize [N] - Resume as N trocas mais antigas do histórico (padrão: todas exceto as 2 mais recentes)")
+	fmt.Println("/config reload - Relê o arquivo de configuração de projeto (.chatcli.yaml ou .chatcli.toml)")
 	fmt.Printf("/reload para recarregar as variáveis e reconfigurar o chatcli.\n\n")
 }
 
@@ -468,22 +874,23 @@ func (cli *ChatCLI) getConversationHistory() string {
 // processSpecialCommands processa comandos especiais como @history, @git, @env, @file
 func (cli *ChatCLI) processSpecialCommands(userInput string) (string, string) {
 	var additionalContext string
-
-	// Processar comandos especiais
-	userInput, context := cli.processHistoryCommand(userInput)
-	additionalContext += context
-
-	userInput, context = cli.processGitCommand(userInput)
-	additionalContext += context
-
-	userInput, context = cli.processEnvCommand(userInput)
-	additionalContext += context
-
-	userInput, context = cli.processFileCommand(userInput)
-	additionalContext += context
-
-	//userInput, context = cli.processCommandCommand(userInput)
-	//additionalContext += context
+	cli.contextSources = nil
+	cli.attachedFiles = nil
+
+	// Expandir os comandos @ em ordem fixa, removendo blocos repetidos e aplicando o orçamento conjunto
+	userInput, blocks := cli.expandContextCommands(userInput)
+	composition := composeContext(blocks, contextBudget())
+	cli.reportComposition(composition)
+	additionalContext += composition.text()
+	cli.promptFileUsage = composition.fileUsage()
+
+	// Apenas os blocos incluídos contam como anexados e como fontes de /cite
+	for _, block := range composition.included {
+		for _, path := range block.attached {
+			cli.markAttached(path)
+		}
+	}
+	cli.contextSources = composition.sentSources(cli.contextSources)
 
 	// Processar '>' como um operador para adicionar contexto
 	if idx := strings.Index(userInput, ">"); idx != -1 {
@@ -491,6 +898,11 @@ func (cli *ChatCLI) processSpecialCommands(userInput string) (string, string) {
 		userInput = userInput[:idx]
 	}
 
+	// No modo /cite, pedir ao modelo que referencie as fontes adicionadas
+	if cli.citeMode && len(cli.contextSources) > 0 {
+		additionalContext += citationInstruction(cli.contextSources)
+	}
+
 	// Remover espaços extras
 	userInput = strings.TrimSpace(userInput)
 
@@ -537,6 +949,7 @@ func (cli *ChatCLI) processHistoryCommand(userInput string) (string, string) {
 // processGitCommand adiciona informações do Git ao contexto
 func (cli *ChatCLI) processGitCommand(userInput string) (string, string) {
 	var additionalContext string
+	userInput, additionalContext = cli.processGitBlameCommand(userInput)
 	if strings.Contains(strings.ToLower(userInput), "@git") {
 		gitData, err := utils.GetGitInfo()
 		if err != nil {
@@ -560,62 +973,224 @@ func (cli *ChatCLI) processEnvCommand(userInput string) (string, string) {
 	return userInput, additionalContext
 }
 
-// processFileCommand adiciona o conteúdo de um arquivo ao contexto
-func (cli *ChatCLI) processFileCommand(userInput string) (string, string) {
-	var additionalContext string
+// fileContextBlocks processa os comandos @file, retornando um bloco de contexto por arquivo ou diretório
+func (cli *ChatCLI) fileContextBlocks(userInput string) (string, []contextBlock) {
+	var blocks []contextBlock
 	if strings.Contains(strings.ToLower(userInput), "@file") {
-		// Extrair todos os caminhos de arquivos
-		filePaths, err := extractAllFilePaths(userInput)
+		// Extrair todos os caminhos de arquivos (e intervalos de linhas, se houver)
+		fileRequests, err := extractFileRequests(userInput)
 		if err != nil {
 			cli.logger.Error("Erro ao processar os comandos @file", zap.Error(err))
+			fmt.Println("Erro no comando @file:", err)
 		} else {
-			for _, filePath := range filePaths {
+			for _, req := range fileRequests {
+				source := "@file " + req.path
+				if req.tree {
+					text, files := cli.treeContext(req)
+					blocks = append(blocks, contextBlock{source: source, text: text, files: files})
+					continue
+				}
+				attached := []string{req.path}
+				if req.chunked {
+					blocks = append(blocks, contextBlock{source: source, text: cli.chunkedFileContext(req.path), files: 1, attached: attached})
+					continue
+				}
+				if len(req.ranges) == 0 {
+					blocks = append(blocks, contextBlock{source: source, text: cli.fileHeadContext(req.path), files: 1, attached: attached})
+					continue
+				}
 				// Ler o conteúdo do arquivo
-				fileContent, err := utils.ReadFileContent(filePath, 5000000)
+				fileContent, err := utils.ReadFileContent(req.path, maxFileContextSize)
 				if err != nil {
-					cli.logger.Error(fmt.Sprintf("Erro ao ler o arquivo '%s'", filePath), zap.Error(err))
-				} else {
-					// Detectar o tipo de arquivo com base na extensão
-					fileType := detectFileType(filePath)
-					// Adicionar o conteúdo ao contexto adicional com formatação de código se aplicável
-					if isCodeFile(fileType) {
-						additionalContext += fmt.Sprintf("\nConteúdo do Arquivo (%s - %s):\n```%s\n%s\n```\n", filePath, fileType, fileType, fileContent)
-					} else {
-						additionalContext += fmt.Sprintf("\nConteúdo do Arquivo (%s - %s):\n%s\n", filePath, fileType, fileContent)
-					}
+					cli.logger.Error(fmt.Sprintf("Erro ao ler o arquivo '%s'", req.path), zap.Error(err))
+					continue
+				}
+				slice, err := extractLineRanges(fileContent, req.ranges)
+				if err != nil {
+					cli.logger.Warn(fmt.Sprintf("Intervalo de linhas inválido para '%s'", req.path), zap.Error(err))
+					fmt.Printf("Arquivo '%s': %v\n", req.path, err)
+					continue
 				}
+				blocks = append(blocks, contextBlock{
+					source:   source,
+					text:     cli.tagSource(req.path) + formatFileSliceContext(req.path, req.ranges, slice),
+					files:    1,
+					attached: attached,
+				})
 			}
 		}
 		// Remover todos os comandos @file da entrada do usuário
 		userInput = removeAllFileCommands(userInput)
 	}
-	return userInput, additionalContext
+	return userInput, blocks
+}
+
+// treeContext monta o contexto de @file --tree: a estrutura do diretório seguida do conteúdo
+// dos arquivos que correspondem a --include, e a quantidade de arquivos incluídos
+func (cli *ChatCLI) treeContext(req fileRequest) (string, int) {
+	root, err := utils.ExpandPath(req.path)
+	if err != nil {
+		root = req.path
+	}
+	tree, err := buildFileTree(root, treeOptions{depth: req.depth, include: req.include})
+	if err != nil {
+		cli.logger.Error(fmt.Sprintf("Erro ao listar o diretório '%s'", req.path), zap.Error(err))
+		fmt.Printf("Erro no comando @file --tree: %v\n", err)
+		return "", 0
+	}
+
+	treeText := cli.tagSource(req.path+"/") + formatTreeContext(req.path, req.depth, tree.listing)
+	included := tree.included
+	if len(included) > maxTreeIncludeFiles {
+		fmt.Printf("Muitos arquivos correspondem a --include; apenas os primeiros %d serão incluídos.\n", maxTreeIncludeFiles)
+		included = included[:maxTreeIncludeFiles]
+	}
+	files := 0
+	for _, rel := range included {
+		path := filepath.Join(root, rel)
+		content, err := utils.ReadFileContent(path, maxFileContextSize)
+		if err != nil {
+			cli.logger.Error(fmt.Sprintf("Erro ao ler o arquivo '%s'", path), zap.Error(err))
+			continue
+		}
+		treeText += cli.tagSource(filepath.Join(req.path, rel)) + formatFileContext(filepath.Join(req.path, rel), content)
+		files++
+	}
+	return treeText, files
+}
+
+// formatFileContext formata o conteúdo de um arquivo para o contexto, detectando o tipo pela extensão
+// e usando blocos de código quando aplicável
+func formatFileContext(filePath, fileContent string) string {
+	fileType := detectFileType(filePath)
+	if isCodeFile(fileType) {
+		return fmt.Sprintf("\nConteúdo do Arquivo (%s - %s):\n```%s\n%s\n```\n", filePath, fileType, fileType, fileContent)
+	}
+	return fmt.Sprintf("\nConteúdo do Arquivo (%s - %s):\n%s\n", filePath, fileType, fileContent)
 }
 
-// Função auxiliar para extrair todos os caminhos de arquivos após @file
-func extractAllFilePaths(input string) ([]string, error) {
-	var filePaths []string
+// fileRequest representa um arquivo solicitado via @file e, opcionalmente, os intervalos de linhas desejados.
+// Com tree, o caminho é um diretório cuja estrutura é listada (seguida dos arquivos de include).
+type fileRequest struct {
+	path    string
+	ranges  []lineRange
+	tree    bool
+	depth   int
+	include []string
+	chunked bool
+}
+
+// Função auxiliar para extrair todos os caminhos de arquivos após @file, com as flags opcionais
+// --lines, --tree, --depth, --include e --mode
+func extractFileRequests(input string) ([]fileRequest, error) {
+	var requests []fileRequest
 	tokens, err := parseFields(input)
 	if err != nil {
 		return nil, err
 	}
 
-	skipNext := false
-	for i, token := range tokens {
-		if skipNext {
-			skipNext = false
+	for i := 0; i < len(tokens); i++ {
+		if tokens[i] != "@file" {
 			continue
 		}
-		if token == "@file" {
-			if i+1 < len(tokens) {
-				filePaths = append(filePaths, tokens[i+1])
-				skipNext = true
-			} else {
-				return nil, fmt.Errorf("comando @file sem caminho de arquivo")
+		req, last, err := parseFileCommand(tokens, i)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, req)
+		i = last
+	}
+	return requests, nil
+}
+
+// parseFileCommand interpreta o comando @file que começa em tokens[start]. As flags podem vir antes
+// ou depois do caminho. Retorna a requisição e o índice do último token consumido.
+func parseFileCommand(tokens []string, start int) (fileRequest, int, error) {
+	var req fileRequest
+	var rangesSpec string
+	i := start
+
+	// consumeFlag trata a flag em tokens[i], retornando false se o token não for uma flag de @file
+	consumeFlag := func() (bool, error) {
+		switch tokens[i] {
+		case "--tree":
+			req.tree = true
+		case "--lines", "--depth", "--include", "--mode":
+			if i+1 >= len(tokens) {
+				return true, fmt.Errorf("flag %s sem valor", tokens[i])
+			}
+			flag := tokens[i]
+			i++
+			switch flag {
+			case "--lines":
+				rangesSpec = tokens[i]
+			case "--depth":
+				depth, err := strconv.Atoi(tokens[i])
+				if err != nil || depth < 1 {
+					return true, fmt.Errorf("valor inválido para --depth: '%s'", tokens[i])
+				}
+				req.depth = depth
+			case "--include":
+				for _, pattern := range strings.Split(tokens[i], ",") {
+					if pattern = strings.TrimSpace(pattern); pattern != "" {
+						req.include = append(req.include, pattern)
+					}
+				}
+			case "--mode":
+				if tokens[i] != "full" && tokens[i] != "chunked" {
+					return true, fmt.Errorf("valor inválido para --mode: '%s' (use full ou chunked)", tokens[i])
+				}
+				req.chunked = tokens[i] == "chunked"
 			}
+		default:
+			return false, nil
+		}
+		return true, nil
+	}
+
+	for i+1 < len(tokens) && req.path == "" {
+		i++
+		isFlag, err := consumeFlag()
+		if err != nil {
+			return req, i, err
+		}
+		if !isFlag {
+			req.path = tokens[i]
+		}
+	}
+	if req.path == "" {
+		return req, i, fmt.Errorf("comando @file sem caminho de arquivo")
+	}
+	// As flags também podem vir depois do caminho
+	for i+1 < len(tokens) {
+		i++
+		isFlag, err := consumeFlag()
+		if err != nil {
+			return req, i, err
+		}
+		if !isFlag {
+			i--
+			break
+		}
+	}
+
+	if !req.tree && (req.depth > 0 || len(req.include) > 0) {
+		return req, i, fmt.Errorf("as flags --depth e --include só podem ser usadas com --tree")
+	}
+	if req.tree && rangesSpec != "" {
+		return req, i, fmt.Errorf("a flag --lines não pode ser usada com --tree")
+	}
+	if req.chunked && (req.tree || rangesSpec != "") {
+		return req, i, fmt.Errorf("--mode chunked não pode ser usado com --tree ou --lines")
+	}
+	if rangesSpec != "" {
+		ranges, err := parseLineRanges(rangesSpec)
+		if err != nil {
+			return req, i, err
 		}
+		req.ranges = ranges
 	}
-	return filePaths, nil
+	return req, i, nil
 }
 
 // Função auxiliar para analisar campos, considerando aspas
@@ -650,21 +1225,21 @@ func parseFields(input string) ([]string, error) {
 	return fields, nil
 }
 
-// removeAllFileCommands remove todos os comandos @file da entrada do usuário
+// removeAllFileCommands remove todos os comandos @file (e suas flags) da entrada do usuário
 func removeAllFileCommands(input string) string {
 	tokens, _ := parseFields(input) // Ignoramos o erro aqui porque já foi tratado
 	var filtered []string
-	skipNext := false
 	for i := 0; i < len(tokens); i++ {
-		if skipNext {
-			skipNext = false
+		if tokens[i] != "@file" {
+			filtered = append(filtered, tokens[i])
 			continue
 		}
-		if tokens[i] == "@file" {
-			skipNext = true
-			continue
+		// Pular flags e o caminho do arquivo
+		_, last, err := parseFileCommand(tokens, i)
+		if err != nil && i+1 < len(tokens) {
+			last = i + 1
 		}
-		filtered = append(filtered, tokens[i])
+		i = last
 	}
 	return strings.Join(filtered, " ")
 }
@@ -712,23 +1287,13 @@ func filterEmptyLines(lines []string) []string {
 func (cli *ChatCLI) executeDirectCommand(command string) {
 	fmt.Println("Executando comando:", command)
 
-	// Verificar se o comando é interativo
-	isInteractive := false
-	if strings.HasPrefix(command, "-i ") || strings.HasPrefix(command, "--interactive ") {
-		isInteractive = true
-		// Remover a flag do comando
-		command = strings.TrimPrefix(command, "-i ")
-		command = strings.TrimPrefix(command, "--interactive ")
+	// Interpretar as flags iniciais (-i, --ai, --timeout, --dir, --shell, --skip-preflight e --quiet)
+	opts, command, err := parseCommandOptions(command)
+	if err != nil {
+		fmt.Println("Erro:", err)
+		return
 	}
-
-	// Verificar se o comando contém a flag --send-ai e pipe |
-	sendToAI := false
 	var aiContext string
-	if strings.Contains(command, "-ai") {
-		sendToAI = true
-		// Remover a flag do comando
-		command = strings.Replace(command, "-ai", "", 1)
-	}
 
 	// Verificar se há um maior > no comando
 	if strings.Contains(command, ">") {
@@ -737,114 +1302,203 @@ func (cli *ChatCLI) executeDirectCommand(command string) {
 		aiContext = strings.TrimSpace(parts[1])
 	}
 
-	// Obter o shell do usuário
-	userShell := utils.GetUserShell()
-	shellPath, err := exec.LookPath(userShell)
-	if err != nil {
-		cli.logger.Error("Erro ao localizar o shell", zap.Error(err))
-		fmt.Println("Erro ao localizar o shell:", err)
+	// Verificar se as ferramentas usadas pelo comando estão instaladas antes de executá-lo. Os cmdlets
+	// do PowerShell não são executáveis no PATH, por isso a verificação não se aplica a --shell pwsh.
+	if !opts.skipPreflight && opts.shell != "pwsh" && !preflight([]string{command}) {
 		return
 	}
 
-	// Obter o caminho do arquivo de configuração do shell
-	shellConfigPath := utils.GetShellConfigFilePath(userShell)
-	if shellConfigPath == "" {
-		fmt.Println("Não foi possível determinar o arquivo de configuração para o shell:", userShell)
-		return
-	}
+	// Registrar o comando para um eventual /replay
+	cli.executedCommands = append(cli.executedCommands, recordedCommand{command: command, opts: opts})
 
-	// Construir o comando para carregar o arquivo de configuração e executar o comando do usuário
-	shellCommand := fmt.Sprintf("source %s && %s", shellConfigPath, command)
+	cli.runDirectCommand(command, opts, aiContext)
 
-	cmd := exec.Command(shellPath, "-c", shellCommand)
+	// Adicionar o comando ao histórico do liner para persistir em .chatcli_history
+	//cli.line.AppendHistory(fmt.Sprintf("@command %s", command))
+}
+
+// runDirectCommand executa o comando no shell do usuário com as opções informadas, registrando a
+// saída no histórico. Retorna erro se o comando falhar ou exceder o tempo limite.
+func (cli *ChatCLI) runDirectCommand(command string, opts commandOptions, aiContext string) error {
+	// Montar a chamada do shell escolhido com --shell ou do shell do usuário
+	shellPath, shellArgs, err := shellInvocation(command, opts.shell)
+	if err != nil {
+		cli.logger.Error("Erro ao preparar o shell", zap.Error(err))
+		fmt.Println("Erro:", err)
+		return err
+	}
+
+	// Fora do modo interativo, Ctrl+C interrompe o comando sem encerrar o ChatCLI; no interativo, o
+	// próprio comando recebe o Ctrl+C, e o ChatCLI apenas o ignora enquanto o comando roda
+	ctx := context.Background()
+	if opts.interactive {
+		defer interrupts.register(func() {})()
+	} else {
+		var stop context.CancelFunc
+		ctx, stop = interruptible(ctx)
+		defer stop()
+	}
+	if opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.timeout)
+		defer cancel()
+	}
 
-	if isInteractive {
-		// Conectar os streams de entrada, saída e erro do comando ao terminal
-		cmd.Stdin = os.Stdin
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
+	cmd := exec.CommandContext(ctx, shellPath, shellArgs...)
+	cmd.Dir = opts.dir
+	// Não esperar indefinidamente por processos filhos que mantenham a saída aberta após o timeout
+	cmd.WaitDelay = 2 * time.Second
 
+	start := time.Now()
+	if opts.interactive {
 		// Fechar o liner para liberar o terminal antes de executar o comando interativo
 		cli.line.Close()
 
-		// Executar o comando
-		err = cmd.Run()
+		// Executar o comando conectado ao terminal, restaurando o estado ao final
+		err = cli.runInteractiveCommand(cmd, newStdinTerminalState())
 
 		// Reabrir o liner após a execução do comando
-		cli.line = liner.NewLiner()
-		cli.line.SetCtrlCAborts(true)
-		cli.loadHistory()
-		cli.line.SetCompleter(cli.completer) // Reconfigurar o autocompletar
+		cli.reopenLiner()
 
-		if err != nil {
+		timedOut := ctx.Err() == context.DeadlineExceeded
+		if timedOut {
+			fmt.Printf("O comando excedeu o tempo limite de %s e foi interrompido.\n", opts.timeout)
+		} else if err != nil {
 			fmt.Println("Erro ao executar comando:", err)
 		}
 
 		// Informar que a saída não foi capturada
 		fmt.Println("A saída do comando não pôde ser capturada para o histórico.")
+		if opts.as != "" {
+			fmt.Printf("A variável %s não foi definida.\n", opts.as)
+		}
 
-		// Armazenar apenas o comando no histórico
+		// Armazenar apenas o comando e o resultado no histórico
+		result := newCommandResult(err, time.Since(start), timedOut, false, opts.timeout)
 		cli.history = append(cli.history, models.Message{
 			Role:    "system",
-			Content: fmt.Sprintf("Comando executado: %s", command),
+			Content: result.feedback(command, "", false),
 		})
-		cli.lastCommandOutput = ""
-	} else {
-		// Capturar a saída do comando
-		output, err := cmd.CombinedOutput()
+		cli.lastCommandResult = &executedCommand{command: command, result: result}
+		if timedOut {
+			return fmt.Errorf("tempo limite de %s excedido", opts.timeout)
+		}
+		return err
+	}
 
-		// Exibir a saída
-		fmt.Println("Saída do comando:\n\n", string(output))
+	// Exibir a saída enquanto o comando roda (com --quiet, apenas ao final) e capturá-la para o histórico e a IA
+	var live io.Writer
+	if !opts.quiet {
+		live = os.Stdout
+		fmt.Println("Saída do comando:")
+	}
+	stream := newLineStreamer(live)
+	cmd.Stdout, cmd.Stderr = stream, stream
+	err = cmd.Run()
+	stream.Flush()
+	output := stream.Bytes()
+	if opts.quiet && len(output) > 0 {
+		fmt.Println("Saída do comando:")
+		fmt.Println(strings.TrimRight(string(output), "\n"))
+	}
+	timedOut := ctx.Err() == context.DeadlineExceeded
+	canceled := ctx.Err() == context.Canceled
+	result := newCommandResult(err, time.Since(start), timedOut, canceled, opts.timeout)
 
-		if err != nil {
-			fmt.Println("Erro ao executar comando:", err)
-		}
+	switch {
+	case timedOut:
+		fmt.Printf("O comando excedeu o tempo limite de %s e foi interrompido. A saída pode estar incompleta.\n", opts.timeout)
+	case canceled:
+		fmt.Print(terminalResetSequence)
+		fmt.Println("\nComando cancelado. A saída pode estar incompleta.")
+	case err != nil:
+		fmt.Println("Erro ao executar comando:", err)
+	}
 
-		// Armazenar a saída no histórico
-		cli.history = append(cli.history, models.Message{
-			Role:    "system",
-			Content: fmt.Sprintf("Comando: %s\nSaída:\n%s", command, string(output)),
-		})
-		cli.lastCommandOutput = string(output)
+	if opts.outputFile != "" {
+		cli.saveCommandOutput(opts.outputFile, string(output))
+	}
+	// A saída exibida é completa; a que vai para o histórico e para a IA respeita o limite configurado
+	modelOutput := cli.scrubSecrets(cli.outputForModel(string(output), opts), "a saída do comando")
 
-		// se a flag --ai foi passada enviar o output para a IA
-		if sendToAI {
-			cli.sendOutputToAI(cli.lastCommandOutput, aiContext)
-		}
+	// Armazenar a saída no histórico com o código de saída, a duração e o status, para que a IA saiba
+	// se o comando falhou ou foi interrompido
+	feedback := result.feedback(command, modelOutput, true)
+	cli.history = append(cli.history, models.Message{
+		Role:    "system",
+		Content: feedback,
+	})
+	cli.lastCommandResult = &executedCommand{command: command, result: result, output: modelOutput, captured: true}
+	if opts.as != "" {
+		cli.setVar(opts.as, command, modelOutput)
 	}
 
-	// Adicionar o comando ao histórico do liner para persistir em .chatcli_history
-	//cli.line.AppendHistory(fmt.Sprintf("@command %s", command))
+	// se a flag --ai foi passada enviar o output para a IA
+	if opts.sendToAI && !canceled {
+		cli.sendOutputToAI(feedback, aiContext)
+	}
+
+	if timedOut {
+		return fmt.Errorf("tempo limite de %s excedido", opts.timeout)
+	}
+	return err
 }
 
-// sendOutputToAI envia o output do comando para a IA com o contexto adicional
+// sendOutputToAI envia o resultado do comando, já no formato de commandResult.feedback, para a IA
+// com o contexto adicional
 func (cli *ChatCLI) sendOutputToAI(output string, aiContext string) {
 	fmt.Println("Enviando sáida do comando para a IA...")
 
-	// Adicionar o output do comando ao histórico como mensagem do usuário
+	// Adicionar o output do comando ao histórico como mensagem do usuário. O contexto é texto do
+	// usuário e fica fora do bloco do resultado.
+	prompt := output
+	if aiContext != "" {
+		prompt += "\n\nContexto do usuário: " + aiContext
+	}
 	cli.history = append(cli.history, models.Message{
 		Role:    "user",
-		Content: fmt.Sprintf("Saída do comando:\n%s\n\nContexto: %s", output, aiContext),
+		Content: prompt,
 	})
+	// Ctrl+C cancela a requisição ou a exibição da resposta sem encerrar o programa
+	opCtx, stop := interruptible(context.Background())
+	defer stop()
+
 	// Exibir mensagem "Pensando..." com animação
 	cli.animation.ShowThinkingAnimation(cli.client.GetModelName())
 
 	//Criar um contexto com timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	ctx, cancel := context.WithTimeout(opCtx, 2*time.Minute)
 	defer cancel()
 
 	//Enviar o output e o contexto para a IA
-	aiResponse, err := cli.client.SendPrompt(ctx, fmt.Sprintf("Saída do comando:\n%s\n\nContexto: %s", output, aiContext), cli.history)
+	if cli.responseFormat == client.ResponseFormatJSON {
+		prompt += jsonInstruction
+	}
+	history := cli.historyForRequest()
+	cli.recordRequest(history, prompt)
+	start := time.Now()
+	aiResponse, err := cli.client.SendPrompt(ctx, prompt, history)
+	cli.recordLatency(time.Since(start), err)
 
 	//parar a animação
 	cli.animation.StopThinkingAnimation()
 
+	if err != nil && opCtx.Err() != nil {
+		cli.cancelOperation(os.Stdout, "Requisição cancelada.")
+		return
+	}
 	if err != nil {
 		cli.logger.Error("Erro do LLM", zap.Error(err))
-		fmt.Println("Ocorreu um erro ao processar a requisição.")
+		fmt.Println(llmErrorMessage(err))
 		return
 	}
 
+	if cli.responseFormat == client.ResponseFormatJSON {
+		aiResponse = cli.enforceJSON(aiResponse)
+	} else {
+		aiResponse = cli.applyFormatters(aiResponse)
+	}
+
 	// Adicionar a resposta da IA ao histórico
 	cli.history = append(cli.history, models.Message{
 		Role:    "assistant",
@@ -852,49 +1506,33 @@ func (cli *ChatCLI) sendOutputToAI(output string, aiContext string) {
 	})
 
 	// Renderizar a resposta da IA
-	renderResponse := cli.renderMarkdown(aiResponse)
+	renderResponse := cli.renderResponse(aiResponse)
 
 	// Exibir a resposta da IA com efeito de digitação
-	cli.typewriterEffect(fmt.Sprintf("\n%s:\n%s\n", cli.client.GetModelName(), renderResponse), 2*time.Millisecond)
+	cli.displayResponse(opCtx, renderResponse)
 }
 
-// loadHistory carrega o histórico do arquivo
-func (cli *ChatCLI) loadHistory() {
-	historyFile := ".chatcli_history"
-	f, err := os.Open(historyFile)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return // Nenhum histórico para carregar
+// llmErrorMessage traduz os erros tipados dos provedores em uma mensagem para o usuário
+func llmErrorMessage(err error) string {
+	var providerErr *client.ProviderError
+	switch {
+	case errors.Is(err, client.ErrRateLimited):
+		if errors.As(err, &providerErr) && providerErr.RetryAfter > 0 {
+			return fmt.Sprintf("Limite de requisições excedido. Tente novamente em %s.", providerErr.RetryAfter.Round(time.Second))
 		}
-		cli.logger.Warn("Não foi possível carregar o histórico:", zap.Error(err))
-		return
-	}
-	defer f.Close()
-
-	scanner := bufio.NewScanner(f)
-	for scanner.Scan() {
-		line := scanner.Text()
-		cli.commandHistory = append(cli.commandHistory, line)
-		cli.line.AppendHistory(line) // Adicionar ao liner para navegação
-	}
-
-	if err := scanner.Err(); err != nil {
-		cli.logger.Warn("Erro ao ler o histórico:", zap.Error(err))
-	}
-}
-
-// saveHistory salva o histórico no arquivo
-func (cli *ChatCLI) saveHistory() {
-	historyFile := ".chatcli_history"
-	f, err := os.OpenFile(historyFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
-	if err != nil {
-		cli.logger.Warn("Não foi possível salvar o histórico:", zap.Error(err))
-		return
-	}
-	defer f.Close()
-
-	for _, cmd := range cli.commandHistory {
-		fmt.Fprintln(f, cmd)
+		return "Limite de requisições excedido. Por favor, aguarde antes de tentar novamente."
+	case errors.Is(err, client.ErrAuth):
+		return "Falha de autenticação com o provedor. Verifique suas credenciais e use /reload após corrigi-las."
+	case errors.Is(err, client.ErrContextTooLong):
+		return "A conversa excede o limite de contexto do modelo. Use /summarize ou /undo para reduzir o histórico."
+	case errors.Is(err, client.ErrTimeout):
+		return "O provedor demorou demais para responder. Tente novamente."
+	case errors.Is(err, client.ErrContentFiltered):
+		return contentFilterMessage(err)
+	case utils.IsTLSVerificationError(err):
+		return "Falha na verificação do certificado TLS do provedor. Se você está atrás de um proxy corporativo, defina CHATCLI_CA_BUNDLE com o caminho do certificado da sua CA e use /reload."
+	default:
+		return "Ocorreu um erro ao processar a requisição."
 	}
 }
 
@@ -903,8 +1541,8 @@ func (cli *ChatCLI) completer(line string) []string {
 	var completions []string
 	trimmedLine := strings.TrimSpace(line)
 
-	commands := []string{"/exit", "/quit", "/switch", "/help", "/reload"}
-	specialCommands := []string{"@history", "@git", "@env", "@file", "@command"}
+	commands := []string{"/exit", "/quit", "/switch", "/help", "/reload", "/config", "/undo", "/redo", "/summarize", "/remember", "/forget", "/memory", "/replay", "/providers", "/save", "/cite", "/page", "/vars", "/history", "/status", "/template", "/bench", "/keys", "/defaultctx", "/latency", "/system", "/edit", "/offline", "/trace", "/format", "/import-openai", "/alias", "/explain", "/doctor"}
+	specialCommands := []string{"@history", "@git", "@github", "@env", "@file", "@image", "@command", "@var", "@clipboard", "@docker-logs", "@docker-inspect", "@k8s", "@provider"}
 
 	if strings.HasPrefix(trimmedLine, "/") {
 		for _, cmd := range commands {
@@ -1114,10 +1752,19 @@ func (cli *ChatCLI) renderMarkdown(input string) string {
 	//if err != nil || width <= 0 {
 	//	width = 80 // valor padrão
 	//}
-	renderer, _ := glamour.NewTermRenderer(
-		glamour.WithAutoStyle(),
+	style := markdownStyle()
+	renderer, err := glamour.NewTermRenderer(
+		markdownStyleOption(style),
 		glamour.WithWordWrap(0),
 	)
+	if err != nil {
+		// Um tema inválido não deve impedir a exibição da resposta
+		cli.logger.Warn("Tema inválido, usando o estilo automático", zap.String("tema", style), zap.Error(err))
+		renderer, err = glamour.NewTermRenderer(glamour.WithAutoStyle(), glamour.WithWordWrap(0))
+		if err != nil {
+			return input
+		}
+	}
 	out, err := renderer.Render(input)
 	if err != nil {
 		return input
@@ -1125,15 +1772,21 @@ func (cli *ChatCLI) renderMarkdown(input string) string {
 	return out
 }
 
-// typewriterEffect exibe o texto com efeito de máquina de escrever
-func (cli *ChatCLI) typewriterEffect(text string, delay time.Duration) {
+// typewriterEffect exibe o texto com efeito de máquina de escrever. Retorna false se o contexto for
+// cancelado antes do fim do texto.
+func (cli *ChatCLI) typewriterEffect(ctx context.Context, text string, delay time.Duration) bool {
+	return writeTypewriter(ctx, os.Stdout, text, delay)
+}
+
+// writeTypewriter escreve o texto caractere a caractere em out, sem delay dentro das sequências de escape
+func writeTypewriter(ctx context.Context, out io.Writer, text string, delay time.Duration) bool {
 	reader := strings.NewReader(text)
 	inEscapeSequence := false
 
 	for {
 		char, _, err := reader.ReadRune()
 		if err != nil {
-			break // Fim do texto
+			return true // Fim do texto
 		}
 
 		// Verifica se é o início de uma sequência de escape
@@ -1141,7 +1794,7 @@ func (cli *ChatCLI) typewriterEffect(text string, delay time.Duration) {
 			inEscapeSequence = true
 		}
 
-		fmt.Printf("%c", char)
+		fmt.Fprintf(out, "%c", char)
 
 		// Verifica o final da sequência de escape
 		if inEscapeSequence {
@@ -1151,6 +1804,10 @@ func (cli *ChatCLI) typewriterEffect(text string, delay time.Duration) {
 			continue // Não aplica delay dentro da sequência de escape
 		}
 
-		time.Sleep(delay) // Ajuste o delay conforme desejado
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(delay): // Ajuste o delay conforme desejado
+		}
 	}
 }
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/diillson/chatcli/utils"
+	"go.uber.org/zap"
+)
+
+// clipboardPattern encontra '@clipboard [--lang <linguagem>]' na entrada do usuário
+var clipboardPattern = regexp.MustCompile(`(?i)@clipboard(?:\s+--lang\s+(\S+))?`)
+
+// readClipboard lê a área de transferência; é uma variável para ser substituída nos testes
+var readClipboard = utils.ReadClipboard
+
+// processClipboardCommand adiciona ao contexto o texto da área de transferência, cercado como bloco de
+// código na linguagem de --lang, quando informada. O texto segue o limite de saída de @command.
+func (cli *ChatCLI) processClipboardCommand(userInput string) (string, string) {
+	match := clipboardPattern.FindStringSubmatch(userInput)
+	if match == nil {
+		return userInput, ""
+	}
+	userInput = clipboardPattern.ReplaceAllString(userInput, " ")
+	userInput = strings.TrimSpace(regexp.MustCompile(`\s+`).ReplaceAllString(userInput, " "))
+
+	content, err := readClipboard()
+	switch {
+	case errors.Is(err, utils.ErrNoClipboardTool):
+		fmt.Println("Não foi possível ler a área de transferência: instale pbpaste, wl-paste, xclip ou xsel (no Windows, PowerShell).")
+		return userInput, ""
+	case err != nil:
+		cli.logger.Error("Erro ao ler a área de transferência", zap.Error(err))
+		fmt.Println("Erro no comando @clipboard:", err)
+		return userInput, ""
+	}
+	content = strings.TrimRight(content, "\n")
+	if strings.TrimSpace(content) == "" {
+		fmt.Println("A área de transferência está vazia; @clipboard ignorado.")
+		return userInput, ""
+	}
+
+	content = cli.outputForModel(content, commandOptions{})
+	return userInput, fmt.Sprintf("\nConteúdo da área de transferência:%s\n```%s\n%s\n```\n",
+		cli.tagSource("@clipboard"), match[1], content)
+}
@@ -0,0 +1,28 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/diillson/chatcli/utils"
+	"go.uber.org/zap"
+)
+
+func TestProcessClipboardCommand(t *testing.T) {
+	defer func(original func() (string, error)) { readClipboard = original }(readClipboard)
+	cli := &ChatCLI{logger: zap.NewNop()}
+
+	readClipboard = func() (string, error) { return "panic: runtime error\ngoroutine 1\n", nil }
+	input, context := cli.processClipboardCommand("por que isso quebrou? @clipboard --lang go")
+	if input != "por que isso quebrou?" {
+		t.Errorf("Entrada inesperada: %q", input)
+	}
+	if !strings.Contains(context, "```go\npanic: runtime error") {
+		t.Errorf("Contexto inesperado:\n%s", context)
+	}
+
+	readClipboard = func() (string, error) { return "", utils.ErrNoClipboardTool }
+	if input, context := cli.processClipboardCommand("@clipboard explique"); input != "explique" || context != "" {
+		t.Errorf("Sem ferramenta, esperado apenas remover o comando: %q %q", input, context)
+	}
+}
@@ -0,0 +1,196 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/peterh/liner"
+	"go.uber.org/zap"
+)
+
+// codeBlock é um bloco de código delimitado por ``` em uma resposta do assistente. O nome de arquivo
+// vem da info string do bloco, nos formatos ```go:main.go ou ```go main.go.
+type codeBlock struct {
+	lang    string
+	path    string
+	content string
+}
+
+// parseFenceInfo extrai a linguagem e o nome de arquivo sugerido da info string de um bloco
+func parseFenceInfo(info string) (lang, path string) {
+	info = strings.TrimSpace(info)
+	if info == "" {
+		return "", ""
+	}
+	if idx := strings.Index(info, ":"); idx > 0 && !strings.ContainsAny(info[:idx], " \t") {
+		return info[:idx], strings.TrimSpace(info[idx+1:])
+	}
+	fields := strings.Fields(info)
+	if len(fields) > 1 {
+		return fields[0], fields[1]
+	}
+	return fields[0], ""
+}
+
+// extractCodeBlocks retorna os blocos de código da resposta, na ordem em que aparecem.
+// Um bloco sem fechamento vai até o fim do texto.
+func extractCodeBlocks(text string) []codeBlock {
+	var blocks []codeBlock
+	var current *codeBlock
+	var lines []string
+
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if current == nil {
+			if strings.HasPrefix(trimmed, "```") {
+				lang, path := parseFenceInfo(strings.TrimPrefix(trimmed, "```"))
+				current = &codeBlock{lang: lang, path: path}
+				lines = nil
+			}
+			continue
+		}
+		if trimmed == "```" {
+			current.content = strings.Join(lines, "\n") + "\n"
+			blocks = append(blocks, *current)
+			current = nil
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if current != nil {
+		current.content = strings.Join(lines, "\n") + "\n"
+		blocks = append(blocks, *current)
+	}
+	return blocks
+}
+
+// lastAssistantResponse retorna a última resposta do assistente no histórico
+func (cli *ChatCLI) lastAssistantResponse() (string, bool) {
+	for i := len(cli.history) - 1; i >= 0; i-- {
+		if cli.history[i].Role == "assistant" {
+			return cli.history[i].Content, true
+		}
+	}
+	return "", false
+}
+
+// handleSaveCommand trata /save: sem argumentos lista os blocos de código da última resposta;
+// '/save N [caminho]' grava o bloco N no caminho informado ou no sugerido pela resposta
+func (cli *ChatCLI) handleSaveCommand(userInput string) {
+	args := strings.Fields(userInput)[1:]
+
+	response, ok := cli.lastAssistantResponse()
+	if !ok {
+		fmt.Println("Nenhuma resposta do assistente nesta sessão.")
+		return
+	}
+	blocks := extractCodeBlocks(response)
+	if len(blocks) == 0 {
+		fmt.Println("A última resposta não contém blocos de código.")
+		return
+	}
+
+	if len(args) == 0 {
+		fmt.Println("Blocos de código da última resposta:")
+		for i, b := range blocks {
+			fmt.Printf("  %d. %s\n", i+1, describeCodeBlock(b))
+		}
+		fmt.Println("Use '/save N [caminho]' para gravar um bloco em arquivo.")
+		return
+	}
+	if len(args) > 2 {
+		fmt.Println("Uso: /save [N] [caminho]")
+		return
+	}
+
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n < 1 || n > len(blocks) {
+		fmt.Printf("Número de bloco inválido: %s (a resposta tem %d bloco(s))\n", args[0], len(blocks))
+		return
+	}
+	block := blocks[n-1]
+	path := block.path
+	if len(args) == 2 {
+		path = args[1]
+	}
+	if path == "" {
+		fmt.Printf("O bloco %d não sugere um nome de arquivo. Use '/save %d <caminho>'.\n", n, n)
+		return
+	}
+
+	target, err := filepath.Abs(path)
+	if err != nil {
+		fmt.Println("Caminho inválido:", err)
+		return
+	}
+	_, statErr := os.Stat(target)
+	exists := statErr == nil
+	question := fmt.Sprintf("Gravar o bloco %d (%d linha(s)) em %s? (s/N): ", n, strings.Count(block.content, "\n"), target)
+	if exists {
+		question = fmt.Sprintf("O arquivo %s já existe e será salvo em %s.bak. Sobrescrever com o bloco %d? (s/N): ", target, target, n)
+	}
+	if !cli.confirm(question) {
+		fmt.Println("Gravação cancelada.")
+		return
+	}
+
+	if err := writeCodeBlock(target, block.content, exists); err != nil {
+		cli.logger.Error("Erro ao gravar o bloco de código", zap.String("path", target), zap.Error(err))
+		fmt.Println("Erro ao gravar o arquivo:", err)
+		return
+	}
+	fmt.Printf("Bloco %d gravado em %s\n", n, target)
+}
+
+// writeCodeBlock grava o conteúdo no caminho, criando os diretórios necessários e, se o arquivo
+// já existir, guardando a versão anterior em <caminho>.bak
+func writeCodeBlock(target, content string, backup bool) error {
+	mode := os.FileMode(0644)
+	if backup {
+		previous, err := os.ReadFile(target)
+		if err != nil {
+			return err
+		}
+		if info, err := os.Stat(target); err == nil {
+			mode = info.Mode().Perm()
+		}
+		if err := os.WriteFile(target+".bak", previous, mode); err != nil {
+			return fmt.Errorf("erro ao criar o backup: %w", err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(target, []byte(content), mode)
+}
+
+// describeCodeBlock descreve um bloco na listagem de /save
+func describeCodeBlock(b codeBlock) string {
+	lang := b.lang
+	if lang == "" {
+		lang = "texto"
+	}
+	desc := fmt.Sprintf("%s, %d linha(s)", lang, strings.Count(b.content, "\n"))
+	if b.path != "" {
+		desc += " -> " + b.path
+	}
+	return desc
+}
+
+// confirm pergunta ao usuário e retorna true apenas para 's' ou 'sim'
+func (cli *ChatCLI) confirm(question string) bool {
+	answer, err := cli.line.Prompt(question)
+	if err != nil {
+		if err == liner.ErrPromptAborted {
+			fmt.Println("\nEntrada abortada!")
+			return false
+		}
+		cli.logger.Error("Erro ao ler a confirmação", zap.Error(err))
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "s" || answer == "sim"
+}
@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExtractCodeBlocks(t *testing.T) {
+	response := "Veja:\n```go:cmd/main.go\npackage main\n```\ne também\n```yaml config.yaml\na: 1\n```\n```\nsem dica\n"
+
+	blocks := extractCodeBlocks(response)
+	if len(blocks) != 3 {
+		t.Fatalf("Esperado 3 blocos, obtido %d: %+v", len(blocks), blocks)
+	}
+	if blocks[0].lang != "go" || blocks[0].path != "cmd/main.go" || blocks[0].content != "package main\n" {
+		t.Errorf("Primeiro bloco inesperado: %+v", blocks[0])
+	}
+	if blocks[1].lang != "yaml" || blocks[1].path != "config.yaml" {
+		t.Errorf("Segundo bloco inesperado: %+v", blocks[1])
+	}
+	if blocks[2].lang != "" || blocks[2].path != "" || blocks[2].content != "sem dica\n\n" {
+		t.Errorf("Bloco sem fechamento inesperado: %+v", blocks[2])
+	}
+}
+
+func TestWriteCodeBlock_backup(t *testing.T) {
+	target := filepath.Join(t.TempDir(), "main.go")
+	os.WriteFile(target, []byte("antigo"), 0600)
+
+	if err := writeCodeBlock(target, "novo", true); err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if got, _ := os.ReadFile(target); string(got) != "novo" {
+		t.Errorf("Conteúdo inesperado: %q", got)
+	}
+	if got, _ := os.ReadFile(target + ".bak"); string(got) != "antigo" {
+		t.Errorf("Backup inesperado: %q", got)
+	}
+}
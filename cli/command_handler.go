@@ -22,9 +22,96 @@ func (ch *CommandHandler) HandleCommand(userInput string) bool {
 	case userInput == "/reload":
 		ch.cli.reloadConfiguration()
 		return false
+	case userInput == "/status":
+		ch.cli.handleStatusCommand()
+		return false
+	case userInput == "/providers" || strings.HasPrefix(userInput, "/providers "):
+		ch.cli.handleProvidersCommand(userInput)
+		return false
 	case strings.HasPrefix(userInput, "/switch"):
 		ch.cli.handleSwitchCommand(userInput)
 		return false
+	case userInput == "/undo" || strings.HasPrefix(userInput, "/undo "):
+		ch.cli.handleUndoCommand(userInput)
+		return false
+	case userInput == "/redo":
+		ch.cli.handleRedoCommand()
+		return false
+	case userInput == "/summarize" || strings.HasPrefix(userInput, "/summarize "):
+		ch.cli.handleSummarizeCommand(userInput)
+		return false
+	case userInput == "/remember" || strings.HasPrefix(userInput, "/remember "):
+		ch.cli.handleRememberCommand(userInput)
+		return false
+	case userInput == "/forget" || strings.HasPrefix(userInput, "/forget "):
+		ch.cli.handleForgetCommand(userInput)
+		return false
+	case userInput == "/memory" || strings.HasPrefix(userInput, "/memory "):
+		ch.cli.handleMemoryCommand(userInput)
+		return false
+	case userInput == "/replay" || strings.HasPrefix(userInput, "/replay "):
+		ch.cli.handleReplayCommand(userInput)
+		return false
+	case userInput == "/save" || strings.HasPrefix(userInput, "/save "):
+		ch.cli.handleSaveCommand(userInput)
+		return false
+	case userInput == "/cite" || strings.HasPrefix(userInput, "/cite "):
+		ch.cli.handleCiteCommand(userInput)
+		return false
+	case userInput == "/page":
+		ch.cli.handlePageCommand()
+		return false
+	case userInput == "/history" || strings.HasPrefix(userInput, "/history "):
+		ch.cli.handleHistoryCommand(userInput)
+		return false
+	case userInput == "/template" || strings.HasPrefix(userInput, "/template "):
+		ch.cli.handleTemplateCommand(userInput)
+		return false
+	case userInput == "/bench" || strings.HasPrefix(userInput, "/bench "):
+		ch.cli.handleBenchCommand(userInput)
+		return false
+	case userInput == "/keys" || strings.HasPrefix(userInput, "/keys "):
+		ch.cli.handleKeysCommand(userInput)
+		return false
+	case userInput == "/defaultctx" || strings.HasPrefix(userInput, "/defaultctx "):
+		ch.cli.handleDefaultContextCommand(userInput)
+		return false
+	case userInput == "/edit" || strings.HasPrefix(userInput, "/edit "):
+		ch.cli.handleEditCommand(userInput)
+		return false
+	case userInput == "/system" || strings.HasPrefix(userInput, "/system "):
+		ch.cli.handleSystemCommand(userInput)
+		return false
+	case userInput == "/offline" || strings.HasPrefix(userInput, "/offline "):
+		ch.cli.handleOfflineCommand(userInput)
+		return false
+	case userInput == "/alias" || strings.HasPrefix(userInput, "/alias "):
+		ch.cli.handleAliasCommand(userInput)
+		return false
+	case userInput == "/import-openai" || strings.HasPrefix(userInput, "/import-openai "):
+		ch.cli.handleImportOpenAICommand(userInput)
+		return false
+	case userInput == "/format" || strings.HasPrefix(userInput, "/format "):
+		ch.cli.handleFormatCommand(userInput)
+		return false
+	case userInput == "/explain" || strings.HasPrefix(userInput, "/explain "):
+		ch.cli.handleExplainCommand(userInput)
+		return false
+	case userInput == "/doctor":
+		ch.cli.handleDoctorCommand()
+		return false
+	case userInput == "/trace" || strings.HasPrefix(userInput, "/trace "):
+		ch.cli.handleTraceCommand(userInput)
+		return false
+	case userInput == "/latency":
+		ch.cli.handleLatencyCommand()
+		return false
+	case userInput == "/vars":
+		ch.cli.handleVarsCommand()
+		return false
+	case strings.HasPrefix(userInput, "/config"):
+		ch.cli.handleConfigCommand(userInput)
+		return false
 	case userInput == "/help":
 		ch.cli.showHelp()
 		return false
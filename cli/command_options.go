@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diillson/chatcli/utils"
+)
+
+// commandOptions reúne as flags aceitas por @command antes do comando propriamente dito
+type commandOptions struct {
+	interactive   bool
+	sendToAI      bool
+	timeout       time.Duration
+	dir           string
+	skipPreflight bool
+	maxOutput     int
+	outputFile    string
+	as            string
+	shell         string
+	// quiet exibe a saída no terminal só quando o comando termina, em vez de linha a linha
+	quiet bool
+}
+
+// parseCommandOptions interpreta as flags iniciais de @command (-i/--interactive, --ai,
+// --timeout <duração>, --dir <caminho>, --skip-preflight, --max-output <tamanho>, --output <arquivo>,
+// --as <NOME>, --shell <shell> e --quiet),
+// em qualquer ordem, e retorna o comando restante
+func parseCommandOptions(input string) (commandOptions, string, error) {
+	var opts commandOptions
+	rest := strings.TrimSpace(input)
+
+	for {
+		flag, remaining := splitFirstField(rest)
+		switch flag {
+		case "-i", "--interactive":
+			opts.interactive = true
+		case "--ai", "-ai":
+			opts.sendToAI = true
+		case "--skip-preflight":
+			opts.skipPreflight = true
+		case "--quiet", "-q":
+			opts.quiet = true
+		case "--timeout":
+			value, after := splitFirstField(remaining)
+			if value == "" {
+				return opts, "", fmt.Errorf("valor ausente para --timeout")
+			}
+			timeout, err := parseCommandTimeout(value)
+			if err != nil {
+				return opts, "", err
+			}
+			opts.timeout = timeout
+			remaining = after
+		case "--dir":
+			value, after := splitFirstField(remaining)
+			if value == "" {
+				return opts, "", fmt.Errorf("valor ausente para --dir")
+			}
+			dir, err := resolveCommandDir(value)
+			if err != nil {
+				return opts, "", err
+			}
+			opts.dir = dir
+			remaining = after
+		case "--max-output":
+			value, after := splitFirstField(remaining)
+			if value == "" {
+				return opts, "", fmt.Errorf("valor ausente para --max-output")
+			}
+			size, err := utils.ParseSize(strings.ToUpper(value))
+			if err != nil || size <= 0 {
+				return opts, "", fmt.Errorf("valor inválido para --max-output: %s (use, por exemplo, 200KB)", value)
+			}
+			opts.maxOutput = int(size)
+			remaining = after
+		case "--output":
+			value, after := splitFirstField(remaining)
+			if value == "" {
+				return opts, "", fmt.Errorf("valor ausente para --output")
+			}
+			path, err := utils.ExpandPath(value)
+			if err != nil {
+				return opts, "", err
+			}
+			opts.outputFile = path
+			remaining = after
+		case "--as":
+			value, after := splitFirstField(remaining)
+			if value == "" {
+				return opts, "", fmt.Errorf("valor ausente para --as")
+			}
+			if !varNamePattern.MatchString(value) {
+				return opts, "", fmt.Errorf("nome inválido para --as: %s (use letras, números e _)", value)
+			}
+			opts.as = value
+			remaining = after
+		case "--shell":
+			value, after := splitFirstField(remaining)
+			if value == "" {
+				return opts, "", fmt.Errorf("valor ausente para --shell")
+			}
+			shell, err := parseCommandShell(value)
+			if err != nil {
+				return opts, "", err
+			}
+			opts.shell = shell
+			remaining = after
+		default:
+			return opts, rest, nil
+		}
+		rest = remaining
+	}
+}
+
+// splitFirstField separa a primeira palavra do restante do texto
+func splitFirstField(s string) (string, string) {
+	s = strings.TrimSpace(s)
+	idx := strings.IndexAny(s, " \t")
+	if idx == -1 {
+		return s, ""
+	}
+	return s[:idx], strings.TrimSpace(s[idx+1:])
+}
+
+// parseCommandTimeout aceita durações do Go ("30s", "2m") ou um número inteiro de segundos
+func parseCommandTimeout(value string) (time.Duration, error) {
+	if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		return d, nil
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second, nil
+	}
+	return 0, fmt.Errorf("valor inválido para --timeout: %s (use, por exemplo, 30s ou 2m)", value)
+}
+
+// resolveCommandDir expande ~ e verifica se o diretório informado em --dir existe
+func resolveCommandDir(value string) (string, error) {
+	dir, err := utils.ExpandPath(value)
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return "", fmt.Errorf("diretório inválido para --dir: %w", err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%s não é um diretório", value)
+	}
+	return dir, nil
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCommandOptions(t *testing.T) {
+	dir := t.TempDir()
+
+	opts, command, err := parseCommandOptions("--timeout 30s --dir " + dir + " --ai make build > explique os erros")
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if opts.timeout != 30*time.Second || opts.dir != dir || !opts.sendToAI || opts.interactive {
+		t.Errorf("Opções inesperadas: %+v", opts)
+	}
+	if command != "make build > explique os erros" {
+		t.Errorf("Comando inesperado: %q", command)
+	}
+
+	opts, command, err = parseCommandOptions("-i --timeout 5 top")
+	if err != nil || !opts.interactive || opts.timeout != 5*time.Second || command != "top" {
+		t.Errorf("Esperado modo interativo com timeout de 5s, obtido %+v, %q, %v", opts, command, err)
+	}
+
+	opts, command, err = parseCommandOptions("--quiet --ai make deploy")
+	if err != nil || !opts.quiet || !opts.sendToAI || command != "make deploy" {
+		t.Errorf("Esperado --quiet com --ai, obtido %+v, %q, %v", opts, command, err)
+	}
+
+	// Flags após o comando pertencem ao próprio comando
+	_, command, _ = parseCommandOptions("grep -ai --timeout arquivo")
+	if command != "grep -ai --timeout arquivo" {
+		t.Errorf("Comando não deveria ser alterado: %q", command)
+	}
+
+	for _, input := range []string{"--timeout", "--timeout abc ls", "--dir /caminho/que/nao/existe ls"} {
+		if _, _, err := parseCommandOptions(input); err == nil {
+			t.Errorf("Esperado erro para %q", input)
+		}
+	}
+}
+
+func TestParseCommandOptions_shell(t *testing.T) {
+	opts, command, err := parseCommandOptions("--shell PowerShell Get-ChildItem")
+	if err != nil || opts.shell != "pwsh" || command != "Get-ChildItem" {
+		t.Errorf("Esperado --shell pwsh, obtido %+v, %q, %v", opts, command, err)
+	}
+	if _, _, err := parseCommandOptions("--shell fish ls"); err == nil {
+		t.Error("Esperado erro para shell não suportado")
+	}
+}
+
+func TestShellInvocation(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh não disponível")
+	}
+	path, args, err := shellInvocation("echo $0", "sh")
+	if err != nil || !strings.HasSuffix(path, "sh") || strings.Join(args, " ") != "-c echo $0" {
+		t.Errorf("Invocação inesperada: %s %v, %v", path, args, err)
+	}
+}
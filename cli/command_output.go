@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/diillson/chatcli/utils"
+	"go.uber.org/zap"
+)
+
+// defaultCommandOutputLimit é o tamanho máximo da saída de @command enviada ao modelo e guardada no histórico
+const defaultCommandOutputLimit = 64 * 1024
+
+// commandOutputLimit retorna o limite de saída do comando: --max-output, CHATCLI_COMMAND_OUTPUT_LIMIT ou o padrão
+func (cli *ChatCLI) commandOutputLimit(opts commandOptions) int {
+	if opts.maxOutput > 0 {
+		return opts.maxOutput
+	}
+	if raw := os.Getenv("CHATCLI_COMMAND_OUTPUT_LIMIT"); raw != "" {
+		size, err := utils.ParseSize(raw)
+		if err == nil && size > 0 {
+			return int(size)
+		}
+		cli.logger.Warn("CHATCLI_COMMAND_OUTPUT_LIMIT inválido, usando o padrão", zap.String("valor", raw))
+	}
+	return defaultCommandOutputLimit
+}
+
+// truncateOutput limita a saída ao tamanho informado, mantendo o início e o fim (onde costumam estar
+// os erros) e indicando no meio quantos bytes foram removidos
+func truncateOutput(output string, limit int) string {
+	if limit <= 0 || len(output) <= limit {
+		return output
+	}
+	head := limit / 2
+	tail := len(output) - (limit - head)
+	// Não cortar caracteres multibyte ao meio
+	for head > 0 && !utf8.RuneStart(output[head]) {
+		head--
+	}
+	for tail < len(output) && !utf8.RuneStart(output[tail]) {
+		tail++
+	}
+	return fmt.Sprintf("%s\n... %d bytes truncados ...\n%s", output[:head], tail-head, output[tail:])
+}
+
+// saveCommandOutput grava a saída completa do comando no arquivo indicado por --output
+func (cli *ChatCLI) saveCommandOutput(path, output string) {
+	if err := os.WriteFile(path, []byte(output), 0644); err != nil {
+		cli.logger.Error("Erro ao gravar a saída do comando", zap.String("path", path), zap.Error(err))
+		fmt.Println("Erro ao gravar a saída do comando:", err)
+		return
+	}
+	fmt.Printf("Saída completa gravada em %s (%d bytes)\n", path, len(output))
+}
+
+// outputForModel aplica o limite à saída que vai para o histórico e para o modelo, avisando o usuário
+func (cli *ChatCLI) outputForModel(output string, opts commandOptions) string {
+	limit := cli.commandOutputLimit(opts)
+	truncated := truncateOutput(output, limit)
+	if len(truncated) != len(output) {
+		hint := "use --output <arquivo> para guardá-la inteira"
+		if opts.outputFile != "" {
+			hint = "a versão completa está em " + opts.outputFile
+		}
+		fmt.Printf("A saída tem %d bytes e foi truncada para o contexto da IA (limite de %d bytes; %s).\n",
+			len(output), limit, strings.TrimSpace(hint))
+	}
+	return truncated
+}
@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTruncateOutput(t *testing.T) {
+	if got := truncateOutput("curta", 100); got != "curta" {
+		t.Errorf("Saída abaixo do limite não deveria mudar: %q", got)
+	}
+
+	output := strings.Repeat("a", 50) + strings.Repeat("b", 100) + strings.Repeat("c", 50)
+	got := truncateOutput(output, 100)
+	if !strings.HasPrefix(got, strings.Repeat("a", 50)+"\n... 100 bytes truncados ...\n") || !strings.HasSuffix(got, strings.Repeat("c", 50)) {
+		t.Errorf("Truncamento inesperado: %q", got)
+	}
+
+	// Caracteres multibyte não devem ser cortados ao meio
+	if got := truncateOutput(strings.Repeat("ç", 100), 51); !strings.Contains(got, "ç\n...") {
+		t.Errorf("Truncamento quebrou caracteres multibyte: %q", got)
+	}
+}
+
+func TestParseCommandOptions_outputLimit(t *testing.T) {
+	opts, rest, err := parseCommandOptions("--max-output 200kb --output /tmp/saida.txt ls -la")
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if opts.maxOutput != 200*1024 || opts.outputFile != "/tmp/saida.txt" || rest != "ls -la" {
+		t.Errorf("Opções inesperadas: %+v, comando %q", opts, rest)
+	}
+	if _, _, err := parseCommandOptions("--max-output muito ls"); err == nil {
+		t.Error("Esperado erro para --max-output inválido")
+	}
+}
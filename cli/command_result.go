@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// commandResult resume como terminou um comando de @command, para que a IA saiba se ele falhou
+type commandResult struct {
+	exitCode int
+	duration time.Duration
+	timedOut bool
+	canceled bool
+	timeout  time.Duration
+	err      error
+}
+
+// newCommandResult obtém o código de saída do erro de execução. Um processo encerrado por sinal
+// (inclusive por timeout) tem código -1.
+func newCommandResult(err error, duration time.Duration, timedOut, canceled bool, timeout time.Duration) commandResult {
+	result := commandResult{duration: duration, timedOut: timedOut, canceled: canceled, timeout: timeout, err: err}
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		result.exitCode = 0
+	case errors.As(err, &exitErr):
+		result.exitCode = exitErr.ExitCode()
+	default:
+		result.exitCode = -1
+	}
+	return result
+}
+
+// status descreve o resultado em uma linha
+func (r commandResult) status() string {
+	var exitErr *exec.ExitError
+	switch {
+	case r.timedOut:
+		return fmt.Sprintf("tempo limite de %s excedido; o comando foi interrompido e a saída pode estar incompleta", r.timeout)
+	case r.canceled:
+		return "cancelado pelo usuário; a saída pode estar incompleta"
+	case r.err == nil:
+		return "sucesso"
+	case errors.As(r.err, &exitErr):
+		return "falhou"
+	default:
+		return "não pôde ser executado: " + r.err.Error()
+	}
+}
+
+// header monta o cabeçalho estruturado incluído antes da saída no histórico e no contexto da IA
+func (r commandResult) header(command string) string {
+	lines := []string{
+		"Comando: " + command,
+		fmt.Sprintf("Código de saída: %d", r.exitCode),
+		"Duração: " + r.duration.Round(time.Millisecond).String(),
+		"Status: " + r.status(),
+	}
+	return strings.Join(lines, "\n")
+}
+
+// feedbackCloseTags neutraliza, na saída, as marcações que encerram o bloco de feedback, para que a
+// saída de um comando não possa fechá-lo antes da hora
+var feedbackCloseTags = strings.NewReplacer("</saida>", "<\\/saida>", "</resultado_comando>", "<\\/resultado_comando>")
+
+// feedback é o modelo único com que o resultado de um comando volta para a IA: no histórico, em
+// @command --ai e em /explain. O cabeçalho e a saída ficam entre marcações, para que o modelo não
+// confunda a saída com o texto do usuário. Sem captured, a saída não foi capturada (comando interativo).
+func (r commandResult) feedback(command, output string, captured bool) string {
+	var builder strings.Builder
+	builder.WriteString("<resultado_comando>\n")
+	builder.WriteString(r.header(command) + "\n")
+	if captured {
+		builder.WriteString("<saida>\n")
+		if output = strings.TrimRight(feedbackCloseTags.Replace(output), "\n"); output != "" {
+			builder.WriteString(output + "\n")
+		}
+		builder.WriteString("</saida>\n")
+	} else {
+		builder.WriteString("(Comando interativo; a saída não foi capturada)\n")
+	}
+	builder.WriteString("</resultado_comando>")
+	return builder.String()
+}
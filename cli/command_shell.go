@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/diillson/chatcli/utils"
+)
+
+// commandShells são os shells aceitos por '@command --shell', com os executáveis procurados no PATH
+var commandShells = map[string][]string{
+	"bash": {"bash"},
+	"zsh":  {"zsh"},
+	"sh":   {"sh"},
+	"pwsh": {"pwsh", "powershell"},
+}
+
+// shellInvocation retorna o executável e os argumentos que executam o comando. Sem shell escolhido,
+// usa o shell do usuário carregando seu arquivo de configuração. Se o shell escolhido não estiver
+// instalado, avisa e usa o shell do usuário.
+func shellInvocation(command, shell string) (string, []string, error) {
+	if shell != "" {
+		for _, name := range commandShells[shell] {
+			path, err := exec.LookPath(name)
+			if err != nil {
+				continue
+			}
+			switch shell {
+			case "pwsh":
+				return path, []string{"-NoProfile", "-Command", command}, nil
+			case "sh":
+				return path, []string{"-c", command}, nil
+			default:
+				// bash e zsh carregam o arquivo de configuração do usuário, quando existir
+				if config := utils.GetShellConfigFilePath(shell); config != "" {
+					if _, err := os.Stat(config); err == nil {
+						return path, []string{"-c", fmt.Sprintf("source %s && %s", config, command)}, nil
+					}
+				}
+				return path, []string{"-c", command}, nil
+			}
+		}
+		fmt.Printf("Aviso: shell %s não encontrado no PATH; usando o shell padrão.\n", shell)
+	}
+
+	userShell := utils.GetUserShell()
+	shellPath, err := exec.LookPath(userShell)
+	if err != nil {
+		return "", nil, fmt.Errorf("erro ao localizar o shell: %w", err)
+	}
+	shellConfigPath := utils.GetShellConfigFilePath(userShell)
+	if shellConfigPath == "" {
+		return "", nil, fmt.Errorf("arquivo de configuração do shell %s não encontrado", userShell)
+	}
+	return shellPath, []string{"-c", fmt.Sprintf("source %s && %s", shellConfigPath, command)}, nil
+}
+
+// parseCommandShell valida o valor de '@command --shell'
+func parseCommandShell(value string) (string, error) {
+	shell := strings.ToLower(value)
+	if shell == "powershell" {
+		shell = "pwsh"
+	}
+	if _, ok := commandShells[shell]; !ok {
+		return "", fmt.Errorf("valor inválido para --shell: %s (use bash, zsh, sh ou pwsh)", value)
+	}
+	return shell, nil
+}
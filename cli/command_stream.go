@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+)
+
+// lineStreamer recebe a saída (stdout e stderr) de @command, exibindo-a no terminal linha a linha
+// enquanto o comando roda e guardando tudo para o histórico e para a IA. Sem out (--quiet), a saída
+// é apenas guardada, e quem chama a exibe ao final.
+type lineStreamer struct {
+	out     io.Writer
+	pending []byte
+	output  bytes.Buffer
+}
+
+func newLineStreamer(out io.Writer) *lineStreamer {
+	return &lineStreamer{out: out}
+}
+
+// Write guarda os dados e exibe as linhas completas; o trecho após a última quebra de linha espera a
+// próxima escrita ou Flush. Erros ao exibir não interrompem o comando.
+func (s *lineStreamer) Write(p []byte) (int, error) {
+	s.output.Write(p)
+	if s.out == nil {
+		return len(p), nil
+	}
+	s.pending = append(s.pending, p...)
+	if i := bytes.LastIndexByte(s.pending, '\n'); i >= 0 {
+		_, _ = s.out.Write(s.pending[:i+1])
+		s.pending = append(s.pending[:0], s.pending[i+1:]...)
+	}
+	return len(p), nil
+}
+
+// Flush exibe a última linha, que pode não terminar com quebra de linha
+func (s *lineStreamer) Flush() {
+	if s.out != nil && len(s.pending) > 0 {
+		_, _ = s.out.Write(append(s.pending, '\n'))
+		s.pending = nil
+	}
+}
+
+// Bytes retorna toda a saída recebida
+func (s *lineStreamer) Bytes() []byte {
+	return s.output.Bytes()
+}
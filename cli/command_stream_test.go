@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"bytes"
+	"os/exec"
+	"testing"
+)
+
+func TestLineStreamer(t *testing.T) {
+	var terminal bytes.Buffer
+	stream := newLineStreamer(&terminal)
+
+	stream.Write([]byte("criando cluster"))
+	if terminal.Len() != 0 {
+		t.Errorf("Linha incompleta não deveria ser exibida: %q", terminal.String())
+	}
+	stream.Write([]byte("...\netapa 1\netapa"))
+	if terminal.String() != "criando cluster...\netapa 1\n" {
+		t.Errorf("Linhas completas deveriam ser exibidas: %q", terminal.String())
+	}
+	stream.Write([]byte(" 2"))
+	stream.Flush()
+	if terminal.String() != "criando cluster...\netapa 1\netapa 2\n" {
+		t.Errorf("Flush deveria exibir a última linha: %q", terminal.String())
+	}
+	if string(stream.Bytes()) != "criando cluster...\netapa 1\netapa 2" {
+		t.Errorf("Saída capturada inesperada: %q", stream.Bytes())
+	}
+}
+
+func TestLineStreamer_quiet(t *testing.T) {
+	stream := newLineStreamer(nil)
+	cmd := exec.Command("sh", "-c", "echo saida; echo erro >&2")
+	cmd.Stdout, cmd.Stderr = stream, stream
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+	stream.Flush()
+	if string(stream.Bytes()) != "saida\nerro\n" {
+		t.Errorf("Stdout e stderr deveriam ser capturados: %q", stream.Bytes())
+	}
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/diillson/chatcli/llm/client"
+)
+
+// contentFilterMessage explica uma recusa por política de conteúdo, com as categorias apontadas pelo
+// provedor ou o texto da recusa, quando informados
+func contentFilterMessage(err error) string {
+	message := "O provedor recusou o pedido por suas políticas de conteúdo"
+	var providerErr *client.ProviderError
+	if errors.As(err, &providerErr) {
+		message = fmt.Sprintf("%s recusou o pedido por suas políticas de conteúdo", providerErr.Provider)
+		if len(providerErr.Categories) > 0 {
+			message += " (categorias: " + strings.Join(providerErr.Categories, ", ") + ")"
+		} else if providerErr.StatusCode == 0 && providerErr.Message != "" {
+			message += ": " + providerErr.Message
+		}
+	}
+	return message + ". Reformule o pedido ou remova o conteúdo sensível do contexto (@file, @command etc.) e tente novamente."
+}
+
+// offerDropBlockedPrompt oferece remover do histórico o prompt recusado, que de outro modo seria
+// reenviado em todas as perguntas seguintes e poderia levar o provedor a recusá-las também
+func (cli *ChatCLI) offerDropBlockedPrompt() {
+	last := len(cli.history) - 1
+	if last < 0 || cli.history[last].Role != "user" {
+		return
+	}
+	if cli.confirm("Remover o prompt recusado do histórico para que ele não seja reenviado? (s/N): ") {
+		cli.history = cli.history[:last]
+		fmt.Println("Prompt removido do histórico.")
+	}
+}
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	maxContextFilesFlag = "--max-context-files"
+	maxContextBytesFlag = "--max-context-bytes"
+)
+
+// ContextLimitFlags são as flags aceitas por ParseContextLimitFlags, oferecidas também pelo completion
+var ContextLimitFlags = []string{maxContextFilesFlag, maxContextBytesFlag}
+
+// contextLimitsUsage é exibido em erros de ParseContextLimitFlags
+const contextLimitsUsage = "uso: chatcli [--max-context-files <n>] [--max-context-bytes <tamanho>]"
+
+// ContextLimits são os limites globais de arquivos e bytes que um único prompt pode injetar, somando
+// @file, o envio em partes e o contexto padrão. Valem independentemente dos orçamentos de cada comando;
+// zero não limita.
+type ContextLimits struct {
+	MaxFiles int
+	MaxBytes int64
+}
+
+// contextUsage é a quantidade de arquivos e bytes injetados por um prompt
+type contextUsage struct {
+	files int
+	bytes int64
+}
+
+// ParseContextLimitFlags lê --max-context-files e --max-context-bytes dos argumentos do modo interativo.
+// O tamanho aceita as mesmas unidades de HISTORY_MAX_SIZE, como 500KB ou 2MB.
+func ParseContextLimitFlags(args []string) (ContextLimits, error) {
+	var limits ContextLimits
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case maxContextFilesFlag, maxContextBytesFlag:
+			if i+1 >= len(args) {
+				return limits, fmt.Errorf("valor ausente para %s", arg)
+			}
+			value := args[i+1]
+			i++
+			if arg == maxContextFilesFlag {
+				n, err := strconv.Atoi(value)
+				if err != nil || n < 1 {
+					return limits, fmt.Errorf("valor inválido para --max-context-files: %s", value)
+				}
+				limits.MaxFiles = n
+				continue
+			}
+			size, err := parseSize(value)
+			if err != nil || size < 1 {
+				return limits, fmt.Errorf("valor inválido para --max-context-bytes: %s", value)
+			}
+			limits.MaxBytes = size
+		default:
+			return limits, fmt.Errorf("argumento inesperado: %s\n%s", arg, contextLimitsUsage)
+		}
+	}
+	return limits, nil
+}
+
+// SetContextLimits define os limites informados por flags, que têm precedência sobre
+// CHATCLI_MAX_CONTEXT_FILES e CHATCLI_MAX_CONTEXT_BYTES
+func (cli *ChatCLI) SetContextLimits(limits ContextLimits) {
+	cli.flagContextLimits = limits
+}
+
+// contextLimits retorna os limites em vigor. As variáveis de ambiente são lidas a cada prompt, para
+// refletir o /reload.
+func (cli *ChatCLI) contextLimits() ContextLimits {
+	limits := cli.flagContextLimits
+	if limits.MaxFiles == 0 {
+		if n, err := strconv.Atoi(os.Getenv("CHATCLI_MAX_CONTEXT_FILES")); err == nil && n > 0 {
+			limits.MaxFiles = n
+		}
+	}
+	if limits.MaxBytes == 0 {
+		if size, err := parseSize(os.Getenv("CHATCLI_MAX_CONTEXT_BYTES")); err == nil && size > 0 {
+			limits.MaxBytes = size
+		}
+	}
+	return limits
+}
+
+// checkContextLimits soma aos arquivos de @file do prompt atual as partes de @file --mode chunked e o
+// contexto padrão, e recusa o prompt quando algum limite global é excedido
+func (cli *ChatCLI) checkContextLimits() error {
+	limits := cli.contextLimits()
+	if limits.MaxFiles == 0 && limits.MaxBytes == 0 {
+		return nil
+	}
+
+	usage := cli.promptFileUsage
+	for _, msg := range cli.primingMessages {
+		usage.bytes += int64(len(msg.Content))
+	}
+	defaultText, defaultFiles := cli.defaultContextContent()
+	usage.files += defaultFiles
+	usage.bytes += int64(len(defaultText))
+
+	switch {
+	case limits.MaxFiles > 0 && usage.files > limits.MaxFiles:
+		return fmt.Errorf("o prompt incluiria %d arquivo(s), acima do limite de %d (--max-context-files ou CHATCLI_MAX_CONTEXT_FILES).\n%s",
+			usage.files, limits.MaxFiles, contextLimitsHint(defaultFiles))
+	case limits.MaxBytes > 0 && usage.bytes > limits.MaxBytes:
+		return fmt.Errorf("o prompt incluiria %s de arquivos, acima do limite de %s (--max-context-bytes ou CHATCLI_MAX_CONTEXT_BYTES).\n%s",
+			formatBytes(usage.bytes), formatBytes(limits.MaxBytes), contextLimitsHint(defaultFiles))
+	}
+	return nil
+}
+
+// contextLimitsHint sugere como reduzir a seleção de arquivos
+func contextLimitsHint(defaultFiles int) string {
+	hints := []string{"Reduza a seleção: anexe menos arquivos, use @file --lines para trechos ou um --include mais específico em @file --tree"}
+	if defaultFiles > 0 {
+		hints = append(hints, fmt.Sprintf("o contexto padrão contribui com %d arquivo(s); veja /defaultctx show", defaultFiles))
+	}
+	return strings.Join(hints, "; ") + "."
+}
+
+// formatBytes exibe um tamanho em B, KB ou MB
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1024*1024:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1024*1024))
+	case n >= 1024:
+		return fmt.Sprintf("%.1f KB", float64(n)/1024)
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
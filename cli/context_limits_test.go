@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestParseContextLimitFlags(t *testing.T) {
+	limits, err := ParseContextLimitFlags([]string{"--max-context-files", "5", "--max-context-bytes", "2MB"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if limits.MaxFiles != 5 || limits.MaxBytes != 2*1024*1024 {
+		t.Errorf("Limites inesperados: %+v", limits)
+	}
+
+	for _, args := range [][]string{
+		{"--max-context-files"},
+		{"--max-context-files", "0"},
+		{"--max-context-bytes", "muito"},
+		{"--outra"},
+	} {
+		if _, err := ParseContextLimitFlags(args); err == nil {
+			t.Errorf("Esperava erro para %v", args)
+		}
+	}
+
+	// As flags oferecidas pelo completion são as aceitas pelo parser
+	for _, flag := range ContextLimitFlags {
+		if _, err := ParseContextLimitFlags([]string{flag, "1"}); err != nil {
+			t.Errorf("%s deveria ser aceita: %v", flag, err)
+		}
+	}
+}
+
+func TestCheckContextLimits(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.go", "b.go", "c.go"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(strings.Repeat("x", 100)), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("CHATCLI_DEFAULT_CONTEXT", filepath.Join(dir, "c.go"))
+	t.Setenv("CHATCLI_MAX_CONTEXT_FILES", "2")
+	t.Setenv("CHATCLI_MAX_CONTEXT_BYTES", "")
+
+	cli := &ChatCLI{logger: zap.NewNop()}
+	_, blocks := cli.fileContextBlocks("explique @file " + filepath.Join(dir, "a.go") + " @file " + filepath.Join(dir, "b.go"))
+	cli.promptFileUsage = composeContext(blocks, 0).fileUsage()
+	if cli.promptFileUsage.files != 2 {
+		t.Fatalf("Esperava 2 arquivos de @file, obtive %d", cli.promptFileUsage.files)
+	}
+
+	// Os arquivos do contexto padrão contam para o limite
+	err := cli.checkContextLimits()
+	if err == nil || !strings.Contains(err.Error(), "3 arquivo(s)") || !strings.Contains(err.Error(), "/defaultctx") {
+		t.Errorf("Esperava o limite de arquivos excedido, obtive %v", err)
+	}
+
+	// As flags têm precedência sobre as variáveis de ambiente
+	cli.SetContextLimits(ContextLimits{MaxFiles: 3, MaxBytes: 200})
+	if err := cli.checkContextLimits(); err == nil || !strings.Contains(err.Error(), "--max-context-bytes") {
+		t.Errorf("Esperava o limite de bytes excedido, obtive %v", err)
+	}
+
+	cli.SetContextLimits(ContextLimits{MaxFiles: 3, MaxBytes: 10 * 1024})
+	if err := cli.checkContextLimits(); err != nil {
+		t.Errorf("Não esperava erro dentro dos limites: %v", err)
+	}
+}
@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/diillson/chatcli/models"
+	"go.uber.org/zap"
+)
+
+// contextBlock é um trecho de contexto injetado por um comando @, com a origem usada no relatório, a
+// quantidade de arquivos lidos, considerada nos limites globais de ContextLimits, e os arquivos anexados,
+// que deixam de se repetir no contexto padrão se o bloco for incluído
+type contextBlock struct {
+	source   string
+	text     string
+	files    int
+	attached []string
+}
+
+// contextStage expande um tipo de comando @, retornando a entrada sem o comando e os blocos gerados
+type contextStage struct {
+	name   string
+	expand func(cli *ChatCLI, userInput string) (string, []contextBlock)
+}
+
+// singleBlock adapta os comandos que produzem todo o contexto de uma vez a um estágio com um único bloco
+func singleBlock(source string, process func(cli *ChatCLI, userInput string) (string, string)) func(*ChatCLI, string) (string, []contextBlock) {
+	return func(cli *ChatCLI, userInput string) (string, []contextBlock) {
+		userInput, text := process(cli, userInput)
+		if text == "" {
+			return userInput, nil
+		}
+		return userInput, []contextBlock{{source: source, text: text}}
+	}
+}
+
+// contextStages define a ordem em que os comandos @ são expandidos, que é também a ordem dos blocos
+// no contexto final. Um novo comando @ entra aqui como mais um estágio.
+var contextStages = []contextStage{
+	{"@history", singleBlock("@history", (*ChatCLI).processHistoryCommand)},
+	// @github é processado antes de @git, que também corresponderia ao prefixo
+	{"@github", singleBlock("@github", (*ChatCLI).processGitHubCommand)},
+	{"@git", singleBlock("@git", (*ChatCLI).processGitCommand)},
+	{"@env", singleBlock("@env", (*ChatCLI).processEnvCommand)},
+	{"@file", (*ChatCLI).fileContextBlocks},
+	{"@var", singleBlock("@var", (*ChatCLI).processVarCommand)},
+	{"@clipboard", singleBlock("@clipboard", (*ChatCLI).processClipboardCommand)},
+	{"@docker", singleBlock("@docker", (*ChatCLI).processDockerCommand)},
+	{"@k8s", singleBlock("@k8s", (*ChatCLI).processK8sCommand)},
+}
+
+// expandContextCommands executa todos os estágios sobre a entrada do usuário
+func (cli *ChatCLI) expandContextCommands(userInput string) (string, []contextBlock) {
+	var blocks []contextBlock
+	for _, stage := range contextStages {
+		var stageBlocks []contextBlock
+		userInput, stageBlocks = stage.expand(cli, userInput)
+		blocks = append(blocks, stageBlocks...)
+	}
+	return userInput, blocks
+}
+
+// contextComposition é o resultado da composição: os blocos incluídos, com seus tokens estimados, e
+// as origens dos blocos repetidos e dos omitidos por exceder o orçamento
+type contextComposition struct {
+	included   []contextBlock
+	tokens     []int
+	duplicates []string
+	omitted    []string
+}
+
+// fileUsage soma os arquivos dos blocos incluídos e o tamanho desses blocos
+func (c contextComposition) fileUsage() contextUsage {
+	var usage contextUsage
+	for _, block := range c.included {
+		if block.files > 0 {
+			usage.files += block.files
+			usage.bytes += int64(len(block.text))
+		}
+	}
+	return usage
+}
+
+// text retorna o contexto final, com os blocos incluídos na ordem dos estágios
+func (c contextComposition) text() string {
+	var builder strings.Builder
+	for _, block := range c.included {
+		builder.WriteString(block.text)
+	}
+	return builder.String()
+}
+
+// sentSources filtra as fontes de /cite cujas marcações estão nos blocos incluídos; as dos blocos
+// repetidos ou omitidos não chegam ao modelo e não podem ser citadas
+func (c contextComposition) sentSources(sources []contextSource) []contextSource {
+	sent := make(map[string]bool)
+	for _, tag := range sourceTagPattern.FindAllString(c.text(), -1) {
+		sent[tag] = true
+	}
+	var filtered []contextSource
+	for _, s := range sources {
+		if sent[sourceTag(s.id)] {
+			filtered = append(filtered, s)
+		}
+	}
+	return filtered
+}
+
+// sourceTagPattern corresponde à marcação de fonte do modo /cite, que muda a cada bloco e não deve
+// impedir a detecção de repetidos
+var sourceTagPattern = regexp.MustCompile(`\n\[Fonte S\d+\]`)
+
+// composeContext remove os blocos idênticos (mantendo o primeiro) e inclui os demais em ordem enquanto
+// couberem no orçamento de tokens. Um orçamento zero não limita o contexto.
+func composeContext(blocks []contextBlock, budget int) contextComposition {
+	var composition contextComposition
+	seen := make(map[string]bool)
+	used := 0
+	for _, block := range blocks {
+		key := strings.TrimSpace(sourceTagPattern.ReplaceAllString(block.text, ""))
+		if key == "" {
+			continue
+		}
+		if seen[key] {
+			composition.duplicates = append(composition.duplicates, block.source)
+			continue
+		}
+		seen[key] = true
+
+		tokens := estimateTokens([]models.Message{{Content: block.text}})
+		if budget > 0 && used+tokens > budget {
+			composition.omitted = append(composition.omitted, block.source)
+			continue
+		}
+		used += tokens
+		composition.included = append(composition.included, block)
+		composition.tokens = append(composition.tokens, tokens)
+	}
+	return composition
+}
+
+// contextBudget lê CHATCLI_CONTEXT_MAX_TOKENS, o orçamento conjunto dos comandos @ de um prompt
+func contextBudget() int {
+	if budget, err := strconv.Atoi(os.Getenv("CHATCLI_CONTEXT_MAX_TOKENS")); err == nil && budget > 0 {
+		return budget
+	}
+	return 0
+}
+
+// reportComposition mostra a composição do contexto quando o prompt combina mais de um bloco ou
+// quando algum bloco foi removido
+func (cli *ChatCLI) reportComposition(c contextComposition) {
+	if len(c.included) < 2 && len(c.duplicates) == 0 && len(c.omitted) == 0 {
+		return
+	}
+
+	parts := make([]string, len(c.included))
+	total := 0
+	for i, block := range c.included {
+		parts[i] = fmt.Sprintf("%s (~%d tokens)", block.source, c.tokens[i])
+		total += c.tokens[i]
+	}
+	fmt.Printf("Contexto do prompt: %s - total ~%d tokens\n", strings.Join(parts, ", "), total)
+	if len(c.duplicates) > 0 {
+		fmt.Printf("  Repetidos, incluídos uma única vez: %s\n", strings.Join(c.duplicates, ", "))
+	}
+	if len(c.omitted) > 0 {
+		fmt.Printf("  Omitidos por exceder CHATCLI_CONTEXT_MAX_TOKENS (%d): %s\n", contextBudget(), strings.Join(c.omitted, ", "))
+		cli.logger.Warn("Blocos de contexto omitidos por exceder o orçamento", zap.Strings("blocos", c.omitted))
+	}
+}
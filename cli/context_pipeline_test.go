@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestComposeContext(t *testing.T) {
+	blocks := []contextBlock{
+		{source: "@file a.go", text: "\n[Fonte S1]\nconteúdo de a.go\n"},
+		{source: "@git", text: "\nstatus do git\n"},
+		{source: "@file a.go", text: "\n[Fonte S3]\nconteúdo de a.go\n"},
+		{source: "@env", text: "\n" + strings.Repeat("x", 400) + "\n"},
+		{source: "@var", text: "\nvalor\n"},
+	}
+
+	composition := composeContext(blocks, 0)
+	if len(composition.included) != 4 || !reflect.DeepEqual(composition.duplicates, []string{"@file a.go"}) {
+		t.Errorf("Composição inesperada: %+v", composition)
+	}
+
+	// Com orçamento, o bloco grande é omitido e os seguintes ainda entram se couberem
+	composition = composeContext(blocks, 20)
+	var sources []string
+	for _, block := range composition.included {
+		sources = append(sources, block.source)
+	}
+	if !reflect.DeepEqual(sources, []string{"@file a.go", "@git", "@var"}) || !reflect.DeepEqual(composition.omitted, []string{"@env"}) {
+		t.Errorf("Composição inesperada com orçamento: incluídos %v, omitidos %v", sources, composition.omitted)
+	}
+	if composition.text() != blocks[0].text+blocks[1].text+blocks[4].text {
+		t.Errorf("Texto final inesperado: %q", composition.text())
+	}
+}
+
+func TestExpandContextCommandsOrderAndDedup(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.txt")
+	if err := os.WriteFile(path, []byte("olá"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cli := &ChatCLI{logger: zap.NewNop()}
+	userInput, blocks := cli.expandContextCommands("compare @file " + path + " com @file " + path)
+	if userInput != "compare com" {
+		t.Errorf("Entrada inesperada: %q", userInput)
+	}
+	if len(blocks) != 2 || blocks[0].source != "@file "+path {
+		t.Fatalf("Esperado um bloco por @file, obteve %+v", blocks)
+	}
+	if composition := composeContext(blocks, 0); len(composition.included) != 1 || len(composition.duplicates) != 1 {
+		t.Errorf("Esperado incluir o arquivo uma única vez: %+v", composition)
+	}
+}
+
+func TestProcessSpecialCommandsOmittedBlocksAreNotSources(t *testing.T) {
+	dir := t.TempDir()
+	small, large := filepath.Join(dir, "pequeno.txt"), filepath.Join(dir, "grande.txt")
+	if err := os.WriteFile(small, []byte("olá"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(large, []byte(strings.Repeat("texto longo ", 200)), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CHATCLI_CONTEXT_MAX_TOKENS", "100")
+
+	cli := &ChatCLI{logger: zap.NewNop(), citeMode: true}
+	_, context := cli.processSpecialCommands("compare @file " + small + " @file " + large)
+
+	if len(cli.contextSources) != 1 || cli.contextSources[0].origin != small {
+		t.Errorf("Apenas o arquivo incluído deveria ser uma fonte: %+v", cli.contextSources)
+	}
+	if strings.Contains(context, "[S2]") {
+		t.Errorf("A instrução de citação não deveria mencionar a fonte omitida: %q", context)
+	}
+	if len(cli.attachedFiles) != 1 || !cli.attachedFiles[small] {
+		t.Errorf("Apenas o arquivo incluído deveria constar como anexado: %v", cli.attachedFiles)
+	}
+}
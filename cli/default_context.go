@@ -0,0 +1,231 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/diillson/chatcli/models"
+	"github.com/diillson/chatcli/utils"
+	"go.uber.org/zap"
+)
+
+// defaultContextMaxTokens é o orçamento padrão, em tokens estimados, dos arquivos de contexto padrão
+const defaultContextMaxTokens = 8000
+
+// defaultContextEntry é um arquivo ou glob do contexto padrão, com a origem e o diretório base
+// usado para resolver caminhos relativos
+type defaultContextEntry struct {
+	pattern string
+	origin  string
+	baseDir string
+}
+
+// defaultContextFile é um arquivo do contexto padrão já resolvido
+type defaultContextFile struct {
+	display string
+	path    string
+}
+
+// defaultContextEntries reúne as entradas da configuração de projeto (default_context), de
+// CHATCLI_DEFAULT_CONTEXT e as adicionadas na sessão com /defaultctx add
+func (cli *ChatCLI) defaultContextEntries() []defaultContextEntry {
+	var entries []defaultContextEntry
+	if cli.project != nil {
+		baseDir := filepath.Dir(cli.project.Path)
+		for _, pattern := range cli.project.Context {
+			entries = append(entries, defaultContextEntry{pattern: pattern, origin: "projeto", baseDir: baseDir})
+		}
+	}
+	for _, pattern := range strings.Split(os.Getenv("CHATCLI_DEFAULT_CONTEXT"), ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			entries = append(entries, defaultContextEntry{pattern: pattern, origin: "CHATCLI_DEFAULT_CONTEXT"})
+		}
+	}
+	for _, pattern := range cli.sessionContext {
+		entries = append(entries, defaultContextEntry{pattern: pattern, origin: "sessão"})
+	}
+	return entries
+}
+
+// resolveDefaultContext expande os globs das entradas e remove os arquivos repetidos, mantendo a
+// ordem em que aparecem. Diretórios são ignorados.
+func resolveDefaultContext(entries []defaultContextEntry) []defaultContextFile {
+	var files []defaultContextFile
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		pattern, err := utils.ExpandPath(entry.pattern)
+		if err != nil {
+			continue
+		}
+		if !filepath.IsAbs(pattern) && entry.baseDir != "" {
+			pattern = filepath.Join(entry.baseDir, pattern)
+		}
+
+		isGlob := strings.ContainsAny(pattern, "*?[")
+		matches := []string{pattern}
+		if isGlob {
+			matches, _ = filepath.Glob(pattern)
+		}
+		for _, match := range matches {
+			abs, err := filepath.Abs(match)
+			if err != nil || seen[abs] {
+				continue
+			}
+			if info, err := os.Stat(abs); err != nil || info.IsDir() {
+				continue
+			}
+			seen[abs] = true
+			display := entry.pattern
+			if isGlob {
+				display = displayPath(entry, match)
+			}
+			files = append(files, defaultContextFile{display: display, path: abs})
+		}
+	}
+	return files
+}
+
+// displayPath mostra o arquivo relativo ao diretório base da entrada, quando possível
+func displayPath(entry defaultContextEntry, match string) string {
+	base := entry.baseDir
+	if base == "" {
+		base, _ = os.Getwd()
+	}
+	if rel, err := filepath.Rel(base, match); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return match
+}
+
+// markAttached registra um arquivo anexado explicitamente com @file no prompt atual, para que ele não
+// se repita no contexto padrão
+func (cli *ChatCLI) markAttached(path string) {
+	expanded, err := utils.ExpandPath(path)
+	if err != nil {
+		return
+	}
+	abs, err := filepath.Abs(expanded)
+	if err != nil {
+		return
+	}
+	if cli.attachedFiles == nil {
+		cli.attachedFiles = make(map[string]bool)
+	}
+	cli.attachedFiles[abs] = true
+}
+
+// defaultContextBudget lê CHATCLI_DEFAULT_CONTEXT_MAX_TOKENS
+func defaultContextBudget() int {
+	if budget, err := strconv.Atoi(os.Getenv("CHATCLI_DEFAULT_CONTEXT_MAX_TOKENS")); err == nil && budget > 0 {
+		return budget
+	}
+	return defaultContextMaxTokens
+}
+
+// defaultContextText lê os arquivos do contexto padrão a cada requisição, para refletir as alterações
+// feitas neles. Arquivos já anexados explicitamente com @file no prompt atual não são repetidos, e os
+// que ultrapassariam o orçamento de tokens são omitidos.
+func (cli *ChatCLI) defaultContextText() string {
+	text, _ := cli.defaultContextContent()
+	return text
+}
+
+// defaultContextContent retorna o texto de defaultContextText e a quantidade de arquivos incluídos nele
+func (cli *ChatCLI) defaultContextContent() (string, int) {
+	var builder strings.Builder
+	files := 0
+	remaining := defaultContextBudget()
+	for _, file := range resolveDefaultContext(cli.defaultContextEntries()) {
+		if cli.attachedFiles[file.path] {
+			continue
+		}
+		content, _, truncated, err := utils.ReadFileHead(file.path, maxFileContextSize)
+		if err != nil {
+			cli.logger.Warn("Erro ao ler arquivo do contexto padrão", zap.String("arquivo", file.display), zap.Error(err))
+			continue
+		}
+		if truncated {
+			content += "\n[... arquivo truncado ...]"
+		}
+		text := formatFileContext(file.display, content)
+		tokens := estimateTokens([]models.Message{{Content: text}})
+		if tokens > remaining {
+			cli.logger.Warn("Arquivo do contexto padrão omitido por exceder o orçamento de tokens",
+				zap.String("arquivo", file.display), zap.Int("tokens", tokens), zap.Int("restante", remaining))
+			continue
+		}
+		remaining -= tokens
+		builder.WriteString(text)
+		files++
+	}
+	return builder.String(), files
+}
+
+// handleDefaultContextCommand trata /defaultctx show|add|remove
+func (cli *ChatCLI) handleDefaultContextCommand(userInput string) {
+	args := strings.Fields(userInput)
+	if len(args) == 1 || args[1] == "show" {
+		cli.showDefaultContext()
+		return
+	}
+	if len(args) != 3 || (args[1] != "add" && args[1] != "remove") {
+		fmt.Println("Uso: /defaultctx show | add <arquivo|glob> | remove <arquivo|glob>")
+		return
+	}
+
+	pattern := args[2]
+	switch args[1] {
+	case "add":
+		for _, entry := range cli.defaultContextEntries() {
+			if entry.pattern == pattern {
+				fmt.Printf("%s já está no contexto padrão (%s).\n", pattern, entry.origin)
+				return
+			}
+		}
+		cli.sessionContext = append(cli.sessionContext, pattern)
+		files := resolveDefaultContext([]defaultContextEntry{{pattern: pattern}})
+		if len(files) == 0 {
+			fmt.Printf("%s adicionado ao contexto padrão, mas ainda não corresponde a nenhum arquivo.\n", pattern)
+			return
+		}
+		fmt.Printf("%s adicionado ao contexto padrão da sessão (%d arquivo(s)).\n", pattern, len(files))
+	case "remove":
+		for i, p := range cli.sessionContext {
+			if p == pattern {
+				cli.sessionContext = append(cli.sessionContext[:i], cli.sessionContext[i+1:]...)
+				fmt.Printf("%s removido do contexto padrão da sessão.\n", pattern)
+				return
+			}
+		}
+		for _, entry := range cli.defaultContextEntries() {
+			if entry.pattern == pattern {
+				fmt.Printf("%s vem de %s; edite a configuração para removê-lo.\n", pattern, entry.origin)
+				return
+			}
+		}
+		fmt.Printf("%s não está no contexto padrão.\n", pattern)
+	}
+}
+
+// showDefaultContext lista as entradas do contexto padrão, os arquivos correspondentes e o uso do orçamento
+func (cli *ChatCLI) showDefaultContext() {
+	entries := cli.defaultContextEntries()
+	if len(entries) == 0 {
+		fmt.Println("Nenhum contexto padrão. Use /defaultctx add <arquivo|glob>, default_context no .chatcli.yaml ou CHATCLI_DEFAULT_CONTEXT.")
+		return
+	}
+
+	fmt.Println("Contexto padrão (relido a cada prompt):")
+	for _, entry := range entries {
+		files := resolveDefaultContext([]defaultContextEntry{entry})
+		fmt.Printf("  %s (%s) - %d arquivo(s)\n", entry.pattern, entry.origin, len(files))
+	}
+	tokens := 0
+	if text := cli.defaultContextText(); text != "" {
+		tokens = estimateTokens([]models.Message{{Content: text}})
+	}
+	fmt.Printf("Tokens estimados: %d de %d (CHATCLI_DEFAULT_CONTEXT_MAX_TOKENS)\n", tokens, defaultContextBudget())
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/diillson/chatcli/config"
+	"go.uber.org/zap"
+)
+
+func TestDefaultContextText(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"go.mod":       "module exemplo",
+		"docs/a.md":    "documento a",
+		"docs/b.md":    "documento b",
+		"docs/big.md":  strings.Repeat("x", 4000),
+		".chatcli.yml": "",
+	} {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0700)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("CHATCLI_DEFAULT_CONTEXT", "")
+	t.Setenv("CHATCLI_DEFAULT_CONTEXT_MAX_TOKENS", "500")
+
+	cli := &ChatCLI{
+		logger:  zap.NewNop(),
+		project: &config.ProjectConfig{Path: filepath.Join(dir, ".chatcli.yml"), Context: []string{"go.mod", "docs/*.md", "go.mod"}},
+	}
+	text := cli.defaultContextText()
+	if strings.Count(text, "module exemplo") != 1 || !strings.Contains(text, "documento b") {
+		t.Errorf("Contexto padrão inesperado: %q", text)
+	}
+	if strings.Contains(text, "xxxx") {
+		t.Error("Arquivo acima do orçamento deveria ser omitido")
+	}
+
+	// Um arquivo anexado com @file no prompt atual não se repete no contexto padrão
+	cli.markAttached(filepath.Join(dir, "go.mod"))
+	if strings.Contains(cli.defaultContextText(), "module exemplo") {
+		t.Error("Arquivo anexado explicitamente não deveria se repetir")
+	}
+}
+
+func TestHandleDefaultContextCommand(t *testing.T) {
+	t.Setenv("CHATCLI_DEFAULT_CONTEXT", "README.md")
+	cli := &ChatCLI{logger: zap.NewNop()}
+
+	cli.handleDefaultContextCommand("/defaultctx add *.go")
+	cli.handleDefaultContextCommand("/defaultctx add *.go")
+	if len(cli.sessionContext) != 1 {
+		t.Errorf("Esperada uma entrada da sessão, obtido: %v", cli.sessionContext)
+	}
+	cli.handleDefaultContextCommand("/defaultctx remove README.md")
+	cli.handleDefaultContextCommand("/defaultctx remove *.go")
+	if len(cli.sessionContext) != 0 || len(cli.defaultContextEntries()) != 1 {
+		t.Errorf("Entradas inesperadas: %v", cli.defaultContextEntries())
+	}
+}
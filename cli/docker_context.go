@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diillson/chatcli/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultDockerTail é a quantidade de linhas de log incluídas quando --tail não é informado
+	defaultDockerTail = 200
+	// maxDockerTail limita --tail
+	maxDockerTail = 5000
+	// dockerTimeout é o tempo limite de cada chamada ao docker
+	dockerTimeout = 30 * time.Second
+)
+
+// dockerRequest representa um '@docker-logs <contêiner> [--tail N] [--since 10m]' ou '@docker-inspect <contêiner>'
+type dockerRequest struct {
+	inspect   bool
+	container string
+	tail      int
+	since     string
+}
+
+// extractDockerRequests extrai os comandos @docker-logs e @docker-inspect da entrada e retorna a entrada sem eles
+func extractDockerRequests(input string) ([]dockerRequest, string, error) {
+	tokens, err := parseFields(input)
+	if err != nil {
+		return nil, input, err
+	}
+
+	var requests []dockerRequest
+	var rest []string
+	for i := 0; i < len(tokens); i++ {
+		command := strings.ToLower(tokens[i])
+		if command != "@docker-logs" && command != "@docker-inspect" {
+			rest = append(rest, tokens[i])
+			continue
+		}
+		if i+1 >= len(tokens) || strings.HasPrefix(tokens[i+1], "-") {
+			return nil, input, fmt.Errorf("comando %s sem nome ou id do contêiner", command)
+		}
+		i++
+		req := dockerRequest{inspect: command == "@docker-inspect", container: tokens[i], tail: defaultDockerTail}
+		for !req.inspect && i+2 < len(tokens) && (tokens[i+1] == "--tail" || tokens[i+1] == "--since") {
+			value := tokens[i+2]
+			if tokens[i+1] == "--tail" {
+				n, err := strconv.Atoi(value)
+				if err != nil || n < 1 || n > maxDockerTail {
+					return nil, input, fmt.Errorf("valor inválido para --tail: %s (use de 1 a %d)", value, maxDockerTail)
+				}
+				req.tail = n
+			} else {
+				if d, err := time.ParseDuration(value); err != nil || d <= 0 {
+					return nil, input, fmt.Errorf("valor inválido para --since: %s (use uma duração como 10m ou 2h)", value)
+				}
+				req.since = value
+			}
+			i += 2
+		}
+		requests = append(requests, req)
+	}
+	return requests, strings.Join(rest, " "), nil
+}
+
+// processDockerCommand adiciona ao contexto os logs e o estado dos contêineres pedidos com
+// @docker-logs e @docker-inspect. Os logs seguem o limite de saída de @command.
+func (cli *ChatCLI) processDockerCommand(userInput string) (string, string) {
+	if !strings.Contains(strings.ToLower(userInput), "@docker-") {
+		return userInput, ""
+	}
+
+	requests, rest, err := extractDockerRequests(userInput)
+	if err != nil {
+		cli.logger.Error("Erro ao processar o comando @docker", zap.Error(err))
+		fmt.Println("Erro no comando @docker:", err)
+		return userInput, ""
+	}
+
+	var additionalContext string
+	for _, req := range requests {
+		ctx, cancel := context.WithTimeout(context.Background(), dockerTimeout)
+		if req.inspect {
+			summary, err := utils.DockerInspect(ctx, req.container)
+			cancel()
+			if err != nil {
+				cli.logger.Warn("Erro ao inspecionar o contêiner", zap.String("container", req.container), zap.Error(err))
+				fmt.Printf("Erro no comando @docker-inspect %s: %v\n", req.container, err)
+				continue
+			}
+			additionalContext += fmt.Sprintf("\nDocker inspect do contêiner %s (resumo, sem variáveis de ambiente):%s\n```json\n%s\n```\n",
+				req.container, cli.tagSource("@docker-inspect "+req.container), summary)
+			continue
+		}
+
+		logs, err := utils.DockerLogs(ctx, req.container, req.tail, req.since)
+		cancel()
+		if err != nil {
+			cli.logger.Warn("Erro ao obter os logs do contêiner", zap.String("container", req.container), zap.Error(err))
+			fmt.Printf("Erro no comando @docker-logs %s: %v\n", req.container, err)
+			continue
+		}
+		if strings.TrimSpace(logs) == "" {
+			fmt.Printf("O contêiner %s não tem logs no período pedido.\n", req.container)
+			continue
+		}
+		period := fmt.Sprintf("últimas %d linhas", req.tail)
+		if req.since != "" {
+			period += ", desde " + req.since
+		}
+		additionalContext += fmt.Sprintf("\nLogs do contêiner %s (%s):%s\n```\n%s\n```\n",
+			req.container, period, cli.tagSource("@docker-logs "+req.container),
+			cli.outputForModel(strings.TrimRight(logs, "\n"), commandOptions{}))
+	}
+	return rest, additionalContext
+}
@@ -0,0 +1,31 @@
+package cli
+
+import "testing"
+
+func TestExtractDockerRequests(t *testing.T) {
+	requests, rest, err := extractDockerRequests("por que está caindo? @docker-logs api --tail 50 --since 10m @docker-inspect api")
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if rest != "por que está caindo?" {
+		t.Errorf("Entrada inesperada: %q", rest)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("Esperados 2 pedidos, obtidos %d", len(requests))
+	}
+	if r := requests[0]; r.inspect || r.container != "api" || r.tail != 50 || r.since != "10m" {
+		t.Errorf("Pedido de logs inesperado: %+v", r)
+	}
+	if r := requests[1]; !r.inspect || r.container != "api" {
+		t.Errorf("Pedido de inspect inesperado: %+v", r)
+	}
+
+	if requests, _, _ := extractDockerRequests("@docker-logs web"); requests[0].tail != defaultDockerTail {
+		t.Errorf("Esperado --tail padrão, obtido %d", requests[0].tail)
+	}
+	for _, input := range []string{"@docker-logs", "@docker-logs --tail 10", "@docker-logs api --tail 0", "@docker-logs api --since ontem"} {
+		if _, _, err := extractDockerRequests(input); err == nil {
+			t.Errorf("Esperado erro para %q", input)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diillson/chatcli/config"
+	"github.com/diillson/chatcli/llm/manager"
+	"github.com/diillson/chatcli/utils"
+	"github.com/joho/godotenv"
+	"go.uber.org/zap"
+)
+
+// doctorProbeTimeout é o tempo limite da verificação de conexão com cada provedor
+const doctorProbeTimeout = 5 * time.Second
+
+// doctorTools são as ferramentas externas usadas pelos comandos @, com o comando que depende de cada uma
+var doctorTools = []struct{ name, usedBy string }{
+	{"git", "@git e @github"},
+	{"docker", "@docker-logs e @docker-inspect"},
+	{"kubectl", "@k8s"},
+}
+
+// doctorStatus é o resultado de um item do diagnóstico
+type doctorStatus int
+
+const (
+	doctorOK doctorStatus = iota
+	// doctorInfo não é um problema, como um provedor opcional não configurado
+	doctorInfo
+	doctorWarn
+	doctorFail
+)
+
+func (s doctorStatus) String() string {
+	switch s {
+	case doctorOK:
+		return "[OK]"
+	case doctorInfo:
+		return "[--]"
+	case doctorWarn:
+		return "[AVISO]"
+	default:
+		return "[FALHA]"
+	}
+}
+
+// doctorCheck é um item do diagnóstico, com a correção sugerida quando há problema
+type doctorCheck struct {
+	status doctorStatus
+	name   string
+	detail string
+	fix    string
+}
+
+// runDoctorChecks reúne o diagnóstico do ambiente: o .env, os provedores e a conexão com eles, as
+// ferramentas externas, o arquivo de log e o diretório de configuração
+func runDoctorChecks(ctx context.Context, mgr manager.LLMManager, logger *zap.Logger) []doctorCheck {
+	var checks []doctorCheck
+	checks = append(checks, dotenvCheck(config.DotenvPath()))
+	checks = append(checks, providerChecks(ctx, mgr, utils.NewHTTPClient(logger, doctorProbeTimeout))...)
+	for _, tool := range doctorTools {
+		checks = append(checks, toolCheck(tool.name, tool.usedBy))
+	}
+	checks = append(checks, logFileCheck())
+	checks = append(checks, configDirCheck())
+	return checks
+}
+
+// dotenvCheck verifica se o .env existe, pode ser lido e não está acessível a outros usuários
+func dotenvCheck(path string) doctorCheck {
+	check := doctorCheck{name: ".env"}
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		check.status, check.detail = doctorWarn, fmt.Sprintf("não encontrado em %s; apenas as variáveis de ambiente são usadas", path)
+		check.fix = "crie o arquivo (veja o exemplo no README) ou aponte CHATCLI_DOTENV para ele"
+		return check
+	}
+	if err != nil {
+		check.status, check.detail = doctorFail, err.Error()
+		return check
+	}
+	if _, err := godotenv.Read(path); err != nil {
+		check.status, check.detail = doctorFail, fmt.Sprintf("erro ao ler %s: %v", path, err)
+		check.fix = "corrija a sintaxe do arquivo (linhas CHAVE=valor)"
+		return check
+	}
+	check.status, check.detail = doctorOK, "carregado de "+path
+	if info.Mode().Perm()&0077 != 0 {
+		check.status = doctorWarn
+		check.detail += fmt.Sprintf(", com permissões %o", info.Mode().Perm())
+		check.fix = fmt.Sprintf("o arquivo guarda chaves; use chmod 600 %s", path)
+	}
+	return check
+}
+
+// providerChecks verifica a configuração de cada provedor, o provedor padrão e, em paralelo, a conexão
+// com os provedores disponíveis. Nenhuma chamada autenticada é feita; para validar as chaves, use /keys check.
+func providerChecks(ctx context.Context, mgr manager.LLMManager, httpClient *http.Client) []doctorCheck {
+	available := make(map[string]bool)
+	for _, p := range mgr.GetAvailableProviders() {
+		available[p] = true
+	}
+
+	providers := allProviders()
+	checks := make([]doctorCheck, len(providers))
+	var reachability []doctorCheck
+	var endpoints []string
+	for i, p := range providers {
+		check := doctorCheck{name: "Provedor " + p.name}
+		var missing []string
+		for _, env := range p.credentials {
+			if !credentialSet(env) {
+				missing = append(missing, env)
+			}
+		}
+		switch {
+		case available[p.name]:
+			check.status, check.detail = doctorOK, "configurado"
+			if problem := keyFormatProblem(p.name); problem != "" {
+				check.status, check.detail = doctorFail, problem
+				check.fix = "copie a chave novamente, sem espaços nem aspas, e use /reload"
+			}
+			reachability = append(reachability, doctorCheck{name: "Conexão com " + p.name})
+			endpoints = append(endpoints, p.endpoint())
+		case len(missing) > 0:
+			check.status, check.detail = doctorInfo, "não configurado; faltando "+strings.Join(missing, ", ")
+			check.fix = "para usá-lo, defina as variáveis no .env e use /reload"
+		case len(p.credentials) == 0:
+			check.status, check.detail = doctorInfo, "não encontrado em "+p.endpoint()
+		default:
+			check.status, check.detail = doctorWarn, "credenciais definidas, mas o provedor não está disponível"
+			check.fix = "verifique os erros no arquivo de log"
+		}
+		checks[i] = check
+	}
+	checks = append(checks, defaultProviderCheck(available))
+
+	var wg sync.WaitGroup
+	for i := range reachability {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			reachability[i].status, reachability[i].detail, reachability[i].fix = reachabilityCheck(ctx, httpClient, endpoints[i])
+		}(i)
+	}
+	wg.Wait()
+	return append(checks, reachability...)
+}
+
+// defaultProviderCheck verifica se o provedor de LLM_PROVIDER está disponível
+func defaultProviderCheck(available map[string]bool) doctorCheck {
+	check := doctorCheck{name: "Provedor padrão"}
+	provider := utils.GetEnvOrDefault("LLM_PROVIDER", "STACKSPOT")
+	switch {
+	case len(available) == 0:
+		check.status, check.detail = doctorFail, "nenhum provedor está configurado"
+		check.fix = "defina a chave de um provedor no .env (por exemplo, OPENAI_API_KEY) e use /reload"
+	case strings.EqualFold(provider, autoProvider):
+		check.status, check.detail = doctorOK, "AUTO (roteamento automático)"
+	case !available[strings.ToUpper(provider)]:
+		check.status, check.detail = doctorFail, fmt.Sprintf("LLM_PROVIDER=%s, mas o provedor não está disponível", provider)
+		check.fix = "configure o provedor ou escolha um disponível em LLM_PROVIDER"
+	default:
+		check.status, check.detail = doctorOK, provider
+	}
+	return check
+}
+
+// reachabilityCheck envia um HEAD sem credenciais ao endpoint pelo mesmo cliente HTTP dos provedores, que
+// respeita HTTPS_PROXY, NO_PROXY e CHATCLI_CA_BUNDLE. Qualquer status HTTP indica que o endpoint responde;
+// só falhas de conexão, de TLS e de tempo limite contam.
+func reachabilityCheck(ctx context.Context, httpClient *http.Client, endpoint string) (doctorStatus, string, string) {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return doctorFail, fmt.Sprintf("URL inválida %q", endpoint), "corrija a URL base do provedor"
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, doctorProbeTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, endpoint, nil)
+	if err != nil {
+		return doctorFail, err.Error(), "corrija a URL base do provedor"
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		if utils.IsTLSVerificationError(err) {
+			return doctorFail, fmt.Sprintf("certificado de %s não reconhecido: %v", u.Host, err),
+				"se a rede usa um proxy com inspeção de TLS, aponte CHATCLI_CA_BUNDLE para a CA dele"
+		}
+		return doctorFail, fmt.Sprintf("sem conexão com %s: %v", u.Host, err),
+			"verifique a rede, o proxy (HTTPS_PROXY) e a URL base do provedor"
+	}
+	resp.Body.Close()
+	return doctorOK, fmt.Sprintf("%s acessível (HTTP %d)", u.Host, resp.StatusCode), ""
+}
+
+// toolCheck verifica se uma ferramenta externa está no PATH
+func toolCheck(name, usedBy string) doctorCheck {
+	check := doctorCheck{name: "Ferramenta " + name}
+	path, err := exec.LookPath(name)
+	if err != nil {
+		check.status, check.detail = doctorWarn, "não encontrada no PATH"
+		check.fix = fmt.Sprintf("instale %s para usar %s", name, usedBy)
+		return check
+	}
+	check.status, check.detail = doctorOK, path
+	return check
+}
+
+// logFileCheck verifica se o arquivo de LOG_FILE pode ser gravado, resolvendo o caminho como o logger
+func logFileCheck() doctorCheck {
+	check := doctorCheck{name: "Arquivo de log"}
+	path := utils.GetEnvOrDefault("LOG_FILE", "app.log")
+	if expanded, err := utils.ExpandPath(path); err == nil {
+		path = expanded
+	}
+	if err := checkWritable(path); err != nil {
+		check.status, check.detail = doctorFail, fmt.Sprintf("%s não pode ser gravado: %v", path, err)
+		check.fix = "ajuste as permissões ou aponte LOG_FILE para um local gravável"
+		return check
+	}
+	check.status, check.detail = doctorOK, path
+	return check
+}
+
+// checkWritable abre o arquivo para acrescentar dados ou, se ele não existir, cria e remove um arquivo
+// temporário no diretório, sem alterar o conteúdo existente
+func checkWritable(path string) error {
+	if _, err := os.Stat(path); err == nil {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+		if err != nil {
+			return err
+		}
+		return f.Close()
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), ".chatcli-doctor-*")
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// configDirCheck verifica o diretório ~/.chatcli, que guarda memória, histórico, apelidos e provedores
+func configDirCheck() doctorCheck {
+	check := doctorCheck{name: "Diretório de configuração"}
+	dir, err := utils.ExpandPath("~/.chatcli")
+	if err != nil {
+		check.status, check.detail = doctorFail, err.Error()
+		return check
+	}
+	info, err := os.Stat(dir)
+	if os.IsNotExist(err) {
+		check.status, check.detail = doctorOK, dir+" ainda não existe; será criado quando necessário"
+		return check
+	}
+	if err != nil {
+		check.status, check.detail = doctorFail, err.Error()
+		return check
+	}
+	if err := checkWritable(filepath.Join(dir, ".doctor")); err != nil {
+		check.status, check.detail = doctorFail, fmt.Sprintf("%s não pode ser gravado: %v", dir, err)
+		check.fix = "ajuste o dono e as permissões do diretório"
+		return check
+	}
+	check.status, check.detail = doctorOK, dir
+	if info.Mode().Perm()&0077 != 0 {
+		check.status = doctorWarn
+		check.detail += fmt.Sprintf(", com permissões %o", info.Mode().Perm())
+		check.fix = "o diretório guarda memória e histórico; use chmod 700 " + dir
+	}
+	return check
+}
+
+// writeDoctorReport exibe o checklist e retorna a quantidade de falhas
+func writeDoctorReport(w io.Writer, checks []doctorCheck) int {
+	failures, warnings := 0, 0
+	for _, c := range checks {
+		fmt.Fprintf(w, "%-8s %s: %s\n", c.status, c.name, c.detail)
+		if c.fix != "" && c.status >= doctorWarn {
+			fmt.Fprintf(w, "         -> %s\n", c.fix)
+		}
+		switch c.status {
+		case doctorFail:
+			failures++
+		case doctorWarn:
+			warnings++
+		}
+	}
+	fmt.Fprintf(w, "\n%d falha(s), %d aviso(s).", failures, warnings)
+	if failures == 0 && warnings == 0 {
+		fmt.Fprint(w, " Tudo pronto.")
+	}
+	fmt.Fprintln(w)
+	return failures
+}
+
+// RunDoctor executa o diagnóstico de 'chatcli doctor' e retorna a quantidade de falhas
+func RunDoctor(ctx context.Context, mgr manager.LLMManager, logger *zap.Logger, w io.Writer) int {
+	return writeDoctorReport(w, runDoctorChecks(ctx, mgr, logger))
+}
+
+// handleDoctorCommand trata /doctor
+func (cli *ChatCLI) handleDoctorCommand() {
+	fmt.Println("Diagnosticando o ambiente...")
+	RunDoctor(context.Background(), cli.manager, cli.logger, os.Stdout)
+}
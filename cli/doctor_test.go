@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diillson/chatcli/utils"
+	"go.uber.org/zap"
+)
+
+func TestDotenvCheck(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".env")
+
+	if c := dotenvCheck(path); c.status != doctorWarn || c.fix == "" {
+		t.Errorf("Esperado aviso para .env ausente, obtido %+v", c)
+	}
+
+	if err := os.WriteFile(path, []byte("OPENAI_API_KEY=sk-teste\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if c := dotenvCheck(path); c.status != doctorOK || !strings.Contains(c.detail, path) {
+		t.Errorf("Esperado OK com o caminho do .env, obtido %+v", c)
+	}
+
+	if err := os.Chmod(path, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if c := dotenvCheck(path); c.status != doctorWarn || !strings.Contains(c.fix, "chmod 600") {
+		t.Errorf("Esperado aviso de permissões, obtido %+v", c)
+	}
+}
+
+func TestReachabilityCheck(t *testing.T) {
+	httpClient := utils.NewHTTPClient(zap.NewNop(), time.Second)
+
+	status, detail, _ := reachabilityCheck(context.Background(), httpClient, "sem-esquema")
+	if status != doctorFail || !strings.Contains(detail, "URL inválida") {
+		t.Errorf("Esperada falha para URL inválida, obtido %v %q", status, detail)
+	}
+
+	// Qualquer status HTTP, mesmo sem credenciais, indica que o endpoint responde
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	status, detail, _ = reachabilityCheck(context.Background(), httpClient, server.URL+"/v1")
+	if status != doctorOK || !strings.Contains(detail, "HTTP 401") {
+		t.Errorf("Esperado endpoint acessível, obtido %v %q", status, detail)
+	}
+
+	server.Close()
+	if status, _, fix := reachabilityCheck(context.Background(), httpClient, server.URL+"/v1"); status != doctorFail || !strings.Contains(fix, "HTTPS_PROXY") {
+		t.Errorf("Esperada falha para endpoint fora do ar, obtido %v", status)
+	}
+}
+
+func TestCheckWritable(t *testing.T) {
+	dir := t.TempDir()
+	if err := checkWritable(filepath.Join(dir, "app.log")); err != nil {
+		t.Errorf("Diretório temporário deveria ser gravável: %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("A verificação não deveria deixar arquivos, restaram %d", len(entries))
+	}
+	if err := checkWritable(filepath.Join(dir, "inexistente", "app.log")); err == nil {
+		t.Error("Esperado erro para diretório inexistente")
+	}
+}
+
+func TestWriteDoctorReport(t *testing.T) {
+	var out bytes.Buffer
+	failures := writeDoctorReport(&out, []doctorCheck{
+		{status: doctorOK, name: "Ferramenta git", detail: "/usr/bin/git"},
+		{status: doctorInfo, name: "Provedor OLLAMA", detail: "não encontrado", fix: "não exibida"},
+		{status: doctorWarn, name: "Ferramenta kubectl", detail: "não encontrada no PATH", fix: "instale kubectl"},
+		{status: doctorFail, name: "Arquivo de log", detail: "sem permissão", fix: "ajuste as permissões"},
+	})
+	report := out.String()
+
+	if failures != 1 {
+		t.Errorf("Esperada 1 falha, obtido %d", failures)
+	}
+	for _, want := range []string{"[OK]", "[--]", "[AVISO]", "[FALHA]", "-> instale kubectl", "-> ajuste as permissões", "1 falha(s), 1 aviso(s)."} {
+		if !strings.Contains(report, want) {
+			t.Errorf("Esperado %q no relatório:\n%s", want, report)
+		}
+	}
+	if strings.Contains(report, "não exibida") {
+		t.Errorf("Itens informativos não deveriam exibir correção:\n%s", report)
+	}
+}
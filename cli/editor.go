@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/peterh/liner"
+	"go.uber.org/zap"
+)
+
+// editorCommand retorna o editor configurado em VISUAL ou EDITOR, com seus argumentos (ex: "code --wait")
+func editorCommand() ([]string, error) {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if value := strings.TrimSpace(os.Getenv(env)); value != "" {
+			args, err := parseFields(value)
+			if err != nil || len(args) == 0 {
+				return nil, fmt.Errorf("valor inválido em %s: %s", env, value)
+			}
+			return args, nil
+		}
+	}
+	return nil, fmt.Errorf("nenhum editor definido; defina a variável EDITOR (ex: export EDITOR=vim)")
+}
+
+// editText abre o editor com o texto inicial em um arquivo temporário e retorna o conteúdo salvo.
+// O liner é fechado durante a edição para liberar o terminal.
+func (cli *ChatCLI) editText(initial string) (string, error) {
+	args, err := editorCommand()
+	if err != nil {
+		return "", err
+	}
+
+	file, err := os.CreateTemp("", "chatcli-prompt-*.md")
+	if err != nil {
+		return "", fmt.Errorf("erro ao criar o arquivo temporário: %w", err)
+	}
+	path := file.Name()
+	defer os.Remove(path)
+	_, err = file.WriteString(initial)
+	file.Close()
+	if err != nil {
+		return "", fmt.Errorf("erro ao escrever o arquivo temporário: %w", err)
+	}
+
+	cli.line.Close()
+	err = cli.runInteractiveCommand(exec.Command(args[0], append(args[1:], path)...), newStdinTerminalState())
+	cli.reopenLiner()
+	if err != nil {
+		return "", fmt.Errorf("o editor %s terminou com erro (%v); o texto foi descartado", args[0], err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("erro ao ler o arquivo editado: %w", err)
+	}
+	return string(data), nil
+}
+
+// reopenLiner recria o liner após um programa interativo usar o terminal
+func (cli *ChatCLI) reopenLiner() {
+	cli.line = liner.NewLiner()
+	cli.line.SetCtrlCAborts(true)
+	for _, cmd := range cli.commandHistory {
+		cli.line.AppendHistory(cmd)
+	}
+	cli.line.SetCompleter(cli.completer)
+}
+
+// handleEditCommand trata /edit [last]: compõe o próximo prompt no editor e o envia após confirmação.
+// Com 'last', o editor começa com o último prompt enviado.
+func (cli *ChatCLI) handleEditCommand(userInput string) {
+	args := strings.Fields(userInput)
+	if len(args) > 2 || (len(args) == 2 && args[1] != "last") {
+		fmt.Println("Uso: /edit [last]")
+		return
+	}
+	initial := ""
+	if len(args) == 2 {
+		if cli.lastPrompt == "" {
+			fmt.Println("Nenhum prompt enviado nesta sessão.")
+			return
+		}
+		initial = cli.lastPrompt
+	}
+
+	prompt, err := cli.editText(initial)
+	if err != nil {
+		cli.logger.Warn("Erro ao editar o prompt", zap.Error(err))
+		fmt.Println("Erro:", err)
+		return
+	}
+	prompt = strings.TrimSpace(prompt)
+	if prompt == "" {
+		fmt.Println("Prompt vazio; nada foi enviado.")
+		return
+	}
+
+	fmt.Printf("Prompt (%d linha(s)):\n%s\n", strings.Count(prompt, "\n")+1, prompt)
+	if !cli.confirm("Enviar este prompt? (s/N): ") {
+		fmt.Println("Prompt descartado.")
+		return
+	}
+	cli.sendPrompt(context.Background(), prompt)
+}
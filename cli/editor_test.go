@@ -0,0 +1,26 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEditorCommand(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", `code --wait`)
+	args, err := editorCommand()
+	if err != nil || strings.Join(args, "|") != "code|--wait" {
+		t.Errorf("Editor inesperado: %v, %v", args, err)
+	}
+
+	t.Setenv("VISUAL", "nvim")
+	if args, _ := editorCommand(); len(args) != 1 || args[0] != "nvim" {
+		t.Errorf("VISUAL deveria ter precedência: %v", args)
+	}
+
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "")
+	if _, err := editorCommand(); err == nil {
+		t.Error("Esperado erro sem editor definido")
+	}
+}
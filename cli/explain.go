@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// explainInstruction é o pedido enviado por /explain junto com o resultado do comando
+const explainInstruction = "Explique o que este comando faz e o que a saída significa. Se ele falhou ou foi interrompido, " +
+	"explique a causa provável e como corrigir."
+
+// executedCommand guarda o último comando executado com @command, para /explain
+type executedCommand struct {
+	command string
+	result  commandResult
+	// output é a saída já limitada e sem segredos, como foi incluída no histórico
+	output string
+	// captured é falso em comandos interativos, cuja saída não é capturada
+	captured bool
+}
+
+// explainPrompt monta o resultado do comando no mesmo formato de @command --ai
+func (c executedCommand) explainPrompt() string {
+	return c.result.feedback(c.command, c.output, c.captured)
+}
+
+// handleExplainCommand trata /explain [pergunta], pedindo à IA que explique o último @command
+func (cli *ChatCLI) handleExplainCommand(userInput string) {
+	if cli.lastCommandResult == nil {
+		fmt.Println("Nenhum comando executado nesta sessão. Use @command <comando> e depois /explain.")
+		return
+	}
+
+	instruction := explainInstruction
+	if question := strings.TrimSpace(strings.TrimPrefix(userInput, "/explain")); question != "" {
+		instruction += " Responda especificamente: " + question
+	}
+	cli.sendOutputToAI(cli.lastCommandResult.explainPrompt(), instruction)
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// lineRange representa um intervalo de linhas (1-based, inclusivo). end == 0 indica até o fim do arquivo.
+type lineRange struct {
+	start int
+	end   int
+}
+
+// String retorna o intervalo no formato START:END
+func (r lineRange) String() string {
+	if r.end == 0 {
+		return fmt.Sprintf("%d:", r.start)
+	}
+	return fmt.Sprintf("%d:%d", r.start, r.end)
+}
+
+// parseLineRanges interpreta intervalos no formato "40:120" ou "1:20,100:150"
+func parseLineRanges(spec string) ([]lineRange, error) {
+	var ranges []lineRange
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		bounds := strings.SplitN(part, ":", 2)
+		if len(bounds) != 2 {
+			return nil, fmt.Errorf("intervalo inválido '%s': use o formato INÍCIO:FIM", part)
+		}
+		start, err := strconv.Atoi(strings.TrimSpace(bounds[0]))
+		if err != nil || start < 1 {
+			return nil, fmt.Errorf("intervalo inválido '%s': a linha inicial deve ser um número maior ou igual a 1", part)
+		}
+		end := 0
+		if endStr := strings.TrimSpace(bounds[1]); endStr != "" {
+			end, err = strconv.Atoi(endStr)
+			if err != nil || end < start {
+				return nil, fmt.Errorf("intervalo inválido '%s': a linha final deve ser maior ou igual à inicial", part)
+			}
+		}
+		ranges = append(ranges, lineRange{start: start, end: end})
+	}
+	if len(ranges) == 0 {
+		return nil, fmt.Errorf("nenhum intervalo de linhas informado")
+	}
+	return ranges, nil
+}
+
+// extractLineRanges retorna as linhas dos intervalos informados, numeradas com a posição original no arquivo
+func extractLineRanges(content string, ranges []lineRange) (string, error) {
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	total := len(lines)
+	width := len(strconv.Itoa(total))
+
+	var builder strings.Builder
+	for i, r := range ranges {
+		end := r.end
+		if end == 0 {
+			end = total
+		}
+		if r.start > total || end > total {
+			return "", fmt.Errorf("intervalo %s fora dos limites do arquivo (%d linhas)", r, total)
+		}
+		if i > 0 {
+			builder.WriteString("...\n")
+		}
+		for n := r.start; n <= end; n++ {
+			builder.WriteString(fmt.Sprintf("%*d | %s\n", width, n, lines[n-1]))
+		}
+	}
+	return strings.TrimSuffix(builder.String(), "\n"), nil
+}
+
+// formatFileSliceContext formata um trecho de arquivo para o contexto, indicando os intervalos de linhas no cabeçalho
+func formatFileSliceContext(filePath string, ranges []lineRange, slice string) string {
+	labels := make([]string, len(ranges))
+	for i, r := range ranges {
+		labels[i] = r.String()
+	}
+	fileType := detectFileType(filePath)
+	header := fmt.Sprintf("\nConteúdo do Arquivo (%s - %s, linhas %s):\n", filePath, fileType, strings.Join(labels, ","))
+	if isCodeFile(fileType) {
+		return fmt.Sprintf("%s```%s\n%s\n```\n", header, fileType, slice)
+	}
+	return fmt.Sprintf("%s%s\n", header, slice)
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseLineRanges(t *testing.T) {
+	ranges, err := parseLineRanges("1:20,100:150")
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if len(ranges) != 2 || ranges[0] != (lineRange{1, 20}) || ranges[1] != (lineRange{100, 150}) {
+		t.Errorf("Intervalos inesperados: %v", ranges)
+	}
+
+	for _, invalid := range []string{"0:10", "10:5", "abc", "5-10", ""} {
+		if _, err := parseLineRanges(invalid); err == nil {
+			t.Errorf("Esperado erro para o intervalo '%s'", invalid)
+		}
+	}
+}
+
+func TestExtractLineRanges(t *testing.T) {
+	content := "um\ndois\ntrês\nquatro\ncinco\n"
+
+	slice, err := extractLineRanges(content, []lineRange{{2, 3}, {5, 0}})
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	expected := "2 | dois\n3 | três\n...\n5 | cinco"
+	if slice != expected {
+		t.Errorf("Trecho inesperado:\n%s", slice)
+	}
+
+	if _, err := extractLineRanges(content, []lineRange{{4, 9}}); err == nil || !strings.Contains(err.Error(), "5 linhas") {
+		t.Errorf("Esperado erro de intervalo fora dos limites, obtido: %v", err)
+	}
+}
+
+func TestExtractFileRequestsWithLines(t *testing.T) {
+	requests, err := extractFileRequests("explique @file --lines 10:20 main.go e @file cli.go --lines 1:5,8:9 agora")
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if len(requests) != 2 || requests[0].path != "main.go" || requests[1].path != "cli.go" {
+		t.Fatalf("Requisições inesperadas: %+v", requests)
+	}
+	if len(requests[0].ranges) != 1 || len(requests[1].ranges) != 2 {
+		t.Errorf("Intervalos inesperados: %+v", requests)
+	}
+
+	cleaned := removeAllFileCommands("explique @file --lines 10:20 main.go e @file cli.go --lines 1:5,8:9 agora")
+	if cleaned != "explique e agora" {
+		t.Errorf("Entrada limpa inesperada: '%s'", cleaned)
+	}
+}
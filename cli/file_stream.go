@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/diillson/chatcli/models"
+	"github.com/diillson/chatcli/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxFileContextSize é o máximo lido de um arquivo por @file; o restante é descartado sem ser lido
+	maxFileContextSize = 5000000
+	// fileChunkSize é o tamanho de cada parte enviada por @file --mode chunked
+	fileChunkSize = 64 * 1024
+	// maxFileChunks limita a quantidade de partes enviadas por @file --mode chunked (cerca de 5 MB no total)
+	maxFileChunks = 80
+)
+
+// fileHeadContext lê até maxFileContextSize bytes do arquivo e os formata para o contexto, indicando
+// quando o arquivo foi truncado e até qual linha ele foi incluído
+func (cli *ChatCLI) fileHeadContext(path string) string {
+	content, size, truncated, err := utils.ReadFileHead(path, maxFileContextSize)
+	if err != nil {
+		cli.logger.Error(fmt.Sprintf("Erro ao ler o arquivo '%s'", path), zap.Error(err))
+		return ""
+	}
+	if truncated {
+		lines := strings.Count(content, "\n")
+		fmt.Printf("Arquivo '%s' truncado: %d de %d bytes incluídos (até a linha %d).\n", path, len(content), size, lines)
+		content += fmt.Sprintf("\n[... arquivo truncado: %d de %d bytes incluídos, até a linha %d ...]", len(content), size, lines)
+	}
+	return cli.tagSource(path) + formatFileContext(path, content)
+}
+
+// chunkedFileContext divide o arquivo em partes que são enviadas, antes da pergunta, como uma sequência
+// de mensagens do usuário confirmadas pelo assistente. As mensagens ficam em cli.primingMessages até
+// serem incluídas no histórico por sendPrompt; o contexto retornado apenas as referencia.
+func (cli *ChatCLI) chunkedFileContext(path string) string {
+	chunks, truncated, err := utils.ReadFileChunks(path, fileChunkSize, maxFileChunks)
+	if err != nil {
+		cli.logger.Error(fmt.Sprintf("Erro ao ler o arquivo '%s'", path), zap.Error(err))
+		return ""
+	}
+	if len(chunks) == 0 {
+		return cli.tagSource(path) + formatFileContext(path, "")
+	}
+
+	fileType := detectFileType(path)
+	for i, chunk := range chunks {
+		header := fmt.Sprintf("Parte %d de %d do arquivo %s (%s). Apenas confirme o recebimento; a pergunta virá depois.",
+			i+1, len(chunks), path, fileType)
+		if i == len(chunks)-1 && truncated {
+			chunk += fmt.Sprintf("\n[... arquivo truncado após %d partes ...]", maxFileChunks)
+		}
+		cli.primingMessages = append(cli.primingMessages,
+			models.Message{Role: "user", Content: fmt.Sprintf("%s\n```\n%s\n```", header, strings.TrimRight(chunk, "\n"))},
+			models.Message{Role: "assistant", Content: fmt.Sprintf("Recebi a parte %d de %d de %s.", i+1, len(chunks), path)},
+		)
+	}
+
+	if truncated {
+		fmt.Printf("Arquivo '%s' truncado: apenas as primeiras %d partes de %d KB serão enviadas.\n", path, maxFileChunks, fileChunkSize/1024)
+	}
+	fmt.Printf("Arquivo '%s' enviado em %d parte(s).\n", path, len(chunks))
+	return cli.tagSource(path) + fmt.Sprintf("\nO conteúdo do arquivo %s foi enviado em %d parte(s) nas mensagens anteriores.\n", path, len(chunks))
+}
@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestChunkedFileContext(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dados.txt")
+	if err := os.WriteFile(path, []byte(strings.Repeat("x\n", fileChunkSize)), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cli := &ChatCLI{logger: zap.NewNop()}
+	userInput, blocks := cli.fileContextBlocks("resuma @file --mode chunked " + path)
+	if userInput != "resuma" || len(blocks) != 1 || !strings.Contains(blocks[0].text, "enviado em 2 parte(s)") {
+		t.Errorf("Resultado inesperado: %q / %+v", userInput, blocks)
+	}
+	if len(cli.primingMessages) != 4 || cli.primingMessages[0].Role != "user" || cli.primingMessages[1].Role != "assistant" ||
+		!strings.Contains(cli.primingMessages[2].Content, "Parte 2 de 2") {
+		t.Errorf("Mensagens de preparação inesperadas: %+v", cli.primingMessages)
+	}
+
+	if _, err := extractFileRequests("@file --mode chunked --lines 1:2 " + path); err == nil {
+		t.Error("Esperado erro ao combinar --mode chunked com --lines")
+	}
+}
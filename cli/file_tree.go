@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	defaultTreeDepth    = 3
+	maxTreeEntries      = 500
+	maxTreeIncludeFiles = 20
+)
+
+// defaultTreeIgnores são diretórios que nunca entram na listagem de @file --tree
+var defaultTreeIgnores = []string{".git", "node_modules", "vendor", "dist", "build", "target", "__pycache__", ".idea", ".vscode"}
+
+// treeOptions são as opções de @file --tree
+type treeOptions struct {
+	depth   int
+	include []string
+}
+
+// fileTree é o resultado da varredura de um diretório: a listagem formatada e os arquivos que
+// correspondem aos padrões de --include, em ordem alfabética
+type fileTree struct {
+	listing  string
+	included []string
+	omitted  int
+}
+
+// ignoreRule é um padrão lido do .gitignore da raiz. Padrões terminados em "/" valem só para diretórios.
+type ignoreRule struct {
+	pattern string
+	dirOnly bool
+}
+
+// loadIgnoreRules lê os padrões simples do .gitignore do diretório (negações com "!" não são suportadas)
+func loadIgnoreRules(root string) []ignoreRule {
+	rules := make([]ignoreRule, 0, len(defaultTreeIgnores))
+	for _, name := range defaultTreeIgnores {
+		rules = append(rules, ignoreRule{pattern: name, dirOnly: true})
+	}
+
+	f, err := os.Open(filepath.Join(root, ".gitignore"))
+	if err != nil {
+		return rules
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		rule := ignoreRule{pattern: strings.TrimPrefix(line, "/")}
+		if strings.HasSuffix(rule.pattern, "/") {
+			rule.dirOnly = true
+			rule.pattern = strings.TrimSuffix(rule.pattern, "/")
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// isIgnored indica se o caminho (relativo à raiz) corresponde a alguma regra de ignore
+func isIgnored(rules []ignoreRule, relPath string, isDir bool) bool {
+	name := filepath.Base(relPath)
+	for _, rule := range rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if ok, _ := filepath.Match(rule.pattern, name); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(rule.pattern, filepath.ToSlash(relPath)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesInclude indica se o nome do arquivo corresponde a algum dos padrões de --include
+func matchesInclude(patterns []string, relPath string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, filepath.Base(relPath)); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, filepath.ToSlash(relPath)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// buildFileTree percorre o diretório até a profundidade informada, respeitando os ignores, e monta
+// a listagem em formato de árvore
+func buildFileTree(root string, opts treeOptions) (fileTree, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return fileTree{}, err
+	}
+	if !info.IsDir() {
+		return fileTree{}, fmt.Errorf("'%s' não é um diretório", root)
+	}
+	if opts.depth <= 0 {
+		opts.depth = defaultTreeDepth
+	}
+
+	var b strings.Builder
+	var tree fileTree
+	entries := 0
+	rules := loadIgnoreRules(root)
+	b.WriteString(filepath.Base(filepath.Clean(root)) + "/\n")
+
+	var walk func(dir, rel, prefix string, level int)
+	walk = func(dir, rel, prefix string, level int) {
+		children, err := os.ReadDir(dir)
+		if err != nil {
+			return
+		}
+		visible := children[:0]
+		for _, child := range children {
+			if !isIgnored(rules, filepath.Join(rel, child.Name()), child.IsDir()) {
+				visible = append(visible, child)
+			}
+		}
+		sort.Slice(visible, func(i, j int) bool { return visible[i].Name() < visible[j].Name() })
+
+		for i, child := range visible {
+			childRel := filepath.Join(rel, child.Name())
+			if !child.IsDir() && len(opts.include) > 0 && matchesInclude(opts.include, childRel) {
+				tree.included = append(tree.included, childRel)
+			}
+			if entries >= maxTreeEntries {
+				tree.omitted++
+				if child.IsDir() && level < opts.depth {
+					walk(filepath.Join(dir, child.Name()), childRel, prefix, level+1)
+				}
+				continue
+			}
+			entries++
+
+			connector, nextPrefix := "├── ", prefix+"│   "
+			if i == len(visible)-1 {
+				connector, nextPrefix = "└── ", prefix+"    "
+			}
+			name := child.Name()
+			if child.IsDir() {
+				name += "/"
+			}
+			b.WriteString(prefix + connector + name + "\n")
+
+			if child.IsDir() && level < opts.depth {
+				walk(filepath.Join(dir, child.Name()), childRel, nextPrefix, level+1)
+			}
+		}
+	}
+	walk(root, "", "", 1)
+
+	if tree.omitted > 0 {
+		fmt.Fprintf(&b, "... (%d entradas omitidas)\n", tree.omitted)
+	}
+	tree.listing = b.String()
+	return tree, nil
+}
+
+// formatTreeContext formata a listagem do diretório para o contexto
+func formatTreeContext(root string, depth int, listing string) string {
+	if depth <= 0 {
+		depth = defaultTreeDepth
+	}
+	return fmt.Sprintf("\nEstrutura do Diretório (%s, profundidade %d):\n```\n%s```\n", root, depth, listing)
+}
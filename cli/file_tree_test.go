@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuildFileTree(t *testing.T) {
+	root := t.TempDir()
+	for _, f := range []string{"go.mod", "main.go", "cli/cli.go", "cli/deep/a/b.go", "node_modules/x.js", "app.log"} {
+		path := filepath.Join(root, f)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte("conteúdo"), 0644)
+	}
+	os.WriteFile(filepath.Join(root, ".gitignore"), []byte("*.log\n"), 0644)
+
+	tree, err := buildFileTree(root, treeOptions{depth: 2, include: []string{"*.go"}})
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	for _, want := range []string{"├── cli/", "│   ├── cli.go", "│   └── deep/", "└── main.go"} {
+		if !strings.Contains(tree.listing, want) {
+			t.Errorf("Listagem deveria conter %q:\n%s", want, tree.listing)
+		}
+	}
+	for _, unwanted := range []string{"node_modules", "app.log", "b.go"} {
+		if strings.Contains(tree.listing, unwanted) {
+			t.Errorf("Listagem não deveria conter %q:\n%s", unwanted, tree.listing)
+		}
+	}
+	if len(tree.included) != 2 || tree.included[0] != filepath.Join("cli", "cli.go") || tree.included[1] != "main.go" {
+		t.Errorf("Arquivos incluídos inesperados: %v", tree.included)
+	}
+}
+
+func TestExtractFileRequests_tree(t *testing.T) {
+	input := `veja @file --tree ./src --depth 2 --include "*.go,go.mod" e explique`
+	requests, err := extractFileRequests(input)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if len(requests) != 1 || !requests[0].tree || requests[0].path != "./src" || requests[0].depth != 2 || len(requests[0].include) != 2 {
+		t.Errorf("Requisição inesperada: %+v", requests)
+	}
+	if cleaned := removeAllFileCommands(input); cleaned != "veja e explique" {
+		t.Errorf("Entrada limpa inesperada: '%s'", cleaned)
+	}
+
+	if _, err := extractFileRequests("@file main.go --depth 2"); err == nil {
+		t.Error("Esperado erro ao usar --depth sem --tree")
+	}
+}
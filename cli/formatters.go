@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/diillson/chatcli/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultFormattersFile é o arquivo de formatadores usado quando CHATCLI_FORMATTERS_FILE não está definido
+	defaultFormattersFile = "~/.chatcli/formatters.json"
+	// formatterTimeout é o tempo limite de cada execução de um formatador
+	formatterTimeout = 10 * time.Second
+)
+
+// loadFormatters lê o arquivo de formatadores, um objeto JSON que associa a linguagem do bloco de
+// código ao comando que o formata pela entrada padrão, como {"go": "gofmt", "js": "prettier --parser babel"}.
+// Um arquivo inexistente resulta em nenhum formatador.
+func loadFormatters(path string) (map[string][]string, error) {
+	if path == "" {
+		path = defaultFormattersFile
+	}
+	expanded, err := utils.ExpandPath(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(expanded)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao ler os formatadores em %s: %w", expanded, err)
+	}
+
+	var raw map[string]string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar os formatadores em %s: %w", expanded, err)
+	}
+	formatters := make(map[string][]string, len(raw))
+	for lang, command := range raw {
+		args, err := parseFields(command)
+		if err != nil || len(args) == 0 {
+			return nil, fmt.Errorf("comando inválido para a linguagem %s em %s", lang, expanded)
+		}
+		formatters[strings.ToLower(lang)] = args
+	}
+	return formatters, nil
+}
+
+// loadFormattersFromEnv carrega os formatadores de CHATCLI_FORMATTERS_FILE ou do arquivo padrão
+func (cli *ChatCLI) loadFormattersFromEnv() {
+	formatters, err := loadFormatters(os.Getenv("CHATCLI_FORMATTERS_FILE"))
+	if err != nil {
+		cli.logger.Warn("Formatadores ignorados", zap.Error(err))
+		fmt.Println("Aviso: formatadores ignorados:", err)
+	}
+	cli.formatters = formatters
+}
+
+// formatCodeBlocks substitui o conteúdo de cada bloco de código fechado pelo resultado de format,
+// mantendo o texto fora dos blocos intacto. Blocos indentados (dentro de listas) e sem fechamento
+// não são alterados.
+func formatCodeBlocks(text string, format func(lang, content string) (string, bool)) string {
+	lines := strings.Split(text, "\n")
+	var out []string
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		out = append(out, line)
+		if !strings.HasPrefix(line, "```") {
+			continue
+		}
+		end := -1
+		for j := i + 1; j < len(lines); j++ {
+			if strings.TrimSpace(lines[j]) == "```" {
+				end = j
+				break
+			}
+		}
+		if end == -1 {
+			out = append(out, lines[i+1:]...)
+			break
+		}
+
+		lang, _ := parseFenceInfo(strings.TrimPrefix(line, "```"))
+		content := strings.Join(lines[i+1:end], "\n")
+		if formatted, ok := format(strings.ToLower(lang), content); ok {
+			content = strings.TrimRight(formatted, "\n")
+		}
+		if content != "" || end > i+1 {
+			out = append(out, content)
+		}
+		out = append(out, lines[end])
+		i = end
+	}
+	return strings.Join(out, "\n")
+}
+
+// applyFormatters passa os blocos de código da resposta pelos formatadores configurados. Se um
+// formatador falhar, o bloco original é mantido.
+func (cli *ChatCLI) applyFormatters(response string) string {
+	if len(cli.formatters) == 0 {
+		return response
+	}
+	return formatCodeBlocks(response, func(lang, content string) (string, bool) {
+		args, ok := cli.formatters[lang]
+		if !ok {
+			return "", false
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), formatterTimeout)
+		defer cancel()
+		cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+		cmd.Stdin = strings.NewReader(content + "\n")
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		output, err := cmd.Output()
+		if err != nil || len(bytes.TrimSpace(output)) == 0 {
+			cli.logger.Warn("Formatador falhou; bloco mantido sem formatação",
+				zap.String("linguagem", lang), zap.String("comando", args[0]),
+				zap.String("stderr", strings.TrimSpace(stderr.String())), zap.Error(err))
+			return "", false
+		}
+		return string(output), true
+	})
+}
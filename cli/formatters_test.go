@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestFormatCodeBlocks(t *testing.T) {
+	text := "Veja:\n```go\nfunc  main( ) {}\n```\nTexto ```go no meio.\n```python\nx=1\n```\n```go\nsem fechamento"
+	formatted := formatCodeBlocks(text, func(lang, content string) (string, bool) {
+		if lang != "go" {
+			return "", false
+		}
+		return "func main() {}\n", true
+	})
+	expected := "Veja:\n```go\nfunc main() {}\n```\nTexto ```go no meio.\n```python\nx=1\n```\n```go\nsem fechamento"
+	if formatted != expected {
+		t.Errorf("Resultado inesperado:\n%s", formatted)
+	}
+}
+
+func TestApplyFormatters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "formatters.json")
+	os.WriteFile(path, []byte(`{"TXT": "tr a-z A-Z", "bad": "comando-que-nao-existe"}`), 0644)
+	formatters, err := loadFormatters(path)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	cli := &ChatCLI{logger: zap.NewNop(), formatters: formatters}
+	response := cli.applyFormatters("texto\n```txt\nabc\n```\n```bad\nintacto\n```")
+	if !strings.Contains(response, "```txt\nABC\n```") || !strings.HasPrefix(response, "texto\n") {
+		t.Errorf("Bloco txt não formatado:\n%s", response)
+	}
+	if !strings.Contains(response, "```bad\nintacto\n```") {
+		t.Errorf("Um formatador com falha deveria manter o bloco:\n%s", response)
+	}
+
+	if formatters, err := loadFormatters(filepath.Join(t.TempDir(), "nao-existe.json")); err != nil || formatters != nil {
+		t.Errorf("Arquivo inexistente deveria resultar em nenhum formatador: %v, %v", formatters, err)
+	}
+}
@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/diillson/chatcli/llm/client"
+	"github.com/diillson/chatcli/models"
+	"go.uber.org/zap"
+)
+
+// generationFlags mapeia as flags de /switch para os parâmetros de geração correspondentes
+var generationFlags = map[string]func(p *models.GenerationParams) **float64{
+	"--temperature":       func(p *models.GenerationParams) **float64 { return &p.Temperature },
+	"--top-p":             func(p *models.GenerationParams) **float64 { return &p.TopP },
+	"--presence-penalty":  func(p *models.GenerationParams) **float64 { return &p.PresencePenalty },
+	"--frequency-penalty": func(p *models.GenerationParams) **float64 { return &p.FrequencyPenalty },
+}
+
+// generationEnvKeys associa as variáveis de ambiente que definem os parâmetros de geração padrão
+// aos campos correspondentes, na ordem em que são gravadas por /switch --save
+var generationEnvKeys = []struct {
+	name  string
+	field func(p *models.GenerationParams) **float64
+}{
+	{"CHATCLI_TEMPERATURE", generationFlags["--temperature"]},
+	{"CHATCLI_TOP_P", generationFlags["--top-p"]},
+	{"CHATCLI_PRESENCE_PENALTY", generationFlags["--presence-penalty"]},
+	{"CHATCLI_FREQUENCY_PENALTY", generationFlags["--frequency-penalty"]},
+}
+
+// maxTokensFlag e maxTokensEnv definem o limite de tokens da resposta, o único parâmetro inteiro
+const (
+	maxTokensFlag = "--max-tokens"
+	maxTokensEnv  = "CHATCLI_MAX_TOKENS"
+)
+
+// generationParamsFromEnv lê os parâmetros de geração padrão das variáveis CHATCLI_TEMPERATURE,
+// CHATCLI_TOP_P, CHATCLI_PRESENCE_PENALTY, CHATCLI_FREQUENCY_PENALTY e CHATCLI_MAX_TOKENS
+func generationParamsFromEnv() (models.GenerationParams, error) {
+	var params models.GenerationParams
+	if raw := strings.TrimSpace(os.Getenv(maxTokensEnv)); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil {
+			return models.GenerationParams{}, fmt.Errorf("valor inválido para %s: %s", maxTokensEnv, raw)
+		}
+		params.MaxTokens = &value
+	}
+	for _, key := range generationEnvKeys {
+		raw := strings.TrimSpace(os.Getenv(key.name))
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return models.GenerationParams{}, fmt.Errorf("valor inválido para %s: %s", key.name, raw)
+		}
+		*key.field(&params) = &value
+	}
+	if err := params.Validate(); err != nil {
+		return models.GenerationParams{}, err
+	}
+	return params, nil
+}
+
+// parseGenerationFlags extrai as flags de parâmetros de geração dos argumentos, aplicando-as sobre
+// os parâmetros atuais. O valor "default" remove o parâmetro, voltando ao padrão do provedor.
+// Retorna os novos parâmetros, os argumentos restantes e se alguma flag foi encontrada.
+func parseGenerationFlags(args []string, current models.GenerationParams) (models.GenerationParams, []string, bool, error) {
+	params := current
+	var rest []string
+	found := false
+
+	for i := 0; i < len(args); i++ {
+		if args[i] == maxTokensFlag {
+			if i+1 >= len(args) {
+				return current, nil, false, fmt.Errorf("valor ausente para %s", args[i])
+			}
+			found = true
+			raw := args[i+1]
+			i++
+			if strings.EqualFold(raw, "default") {
+				params.MaxTokens = nil
+				continue
+			}
+			value, err := strconv.Atoi(raw)
+			if err != nil {
+				return current, nil, false, fmt.Errorf("valor inválido para %s: %s", maxTokensFlag, raw)
+			}
+			params.MaxTokens = &value
+			continue
+		}
+
+		field, ok := generationFlags[args[i]]
+		if !ok {
+			rest = append(rest, args[i])
+			continue
+		}
+		if i+1 >= len(args) {
+			return current, nil, false, fmt.Errorf("valor ausente para %s", args[i])
+		}
+		found = true
+		raw := args[i+1]
+		i++
+
+		if strings.EqualFold(raw, "default") {
+			*field(&params) = nil
+			continue
+		}
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return current, nil, false, fmt.Errorf("valor inválido para %s: %s", args[i-1], raw)
+		}
+		*field(&params) = &value
+	}
+
+	if err := params.Validate(); err != nil {
+		return current, nil, false, err
+	}
+	return params, rest, found, nil
+}
+
+// applyGenerationParams repassa os parâmetros de geração da sessão ao cliente atual, avisando quando
+// o max_tokens excede o limite documentado do modelo e será reduzido pelo cliente
+func (cli *ChatCLI) applyGenerationParams() {
+	cli.applyResponseFormat()
+	if cli.generationParams.MaxTokens != nil && cli.client != nil {
+		model := cli.client.GetModelName()
+		if limit := models.MaxOutputTokens(model); limit > 0 && *cli.generationParams.MaxTokens > limit {
+			fmt.Printf("Aviso: max_tokens=%d excede o limite de %d tokens de saída do modelo %s; será usado %d.\n",
+				*cli.generationParams.MaxTokens, limit, model, limit)
+		}
+	}
+	if configurable, ok := cli.client.(client.GenerationConfigurable); ok {
+		configurable.SetGenerationParams(cli.generationParams)
+		return
+	}
+	if !cli.generationParams.IsEmpty() {
+		cli.logger.Debug("O cliente atual não suporta parâmetros de geração; eles serão ignorados",
+			zap.String("provider", cli.provider))
+	}
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/diillson/chatcli/models"
+)
+
+func TestParseGenerationFlags(t *testing.T) {
+	params, rest, found, err := parseGenerationFlags(
+		[]string{"--temperature", "0.2", "--slugname", "abc", "--top-p", "0.9"}, models.GenerationParams{})
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if !found {
+		t.Error("Esperado encontrar flags de geração")
+	}
+	if params.Temperature == nil || *params.Temperature != 0.2 || params.TopP == nil || *params.TopP != 0.9 {
+		t.Errorf("Parâmetros inesperados: %s", params)
+	}
+	if len(rest) != 2 || rest[0] != "--slugname" || rest[1] != "abc" {
+		t.Errorf("Argumentos restantes inesperados: %v", rest)
+	}
+
+	// "default" remove o parâmetro
+	params, _, _, err = parseGenerationFlags([]string{"--temperature", "default"}, params)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if params.Temperature != nil || params.TopP == nil {
+		t.Errorf("Esperado remover apenas temperature, obteve: %s", params)
+	}
+
+	// Valores fora do intervalo ou inválidos retornam erro e preservam os parâmetros atuais
+	for _, args := range [][]string{{"--top-p", "1.5"}, {"--temperature", "quente"}, {"--temperature"}} {
+		got, _, _, err := parseGenerationFlags(args, params)
+		if err == nil {
+			t.Errorf("Esperado erro para %v", args)
+		}
+		if got.TopP != params.TopP {
+			t.Errorf("Parâmetros atuais não deveriam mudar para %v", args)
+		}
+	}
+}
+
+func TestParseGenerationFlagsMaxTokens(t *testing.T) {
+	params, _, found, err := parseGenerationFlags([]string{"--max-tokens", "2048"}, models.GenerationParams{})
+	if err != nil || !found || params.MaxTokens == nil || *params.MaxTokens != 2048 {
+		t.Fatalf("Parâmetros inesperados: %s (erro: %v)", params, err)
+	}
+
+	params, _, _, err = parseGenerationFlags([]string{"--max-tokens", "default"}, params)
+	if err != nil || params.MaxTokens != nil {
+		t.Errorf("Esperado remover max_tokens, obteve: %s (erro: %v)", params, err)
+	}
+
+	for _, args := range [][]string{{"--max-tokens", "0"}, {"--max-tokens", "1.5"}, {"--max-tokens"}} {
+		if _, _, _, err := parseGenerationFlags(args, params); err == nil {
+			t.Errorf("Esperado erro para %v", args)
+		}
+	}
+}
+
+func TestGenerationParamsFromEnv(t *testing.T) {
+	t.Setenv("CHATCLI_TEMPERATURE", "0.3")
+	t.Setenv("CHATCLI_TOP_P", "")
+	t.Setenv("CHATCLI_PRESENCE_PENALTY", "")
+	t.Setenv("CHATCLI_FREQUENCY_PENALTY", "")
+	t.Setenv("CHATCLI_MAX_TOKENS", "")
+
+	params, err := generationParamsFromEnv()
+	if err != nil || params.Temperature == nil || *params.Temperature != 0.3 || params.TopP != nil {
+		t.Errorf("Parâmetros inesperados: %s (erro: %v)", params, err)
+	}
+
+	t.Setenv("CHATCLI_TOP_P", "1.5")
+	if _, err := generationParamsFromEnv(); err == nil {
+		t.Error("Esperado erro para top_p fora do intervalo")
+	}
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/diillson/chatcli/utils"
+	"go.uber.org/zap"
+)
+
+// blameRequest representa um '@git blame <arquivo> [--lines INÍCIO:FIM]'
+type blameRequest struct {
+	path  string
+	start int
+	end   int
+}
+
+// extractBlameRequests extrai os comandos @git blame da entrada e retorna a entrada sem eles
+func extractBlameRequests(input string) ([]blameRequest, string, error) {
+	tokens, err := parseFields(input)
+	if err != nil {
+		return nil, input, err
+	}
+
+	var requests []blameRequest
+	var rest []string
+	for i := 0; i < len(tokens); i++ {
+		if !strings.EqualFold(tokens[i], "@git") || i+1 >= len(tokens) || tokens[i+1] != "blame" {
+			rest = append(rest, tokens[i])
+			continue
+		}
+		i++
+		if i+1 >= len(tokens) {
+			return nil, input, fmt.Errorf("comando @git blame sem caminho de arquivo")
+		}
+		i++
+		req := blameRequest{path: tokens[i]}
+		if i+2 < len(tokens) && tokens[i+1] == "--lines" {
+			ranges, err := parseLineRanges(tokens[i+2])
+			if err != nil {
+				return nil, input, err
+			}
+			if len(ranges) != 1 {
+				return nil, input, fmt.Errorf("@git blame aceita apenas um intervalo de linhas")
+			}
+			req.start, req.end = ranges[0].start, ranges[0].end
+			i += 2
+		}
+		requests = append(requests, req)
+	}
+	return requests, strings.Join(rest, " "), nil
+}
+
+// processGitBlameCommand adiciona ao contexto o blame dos arquivos pedidos com @git blame
+func (cli *ChatCLI) processGitBlameCommand(userInput string) (string, string) {
+	if !strings.Contains(strings.ToLower(userInput), "@git blame") {
+		return userInput, ""
+	}
+
+	requests, rest, err := extractBlameRequests(userInput)
+	if err != nil {
+		cli.logger.Error("Erro ao processar o comando @git blame", zap.Error(err))
+		fmt.Println("Erro no comando @git blame:", err)
+		return userInput, ""
+	}
+
+	var additionalContext string
+	for _, req := range requests {
+		blame, err := utils.GetFileBlame(req.path, req.start, req.end)
+		if err != nil {
+			cli.logger.Warn("Erro ao obter o blame", zap.String("path", req.path), zap.Error(err))
+			fmt.Println("Erro no comando @git blame:", err)
+			continue
+		}
+		header := req.path
+		if req.start > 0 {
+			header += " " + lineRange{start: req.start, end: req.end}.String()
+		}
+		additionalContext += fmt.Sprintf("\nGit Blame (%s):\n```\n%s```\n", header, blame)
+	}
+	return rest, additionalContext
+}
@@ -0,0 +1,20 @@
+package cli
+
+import "testing"
+
+func TestExtractBlameRequests(t *testing.T) {
+	requests, rest, err := extractBlameRequests("quem escreveu @git blame cli/cli.go --lines 40:60 e por quê?")
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if len(requests) != 1 || requests[0].path != "cli/cli.go" || requests[0].start != 40 || requests[0].end != 60 {
+		t.Errorf("Requisições inesperadas: %+v", requests)
+	}
+	if rest != "quem escreveu e por quê?" {
+		t.Errorf("Entrada restante inesperada: %q", rest)
+	}
+
+	if _, _, err := extractBlameRequests("@git blame"); err == nil {
+		t.Error("Esperado erro para @git blame sem arquivo")
+	}
+}
@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diillson/chatcli/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultGitHubComments é a quantidade de comentários recentes incluídos quando --comments não é informado
+	defaultGitHubComments = 5
+	// maxGitHubBodyChars limita o texto da descrição incluído no contexto
+	maxGitHubBodyChars = 4000
+	// maxGitHubCommentChars limita o texto de cada comentário incluído no contexto
+	maxGitHubCommentChars = 1500
+)
+
+// githubRequest representa um '@github owner/repo#123 [--comments N] [--diff]'
+type githubRequest struct {
+	ref      utils.GitHubRef
+	comments int
+	diff     bool
+}
+
+// extractGitHubRequests extrai os comandos @github da entrada e retorna a entrada sem eles
+func extractGitHubRequests(input string) ([]githubRequest, string, error) {
+	tokens, err := parseFields(input)
+	if err != nil {
+		return nil, input, err
+	}
+
+	var requests []githubRequest
+	var rest []string
+	for i := 0; i < len(tokens); i++ {
+		if !strings.EqualFold(tokens[i], "@github") {
+			rest = append(rest, tokens[i])
+			continue
+		}
+		if i+1 >= len(tokens) {
+			return nil, input, fmt.Errorf("comando @github sem referência (use owner/repo#123)")
+		}
+		i++
+		ref, err := utils.ParseGitHubRef(tokens[i])
+		if err != nil {
+			return nil, input, err
+		}
+		req := githubRequest{ref: ref, comments: defaultGitHubComments}
+		for i+1 < len(tokens) {
+			switch tokens[i+1] {
+			case "--diff":
+				req.diff = true
+				i++
+				continue
+			case "--comments":
+				if i+2 >= len(tokens) {
+					return nil, input, fmt.Errorf("valor ausente para --comments")
+				}
+				n, err := strconv.Atoi(tokens[i+2])
+				if err != nil || n < 0 || n > 100 {
+					return nil, input, fmt.Errorf("valor inválido para --comments: %s (use de 0 a 100)", tokens[i+2])
+				}
+				req.comments = n
+				i += 2
+				continue
+			}
+			break
+		}
+		requests = append(requests, req)
+	}
+	return requests, strings.Join(rest, " "), nil
+}
+
+// processGitHubCommand adiciona ao contexto as issues e pull requests pedidos com @github
+func (cli *ChatCLI) processGitHubCommand(userInput string) (string, string) {
+	if !strings.Contains(strings.ToLower(userInput), "@github") {
+		return userInput, ""
+	}
+
+	requests, rest, err := extractGitHubRequests(userInput)
+	if err != nil {
+		cli.logger.Error("Erro ao processar o comando @github", zap.Error(err))
+		fmt.Println("Erro no comando @github:", err)
+		return userInput, ""
+	}
+
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		fmt.Println("GITHUB_TOKEN não definido: apenas repositórios públicos podem ser consultados.")
+	}
+	github := utils.NewGitHubClient(utils.NewHTTPClient(cli.logger, 30*time.Second), os.Getenv("GITHUB_API_URL"), token)
+
+	var additionalContext string
+	for _, req := range requests {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		issue, err := github.FetchIssue(ctx, req.ref, req.comments, req.diff)
+		cancel()
+		if err != nil {
+			cli.logger.Warn("Erro ao consultar o GitHub", zap.String("ref", req.ref.String()), zap.Error(err))
+			fmt.Printf("Erro no comando @github %s: %v\n", req.ref, err)
+			continue
+		}
+		if req.diff && !issue.IsPullRequest {
+			fmt.Printf("%s é uma issue; --diff ignorado.\n", req.ref)
+		}
+		additionalContext += cli.tagSource(req.ref.String()) + formatGitHubIssue(issue, cli.commandOutputLimit(commandOptions{}))
+	}
+	return rest, additionalContext
+}
+
+// formatGitHubIssue resume a issue ou pull request para o contexto, limitando a descrição, os
+// comentários e o diff
+func formatGitHubIssue(issue *utils.GitHubIssue, diffLimit int) string {
+	kind := "Issue"
+	if issue.IsPullRequest {
+		kind = "Pull request"
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("\n%s %s: %s\n", kind, issue.Ref, issue.Title))
+	builder.WriteString(fmt.Sprintf("Estado: %s | Autor: %s | %s\n", issue.State, issue.Author, issue.URL))
+	if body := strings.TrimSpace(issue.Body); body != "" {
+		builder.WriteString("\n" + trimText(body, maxGitHubBodyChars) + "\n")
+	}
+
+	if len(issue.Comments) > 0 {
+		builder.WriteString(fmt.Sprintf("\nÚltimos %d de %d comentários:\n", len(issue.Comments), issue.TotalComments))
+		for _, c := range issue.Comments {
+			builder.WriteString(fmt.Sprintf("- %s (%s): %s\n", c.Author, c.CreatedAt.Format("2006-01-02"),
+				trimText(strings.TrimSpace(c.Body), maxGitHubCommentChars)))
+		}
+	}
+
+	if issue.Diff != "" {
+		builder.WriteString(fmt.Sprintf("\nDiff:\n```diff\n%s\n```\n", strings.TrimRight(truncateOutput(issue.Diff, diffLimit), "\n")))
+	}
+	return builder.String()
+}
+
+// trimText limita o texto ao número de caracteres informado, indicando o corte
+func trimText(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return string(runes[:max]) + " [...]"
+}
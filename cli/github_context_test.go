@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/diillson/chatcli/utils"
+)
+
+func TestExtractGitHubRequests(t *testing.T) {
+	requests, rest, err := extractGitHubRequests("resuma @github octo/app#7 --diff --comments 2 e @github octo/app#8 por favor")
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if rest != "resuma e por favor" {
+		t.Errorf("Entrada restante inesperada: %q", rest)
+	}
+	if len(requests) != 2 || !requests[0].diff || requests[0].comments != 2 || requests[1].comments != defaultGitHubComments {
+		t.Errorf("Requisições inesperadas: %+v", requests)
+	}
+
+	for _, input := range []string{"@github", "@github octo/app", "@github octo/app#1 --comments x"} {
+		if _, _, err := extractGitHubRequests(input); err == nil {
+			t.Errorf("Esperado erro para %q", input)
+		}
+	}
+}
+
+func TestFormatGitHubIssue(t *testing.T) {
+	issue := &utils.GitHubIssue{
+		Ref:           utils.GitHubRef{Owner: "octo", Repo: "app", Number: 7},
+		Title:         "Corrige o login",
+		Body:          strings.Repeat("a", maxGitHubBodyChars+10),
+		IsPullRequest: true,
+		TotalComments: 9,
+		Comments:      []utils.GitHubComment{{Author: "bia", Body: "aprovado"}},
+		Diff:          strings.Repeat("+linha\n", 100),
+	}
+	got := formatGitHubIssue(issue, 200)
+	for _, expected := range []string{"Pull request octo/app#7: Corrige o login", "[...]", "Últimos 1 de 9 comentários", "bia", "bytes truncados"} {
+		if !strings.Contains(got, expected) {
+			t.Errorf("Esperado %q no contexto:\n%s", expected, got)
+		}
+	}
+}
@@ -2,28 +2,51 @@ package cli
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
-	"go.uber.org/zap"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
-	"time"
+
+	"github.com/diillson/chatcli/utils"
+	"go.uber.org/zap"
 )
 
-const defaultMaxHistorySize = 50 * 1024 * 1024 // 50MB
+const (
+	defaultMaxHistorySize = 50 * 1024 * 1024 // 50MB
+	// defaultInputHistoryFile guarda as entradas digitadas entre sessões, para as setas e o Ctrl+R
+	defaultInputHistoryFile = "~/.chatcli/input_history"
+	// legacyHistoryFile é o arquivo usado pelas versões anteriores, no diretório atual; ele só é lido
+	// enquanto o novo arquivo não existir
+	legacyHistoryFile = ".chatcli_history"
+)
 
 type HistoryManager struct {
 	historyFile    string
 	commandHistory []string
 	logger         *zap.Logger
 	maxHistorySize int64
+	// excludeSecrets impede que entradas com segredos sejam gravadas no arquivo
+	excludeSecrets bool
+	// locked indica que o arquivo está criptografado e não pôde ser lido, e por isso não é sobrescrito
+	locked bool
 }
 
 func NewHistoryManager(logger *zap.Logger) *HistoryManager {
+	historyFile := utils.GetEnvOrDefault("CHATCLI_INPUT_HISTORY_FILE", defaultInputHistoryFile)
+	if expanded, err := utils.ExpandPath(historyFile); err == nil {
+		historyFile = expanded
+	} else {
+		logger.Warn("Não foi possível expandir o caminho do histórico de entradas", zap.Error(err))
+	}
+	excludeSecrets, err := strconv.ParseBool(utils.GetEnvOrDefault("CHATCLI_HISTORY_EXCLUDE_SECRETS", "true"))
 	return &HistoryManager{
-		historyFile:    ".chatcli_history",
+		historyFile:    historyFile,
 		logger:         logger,
 		maxHistorySize: getMaxHistorySizeFromEnv(),
+		excludeSecrets: err != nil || excludeSecrets,
 	}
 }
 
@@ -71,60 +94,104 @@ func parseSize(sizeStr string) (int64, error) {
 	return size * multiplier, nil
 }
 
-// LoadHistory carrega o histórico do arquivo
+// LoadHistory carrega o histórico do arquivo. Sem ele, usa o arquivo das versões anteriores no
+// diretório atual, que passa a ser gravado no novo local ao final da sessão. Um arquivo criptografado
+// que não pôde ser lido com CHATCLI_ENCRYPTION_KEY não é sobrescrito ao final da sessão.
 func (hm *HistoryManager) LoadHistory() ([]string, error) {
-	f, err := os.Open(hm.historyFile)
-	if err != nil {
+	history, err := readHistoryFile(hm.historyFile)
+	hm.locked = errors.Is(err, utils.ErrEncryptionKeyMissing) || errors.Is(err, utils.ErrEncryptionKeyWrong)
+	if os.IsNotExist(err) {
+		history, err = readHistoryFile(legacyHistoryFile)
 		if os.IsNotExist(err) {
 			return nil, nil // Nenhum histórico para carregar
 		}
+	}
+	if err != nil {
 		hm.logger.Warn("Não foi possível carregar o histórico:", zap.Error(err))
 		return nil, err
 	}
-	defer f.Close()
-
-	var history []string
-	scanner := bufio.NewScanner(f)
-	for scanner.Scan() {
-		line := scanner.Text()
-		history = append(history, line)
-	}
+	return history, nil
+}
 
-	if err := scanner.Err(); err != nil {
-		hm.logger.Warn("Erro ao ler o histórico:", zap.Error(err))
+// readHistoryFile lê uma entrada por linha, descriptografando o arquivo se necessário
+func readHistoryFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
 		return nil, err
 	}
+	if utils.IsEncrypted(data) {
+		if data, err = utils.DecryptData(data, os.Getenv("CHATCLI_ENCRYPTION_KEY")); err != nil {
+			return nil, err
+		}
+	}
 
-	return history, nil
+	var history []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		history = append(history, scanner.Text())
+	}
+	return history, scanner.Err()
 }
 
-// SaveHistory salva o histórico no arquivo e faz backup se o tamanho exceder o limite
+// SaveHistory regrava o arquivo com o histórico da sessão (que já inclui o carregado no início),
+// descartando as entradas mais antigas além de HISTORY_MAX_SIZE e, se configurado, as que contêm segredos.
+// Com CHATCLI_ENCRYPTION_KEY definida, o arquivo é gravado criptografado.
 func (hm *HistoryManager) SaveHistory(commandHistory []string) error {
-	// Verificar o tamanho do arquivo de histórico
-	fileInfo, err := os.Stat(hm.historyFile)
-	if err == nil && fileInfo.Size() >= hm.maxHistorySize {
-		// Fazer backup do arquivo de histórico
-		backupFile := fmt.Sprintf("%s.bak-%d", hm.historyFile, time.Now().Unix())
-		err := os.Rename(hm.historyFile, backupFile)
-		if err != nil {
-			hm.logger.Warn("Não foi possível fazer backup do histórico:", zap.Error(err))
+	if hm.locked {
+		return fmt.Errorf("o histórico em %s está criptografado e não pôde ser lido; ele não foi sobrescrito", hm.historyFile)
+	}
+	entries := hm.persistableEntries(commandHistory)
+
+	if err := os.MkdirAll(filepath.Dir(hm.historyFile), 0700); err != nil {
+		hm.logger.Warn("Não foi possível criar o diretório do histórico:", zap.Error(err))
+		return err
+	}
+	data := strings.Join(entries, "\n")
+	if data != "" {
+		data += "\n"
+	}
+	content := []byte(data)
+	if key := os.Getenv("CHATCLI_ENCRYPTION_KEY"); key != "" {
+		var err error
+		if content, err = utils.EncryptData(content, key); err != nil {
+			hm.logger.Warn("Não foi possível criptografar o histórico:", zap.Error(err))
 			return err
 		}
-		hm.logger.Info("Backup do histórico criado:", zap.String("backupFile", backupFile))
 	}
-
-	// Abrir o arquivo de histórico para escrita
-	f, err := os.OpenFile(hm.historyFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
-	if err != nil {
+	if err := utils.WriteFileAtomic(hm.historyFile, content, 0600); err != nil {
 		hm.logger.Warn("Não foi possível salvar o histórico:", zap.Error(err))
 		return err
 	}
-	defer f.Close()
+	return nil
+}
 
-	// Escrever o histórico no arquivo
+// persistableEntries filtra as entradas gravadas e mantém as mais recentes que cabem no tamanho máximo
+func (hm *HistoryManager) persistableEntries(commandHistory []string) []string {
+	var entries []string
+	skipped := 0
 	for _, cmd := range commandHistory {
-		fmt.Fprintln(f, cmd)
+		if strings.TrimSpace(cmd) == "" || strings.Contains(cmd, "\n") {
+			continue
+		}
+		if hm.excludeSecrets {
+			if _, found := utils.ScrubSecrets(cmd, scrubAllowlist()); len(found) > 0 {
+				skipped++
+				continue
+			}
+		}
+		entries = append(entries, cmd)
+	}
+	if skipped > 0 {
+		hm.logger.Info("Entradas com segredos não foram gravadas no histórico", zap.Int("quantidade", skipped))
 	}
 
-	return nil
+	var size int64
+	for i := len(entries) - 1; i >= 0; i-- {
+		size += int64(len(entries[i])) + 1
+		if size > hm.maxHistorySize {
+			return entries[i+1:]
+		}
+	}
+	return entries
 }
@@ -1,19 +1,22 @@
 package cli
 
 import (
+	"errors"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/diillson/chatcli/utils"
 	"go.uber.org/zap"
 )
 
 func TestHistoryManager_LoadAndSaveHistory(t *testing.T) {
+	t.Setenv("CHATCLI_INPUT_HISTORY_FILE", filepath.Join(t.TempDir(), "chatcli", "input_history"))
 	logger, _ := zap.NewDevelopment()
 	hm := NewHistoryManager(logger)
 
-	// Remover arquivo de histórico anterior
-	os.Remove(hm.historyFile)
-
 	commands := []string{"/help", "/exit"}
 	err := hm.SaveHistory(commands)
 	if err != nil {
@@ -28,4 +31,94 @@ func TestHistoryManager_LoadAndSaveHistory(t *testing.T) {
 	if len(loadedCommands) != len(commands) {
 		t.Errorf("Esperado %d comandos, obtido %d", len(commands), len(loadedCommands))
 	}
+
+	// Salvar de novo o histórico carregado não deve duplicar as entradas
+	if err := hm.SaveHistory(append(loadedCommands, "/status")); err != nil {
+		t.Fatal(err)
+	}
+	if loadedCommands, _ = hm.LoadHistory(); !reflect.DeepEqual(loadedCommands, []string{"/help", "/exit", "/status"}) {
+		t.Errorf("Histórico inesperado após a segunda gravação: %q", loadedCommands)
+	}
+}
+
+func TestHistoryManager_encryptedHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "input_history")
+	t.Setenv("CHATCLI_INPUT_HISTORY_FILE", path)
+	t.Setenv("CHATCLI_ENCRYPTION_KEY", "senha-de-teste")
+	hm := NewHistoryManager(zap.NewNop())
+
+	if err := hm.SaveHistory([]string{"/help", "explique o deploy"}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !utils.IsEncrypted(data) || strings.Contains(string(data), "deploy") {
+		t.Fatalf("Esperado histórico criptografado, obtido %q", data)
+	}
+	if history, err := hm.LoadHistory(); err != nil || !reflect.DeepEqual(history, []string{"/help", "explique o deploy"}) {
+		t.Errorf("Histórico inesperado: %q (%v)", history, err)
+	}
+
+	// Sem a chave, o histórico não é carregado nem sobrescrito
+	t.Setenv("CHATCLI_ENCRYPTION_KEY", "")
+	hm = NewHistoryManager(zap.NewNop())
+	if _, err := hm.LoadHistory(); !errors.Is(err, utils.ErrEncryptionKeyMissing) {
+		t.Errorf("Esperado ErrEncryptionKeyMissing, obtido %v", err)
+	}
+	if err := hm.SaveHistory([]string{"/exit"}); err == nil {
+		t.Error("O histórico criptografado não deveria ser sobrescrito")
+	}
+	if after, _ := os.ReadFile(path); string(after) != string(data) {
+		t.Error("O arquivo criptografado foi alterado")
+	}
+}
+
+func TestHistoryManager_persistableEntries(t *testing.T) {
+	hm := &HistoryManager{logger: zap.NewNop(), maxHistorySize: 20, excludeSecrets: true}
+	commands := []string{
+		"primeira entrada antiga",
+		"export OPENAI_API_KEY=sk-abcdefghijklmnopqrstuvwxyz123456",
+		"",
+		"/status",
+		"@git resuma",
+	}
+	if got := hm.persistableEntries(commands); !reflect.DeepEqual(got, []string{"/status", "@git resuma"}) {
+		t.Errorf("Entradas inesperadas: %q", got)
+	}
+
+	hm.excludeSecrets = false
+	hm.maxHistorySize = 1024
+	if got := hm.persistableEntries(commands); len(got) != 4 || !strings.HasPrefix(got[1], "export") {
+		t.Errorf("Sem exclusão, o segredo deveria ser mantido: %q", got)
+	}
+}
+
+func TestHistoryManager_legacyFile(t *testing.T) {
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	t.Setenv("CHATCLI_INPUT_HISTORY_FILE", filepath.Join(dir, "novo", "input_history"))
+	if err := os.WriteFile(legacyHistoryFile, []byte("/help\n@git\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	hm := NewHistoryManager(zap.NewNop())
+	if history, err := hm.LoadHistory(); err != nil || len(history) != 2 {
+		t.Errorf("Esperado o histórico do arquivo antigo, obtido %q (%v)", history, err)
+	}
+}
+
+func TestMatchInputHistory(t *testing.T) {
+	history := []string{"@git resuma", "/status", "@git explique o diff", "@git resuma", "/history search git"}
+	expected := []string{"@git resuma", "@git explique o diff"}
+	if got := matchInputHistory(history, "GIT"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Resultados inesperados: %q", got)
+	}
 }
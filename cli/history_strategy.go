@@ -0,0 +1,203 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/diillson/chatcli/models"
+)
+
+const (
+	// historyStrategyFull envia todo o histórico a cada requisição
+	historyStrategyFull = "full"
+	// historyStrategyLastN envia apenas as últimas N trocas (e os resumos anteriores a elas)
+	historyStrategyLastN = "last-n"
+	// historyStrategySummarize resume as trocas mais antigas quando o histórico passa do limite
+	historyStrategySummarize = "summarize"
+	// defaultHistoryLastN é a quantidade de trocas mantidas por last-n quando N não é informado
+	defaultHistoryLastN = 10
+)
+
+// historyStrategy define como o histórico é reduzido antes de ser enviado ao provedor.
+// O histórico da sessão é mantido completo; apenas a requisição é afetada.
+type historyStrategy struct {
+	name  string
+	lastN int
+}
+
+// String descreve a estratégia para o usuário
+func (s historyStrategy) String() string {
+	switch s.name {
+	case historyStrategyLastN:
+		return fmt.Sprintf("%s (últimas %d trocas)", s.name, s.lastN)
+	case "":
+		return historyStrategyFull
+	default:
+		return s.name
+	}
+}
+
+// parseHistoryStrategy interpreta o nome da estratégia e, para last-n, a quantidade de trocas
+func parseHistoryStrategy(name, n string) (historyStrategy, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	switch name {
+	case "", historyStrategyFull:
+		return historyStrategy{name: historyStrategyFull}, nil
+	case historyStrategySummarize:
+		return historyStrategy{name: historyStrategySummarize}, nil
+	case historyStrategyLastN:
+		lastN := defaultHistoryLastN
+		if n != "" {
+			parsed, err := strconv.Atoi(n)
+			if err != nil || parsed < 1 {
+				return historyStrategy{}, fmt.Errorf("quantidade de trocas inválida: %s", n)
+			}
+			lastN = parsed
+		}
+		return historyStrategy{name: historyStrategyLastN, lastN: lastN}, nil
+	default:
+		return historyStrategy{}, fmt.Errorf("estratégia desconhecida: %s (use full, last-n ou summarize)", name)
+	}
+}
+
+// historyStrategyFromEnv lê CHATCLI_HISTORY_STRATEGY e CHATCLI_HISTORY_LAST_N
+func historyStrategyFromEnv() (historyStrategy, error) {
+	return parseHistoryStrategy(os.Getenv("CHATCLI_HISTORY_STRATEGY"), os.Getenv("CHATCLI_HISTORY_LAST_N"))
+}
+
+// loadHistoryStrategy aplica a estratégia definida nas variáveis de ambiente, avisando se ela for inválida
+func (cli *ChatCLI) loadHistoryStrategy() {
+	strategy, err := historyStrategyFromEnv()
+	if err != nil {
+		fmt.Println("Aviso: CHATCLI_HISTORY_STRATEGY ignorada:", err)
+	}
+	cli.historyStrategy = strategy
+}
+
+// applyHistoryStrategy retorna as mensagens do histórico que devem ser enviadas ao provedor
+func applyHistoryStrategy(history []models.Message, s historyStrategy) []models.Message {
+	if s.name != historyStrategyLastN {
+		return history
+	}
+	prefix, exchanges := splitSummarizable(history)
+	if len(exchanges) <= s.lastN {
+		return history
+	}
+	bounded := append([]models.Message(nil), prefix...)
+	for _, exchange := range exchanges[len(exchanges)-s.lastN:] {
+		bounded = append(bounded, exchange...)
+	}
+	return bounded
+}
+
+// handleHistoryCommand trata /history show, /history clear e /history strategy [full|last-n [N]|summarize]
+func (cli *ChatCLI) handleHistoryCommand(userInput string) {
+	args := strings.Fields(userInput)
+	if len(args) < 2 {
+		fmt.Println("Uso: /history show | clear | search <termo> | strategy [full|last-n [N]|summarize]")
+		return
+	}
+
+	switch args[1] {
+	case "show":
+		cli.showHistory()
+	case "clear":
+		if len(cli.history) == 0 {
+			fmt.Println("O histórico já está vazio.")
+			return
+		}
+		if !cli.confirm(fmt.Sprintf("Apagar as %d mensagens do histórico da sessão? (s/N): ", len(cli.history))) {
+			fmt.Println("Nada foi apagado.")
+			return
+		}
+		cli.history = []models.Message{}
+		cli.redoStack = nil
+		fmt.Println("Histórico da sessão apagado.")
+	case "search":
+		if len(args) < 3 {
+			fmt.Println("Uso: /history search <termo>")
+			return
+		}
+		cli.searchInputHistory(strings.Join(args[2:], " "))
+	case "strategy":
+		if len(args) == 2 {
+			fmt.Println("Estratégia de histórico:", cli.historyStrategy)
+			return
+		}
+		n := ""
+		if len(args) > 3 {
+			n = args[3]
+		}
+		strategy, err := parseHistoryStrategy(args[2], n)
+		if err != nil {
+			fmt.Println("Erro:", err)
+			return
+		}
+		cli.historyStrategy = strategy
+		fmt.Println("Estratégia de histórico alterada para", strategy)
+	default:
+		fmt.Println("Uso: /history show | clear | search <termo> | strategy [full|last-n [N]|summarize]")
+	}
+}
+
+// showHistory lista as mensagens do histórico, indicando as que ficam fora da requisição pela estratégia atual
+func (cli *ChatCLI) showHistory() {
+	if len(cli.history) == 0 {
+		fmt.Println("O histórico da sessão está vazio.")
+		return
+	}
+	sent := applyHistoryStrategy(cli.history, cli.historyStrategy)
+	omitted := len(cli.history) - len(sent)
+
+	fmt.Printf("Estratégia: %s\n", cli.historyStrategy)
+	// As mensagens omitidas por last-n são sempre as trocas mais antigas após os resumos
+	prefix, _ := splitSummarizable(cli.history)
+	for i, msg := range cli.history {
+		marker := ""
+		if i >= len(prefix) && i < len(prefix)+omitted {
+			marker = " (não enviada)"
+		}
+		content := strings.Join(strings.Fields(msg.Content), " ")
+		if runes := []rune(content); len(runes) > 80 {
+			content = string(runes[:80]) + "..."
+		}
+		fmt.Printf("  [%d] %s%s: %s\n", i+1, msg.Role, marker, content)
+	}
+	fmt.Printf("%d mensagem(ns), %d enviada(s) ao provedor, cerca de %d tokens por requisição.\n",
+		len(cli.history), len(sent), estimateTokens(cli.historyForRequest()))
+}
+
+// maxHistorySearchResults limita os resultados de /history search
+const maxHistorySearchResults = 20
+
+// matchInputHistory lista as entradas digitadas, desta e das sessões anteriores, que contêm o termo,
+// da mais recente para a mais antiga e sem repetições
+func matchInputHistory(history []string, term string) []string {
+	term = strings.ToLower(term)
+	seen := make(map[string]bool)
+	var matches []string
+	for i := len(history) - 1; i >= 0 && len(matches) < maxHistorySearchResults; i-- {
+		entry := history[i]
+		if seen[entry] || strings.HasPrefix(entry, "/history search") || !strings.Contains(strings.ToLower(entry), term) {
+			continue
+		}
+		seen[entry] = true
+		matches = append(matches, entry)
+	}
+	return matches
+}
+
+// searchInputHistory trata /history search <termo>
+func (cli *ChatCLI) searchInputHistory(term string) {
+	matches := matchInputHistory(cli.commandHistory, term)
+	if len(matches) == 0 {
+		fmt.Printf("Nenhuma entrada do histórico contém %q.\n", term)
+		return
+	}
+	fmt.Printf("Entradas com %q (da mais recente para a mais antiga; use Ctrl+R para buscar e reutilizar no prompt):\n", term)
+	for i, entry := range matches {
+		fmt.Printf("%d. %s\n", i+1, entry)
+	}
+}
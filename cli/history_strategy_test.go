@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/diillson/chatcli/llm/client"
+	"github.com/diillson/chatcli/models"
+)
+
+// longHistory monta um histórico com n trocas de tamanho semelhante
+func longHistory(n int) []models.Message {
+	var history []models.Message
+	for i := 1; i <= n; i++ {
+		history = append(history,
+			models.Message{Role: "user", Content: fmt.Sprintf("pergunta %d %s", i, strings.Repeat("x", 400))},
+			models.Message{Role: "assistant", Content: fmt.Sprintf("resposta %d %s", i, strings.Repeat("y", 400))},
+		)
+	}
+	return history
+}
+
+func TestApplyHistoryStrategy(t *testing.T) {
+	history := longHistory(30)
+
+	full := applyHistoryStrategy(history, historyStrategy{name: historyStrategyFull})
+	if len(full) != len(history) {
+		t.Errorf("full deveria enviar todo o histórico, obtido %d mensagens", len(full))
+	}
+
+	// Com last-n, o tamanho da requisição fica limitado independentemente do tamanho do histórico
+	strategy := historyStrategy{name: historyStrategyLastN, lastN: 5}
+	bounded := applyHistoryStrategy(history, strategy)
+	if len(bounded) != 10 || !strings.HasPrefix(bounded[0].Content, "pergunta 26") {
+		t.Fatalf("Esperado enviar as 5 últimas trocas, obtido %d mensagens começando por %q", len(bounded), bounded[0].Content[:12])
+	}
+	if tokens := estimateTokens(bounded); tokens > estimateTokens(history)/5 {
+		t.Errorf("last-n deveria limitar os tokens a 5 trocas, obtido %d", tokens)
+	}
+	if len(applyHistoryStrategy(longHistory(3), strategy)) != 6 {
+		t.Error("Históricos menores que N deveriam ser enviados inteiros")
+	}
+
+	// Os resumos anteriores às trocas mantidas continuam sendo enviados
+	withSummary := append([]models.Message{{Role: "system", Content: summaryPrefix + "\nresumo"}}, history...)
+	if bounded := applyHistoryStrategy(withSummary, strategy); len(bounded) != 11 || !isSummaryMessage(bounded[0]) {
+		t.Errorf("Esperado manter o resumo antes das trocas recentes, obtido %d mensagens", len(bounded))
+	}
+}
+
+func TestAutoSummarizeIfNeeded_summarizeStrategy(t *testing.T) {
+	t.Setenv("CHATCLI_AUTO_SUMMARIZE", "")
+	history := longHistory(150)
+	if estimateTokens(history) < defaultAutoSummarizeThreshold {
+		t.Fatal("O histórico de teste deveria ultrapassar o limite padrão")
+	}
+
+	cli := &ChatCLI{client: &client.MockLLMClient{Response: "resumo"}, history: history}
+	cli.autoSummarizeIfNeeded(context.Background())
+	if len(cli.history) != len(history) {
+		t.Fatal("Com a estratégia full e sem CHATCLI_AUTO_SUMMARIZE, o histórico não deveria ser resumido")
+	}
+
+	cli.historyStrategy = historyStrategy{name: historyStrategySummarize}
+	cli.autoSummarizeIfNeeded(context.Background())
+	if tokens := estimateTokens(cli.historyForRequest()); tokens >= defaultAutoSummarizeThreshold {
+		t.Errorf("Esperado ficar abaixo do limite após o resumo, obtido %d tokens", tokens)
+	}
+	if !isSummaryMessage(cli.history[0]) || len(cli.history) != 1+2*summarizeKeepRecent {
+		t.Errorf("Esperado resumo seguido das trocas recentes, obtido %d mensagens", len(cli.history))
+	}
+}
+
+func TestParseHistoryStrategy(t *testing.T) {
+	if s, err := parseHistoryStrategy("", ""); err != nil || s.name != historyStrategyFull {
+		t.Errorf("Esperado full por padrão, obtido %+v (erro: %v)", s, err)
+	}
+	if s, err := parseHistoryStrategy("last-n", ""); err != nil || s.lastN != defaultHistoryLastN {
+		t.Errorf("Esperado last-n com %d trocas, obtido %+v (erro: %v)", defaultHistoryLastN, s, err)
+	}
+	if s, _ := parseHistoryStrategy("LAST-N", "3"); s.lastN != 3 {
+		t.Errorf("Esperado last-n com 3 trocas, obtido %+v", s)
+	}
+	for _, args := range [][2]string{{"tudo", ""}, {"last-n", "0"}, {"last-n", "abc"}} {
+		if _, err := parseHistoryStrategy(args[0], args[1]); err == nil {
+			t.Errorf("Esperado erro para %v", args)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/diillson/chatcli/llm/client"
+	"github.com/diillson/chatcli/models"
+	"github.com/diillson/chatcli/utils"
+)
+
+// maxImageSize é o maior tamanho de imagem aceito por @image (limite da OpenAI; a ClaudeAI aceita menos)
+const maxImageSize = 20 * 1024 * 1024
+
+// imageMediaTypes associa as extensões aceitas por @image aos tipos MIME esperados
+var imageMediaTypes = map[string]string{
+	".png":  "image/png",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".gif":  "image/gif",
+	".webp": "image/webp",
+}
+
+// extractImages extrai os comandos '@image <caminho> [--detail low|high]' da entrada, carregando e
+// validando cada imagem, e retorna a entrada sem eles
+func extractImages(input string) ([]models.Image, string, error) {
+	if !strings.Contains(strings.ToLower(input), "@image") {
+		return nil, input, nil
+	}
+	tokens, err := parseFields(input)
+	if err != nil {
+		return nil, input, err
+	}
+
+	var images []models.Image
+	var rest []string
+	for i := 0; i < len(tokens); i++ {
+		if !strings.EqualFold(tokens[i], "@image") {
+			rest = append(rest, tokens[i])
+			continue
+		}
+		if i+1 >= len(tokens) {
+			return nil, input, fmt.Errorf("comando @image sem caminho")
+		}
+		i++
+		image, err := loadImage(tokens[i])
+		if err != nil {
+			return nil, input, err
+		}
+		if i+1 < len(tokens) && tokens[i+1] == "--detail" {
+			if i+2 >= len(tokens) || (tokens[i+2] != "low" && tokens[i+2] != "high") {
+				return nil, input, fmt.Errorf("valor inválido para --detail (use low ou high)")
+			}
+			image.Detail = tokens[i+2]
+			i += 2
+		}
+		images = append(images, image)
+	}
+	return images, strings.Join(rest, " "), nil
+}
+
+// loadImage lê a imagem, verificando o tamanho e se o conteúdo é de fato PNG, JPEG, GIF ou WebP
+func loadImage(path string) (models.Image, error) {
+	expanded, err := utils.ExpandPath(path)
+	if err != nil {
+		return models.Image{}, err
+	}
+	if _, ok := imageMediaTypes[strings.ToLower(filepath.Ext(expanded))]; !ok {
+		return models.Image{}, fmt.Errorf("formato de imagem não suportado: %s (use PNG, JPEG, GIF ou WebP)", path)
+	}
+
+	info, err := os.Stat(expanded)
+	if err != nil {
+		return models.Image{}, fmt.Errorf("erro ao acessar a imagem %s: %w", path, err)
+	}
+	if info.IsDir() {
+		return models.Image{}, fmt.Errorf("%s é um diretório", path)
+	}
+	if info.Size() > maxImageSize {
+		return models.Image{}, fmt.Errorf("a imagem %s tem %.1f MB; o máximo é %d MB", path,
+			float64(info.Size())/(1024*1024), maxImageSize/(1024*1024))
+	}
+
+	data, err := os.ReadFile(expanded)
+	if err != nil {
+		return models.Image{}, fmt.Errorf("erro ao ler a imagem %s: %w", path, err)
+	}
+	// O tipo é determinado pelo conteúdo, já que a extensão pode não corresponder ao arquivo
+	mediaType := http.DetectContentType(data)
+	valid := false
+	for _, accepted := range imageMediaTypes {
+		valid = valid || mediaType == accepted
+	}
+	if !valid {
+		return models.Image{}, fmt.Errorf("o conteúdo de %s não é uma imagem válida (detectado: %s)", path, mediaType)
+	}
+	return models.Image{Path: path, MediaType: mediaType, Data: data}, nil
+}
+
+// imageClient retorna o cliente atual se ele aceitar imagens, ou um erro explicando que o modelo não tem visão
+func (cli *ChatCLI) imageClient() (client.ImageCapable, error) {
+	imageCapable, ok := cli.client.(client.ImageCapable)
+	if !ok || !imageCapable.SupportsImages() {
+		return nil, fmt.Errorf("o modelo %s (%s) não aceita imagens; use /switch para um modelo com visão, como gpt-4o ou claude-3-5-sonnet",
+			cli.client.GetModelName(), cli.provider)
+	}
+	return imageCapable, nil
+}
+
+// imageNote descreve as imagens anexadas na mensagem guardada no histórico, que mantém apenas texto
+func imageNote(images []models.Image) string {
+	var builder strings.Builder
+	for _, image := range images {
+		builder.WriteString(fmt.Sprintf("\n[Imagem anexada: %s]", image.Path))
+	}
+	return builder.String()
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/diillson/chatcli/llm/client"
+)
+
+// pngHeader é suficiente para que o conteúdo seja detectado como PNG
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestExtractImages(t *testing.T) {
+	dir := t.TempDir()
+	png := filepath.Join(dir, "tela.png")
+	if err := os.WriteFile(png, pngHeader, 0600); err != nil {
+		t.Fatal(err)
+	}
+	fake := filepath.Join(dir, "falsa.jpg")
+	if err := os.WriteFile(fake, []byte("apenas texto"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	images, rest, err := extractImages("o que há de errado @image " + png + " --detail high nesta tela?")
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if rest != "o que há de errado nesta tela?" {
+		t.Errorf("Entrada restante inesperada: %q", rest)
+	}
+	if len(images) != 1 || images[0].MediaType != "image/png" || images[0].Detail != "high" {
+		t.Errorf("Imagens inesperadas: %+v", images)
+	}
+
+	for _, input := range []string{
+		"@image",
+		"@image " + fake,
+		"@image " + filepath.Join(dir, "doc.pdf"),
+		"@image " + png + " --detail max",
+	} {
+		if _, _, err := extractImages(input); err == nil {
+			t.Errorf("Esperado erro para %q", input)
+		}
+	}
+}
+
+func TestImageClient(t *testing.T) {
+	cli := &ChatCLI{client: &client.MockLLMClient{}, provider: "STACKSPOT"}
+	_, err := cli.imageClient()
+	if err == nil || !strings.Contains(err.Error(), "não aceita imagens") {
+		t.Errorf("Esperado erro de modelo sem visão, obtido: %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"go.uber.org/zap"
+	"golang.org/x/term"
+)
+
+// terminalResetSequence reseta cores/atributos e reexibe o cursor, caso o comando tenha saído sem restaurá-los
+const terminalResetSequence = "\033[0m\033[?25h"
+
+// terminalState abstrai o salvamento e a restauração do estado do terminal ao redor de comandos interativos
+type terminalState interface {
+	Save() error
+	Restore() error
+}
+
+// stdinTerminalState salva e restaura o modo do terminal conectado à entrada padrão
+type stdinTerminalState struct {
+	fd    int
+	state *term.State
+}
+
+func newStdinTerminalState() *stdinTerminalState {
+	return &stdinTerminalState{fd: int(os.Stdin.Fd())}
+}
+
+// Save guarda o estado atual do terminal. Não faz nada quando a entrada não é um terminal.
+func (s *stdinTerminalState) Save() error {
+	if !term.IsTerminal(s.fd) {
+		return nil
+	}
+	state, err := term.GetState(s.fd)
+	if err != nil {
+		return err
+	}
+	s.state = state
+	return nil
+}
+
+// Restore restaura o estado salvo e reseta os atributos de exibição
+func (s *stdinTerminalState) Restore() error {
+	if s.state == nil {
+		return nil
+	}
+	fmt.Print(terminalResetSequence)
+	return term.Restore(s.fd, s.state)
+}
+
+// runInteractiveCommand executa o comando conectado diretamente ao terminal. O processo filho herda o
+// TTY (e, portanto, recebe os sinais de redimensionamento de janela), e o estado do terminal é
+// restaurado ao final mesmo que o comando termine de forma anormal.
+func (cli *ChatCLI) runInteractiveCommand(cmd *exec.Cmd, ts terminalState) error {
+	if err := ts.Save(); err != nil {
+		cli.logger.Warn("Não foi possível salvar o estado do terminal", zap.Error(err))
+	}
+	defer func() {
+		if err := ts.Restore(); err != nil {
+			cli.logger.Warn("Não foi possível restaurar o estado do terminal", zap.Error(err))
+		}
+	}()
+
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"os/exec"
+	"runtime"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// fakeTerminalState registra as chamadas de salvamento e restauração do terminal
+type fakeTerminalState struct {
+	saved    bool
+	restored bool
+}
+
+func (f *fakeTerminalState) Save() error {
+	f.saved = true
+	return nil
+}
+
+func (f *fakeTerminalState) Restore() error {
+	f.restored = true
+	return nil
+}
+
+func TestChatCLI_runInteractiveCommandRestoresTerminal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("teste depende de sh")
+	}
+	logger, _ := zap.NewDevelopment()
+	cli := &ChatCLI{logger: logger}
+
+	// Comando que termina de forma anormal (morto por sinal)
+	ts := &fakeTerminalState{}
+	err := cli.runInteractiveCommand(exec.Command("sh", "-c", "kill -9 $$"), ts)
+	if err == nil {
+		t.Error("Esperado erro para comando encerrado por sinal")
+	}
+	if !ts.saved || !ts.restored {
+		t.Errorf("Esperado salvar e restaurar o terminal, saved=%v restored=%v", ts.saved, ts.restored)
+	}
+
+	// Comando inexistente também deve restaurar o terminal
+	ts = &fakeTerminalState{}
+	cli.runInteractiveCommand(exec.Command("comando-que-nao-existe-chatcli"), ts)
+	if !ts.restored {
+		t.Error("Esperado restaurar o terminal quando o comando falha ao iniciar")
+	}
+}
@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/diillson/chatcli/llm/client"
+	"go.uber.org/zap"
+)
+
+// jsonInstruction é acrescentada ao prompt no modo JSON. Nos provedores com modo nativo ela também é
+// necessária: a OpenAI rejeita response_format json_object se as mensagens não mencionarem JSON.
+const jsonInstruction = "\n\nResponda apenas com um JSON válido, sem nenhum texto antes ou depois e sem blocos de código Markdown."
+
+var (
+	// jsonFencePattern captura o conteúdo do primeiro bloco de código, com ou sem a linguagem json
+	jsonFencePattern = regexp.MustCompile("(?s)```(?:json)?\\s*\\n(.*?)\\n\\s*```")
+	// trailingCommaPattern corresponde a vírgulas antes do fechamento de objetos e listas
+	trailingCommaPattern = regexp.MustCompile(`,(\s*[}\]])`)
+)
+
+// parseResponseFormat normaliza o formato pedido em /format e /switch --response-format
+func parseResponseFormat(value string) (string, error) {
+	switch strings.ToLower(value) {
+	case "json":
+		return client.ResponseFormatJSON, nil
+	case "text", "texto", "off":
+		return "", nil
+	default:
+		return "", fmt.Errorf("formato de resposta inválido: %s (use json ou text)", value)
+	}
+}
+
+// applyResponseFormat repassa o formato da resposta ao cliente atual, quando o provedor tem modo nativo
+func (cli *ChatCLI) applyResponseFormat() {
+	if configurable, ok := cli.client.(client.ResponseFormatConfigurable); ok {
+		configurable.SetResponseFormat(cli.responseFormat)
+	}
+}
+
+// setResponseFormat define o formato da resposta da sessão e informa como ele será aplicado
+func (cli *ChatCLI) setResponseFormat(format string) {
+	cli.responseFormat = format
+	cli.applyResponseFormat()
+	if format == "" {
+		fmt.Println("Formato da resposta: texto livre.")
+		return
+	}
+	if _, ok := cli.client.(client.ResponseFormatConfigurable); ok {
+		fmt.Printf("Formato da resposta: JSON, usando o modo nativo de %s.\n", cli.provider)
+		return
+	}
+	fmt.Printf("Formato da resposta: JSON. %s não tem modo nativo; o prompt recebe uma instrução e a resposta é validada e, se possível, reparada.\n", cli.provider)
+}
+
+// handleFormatCommand trata /format [json|text]
+func (cli *ChatCLI) handleFormatCommand(userInput string) {
+	args := strings.Fields(userInput)
+	if len(args) == 1 {
+		format := "texto livre"
+		if cli.responseFormat == client.ResponseFormatJSON {
+			format = "JSON"
+		}
+		fmt.Printf("Formato da resposta: %s. Use /format json ou /format text para alterá-lo.\n", format)
+		return
+	}
+	if len(args) != 2 {
+		fmt.Println("Uso: /format [json|text]")
+		return
+	}
+	format, err := parseResponseFormat(args[1])
+	if err != nil {
+		fmt.Println("Erro:", err)
+		return
+	}
+	cli.setResponseFormat(format)
+}
+
+// repairJSON tenta obter um JSON válido da resposta: o texto inteiro, o conteúdo de um bloco de código
+// ou o trecho entre o primeiro { ou [ e o último } ou ], removendo vírgulas finais se necessário.
+// Retorna o JSON encontrado e se ele é válido.
+func repairJSON(text string) (string, bool) {
+	trimmed := strings.TrimSpace(text)
+	candidates := []string{trimmed}
+	if match := jsonFencePattern.FindStringSubmatch(trimmed); match != nil {
+		candidates = append(candidates, strings.TrimSpace(match[1]))
+	}
+	if start := strings.IndexAny(trimmed, "{["); start != -1 {
+		if end := strings.LastIndexAny(trimmed, "}]"); end > start {
+			candidates = append(candidates, trimmed[start:end+1])
+		}
+	}
+
+	for _, candidate := range candidates {
+		if json.Valid([]byte(candidate)) {
+			return candidate, true
+		}
+		if fixed := trailingCommaPattern.ReplaceAllString(candidate, "$1"); json.Valid([]byte(fixed)) {
+			return fixed, true
+		}
+	}
+	return text, false
+}
+
+// enforceJSON valida a resposta no modo JSON, substituindo-a pela versão reparada quando necessário
+// e informando o resultado
+func (cli *ChatCLI) enforceJSON(response string) string {
+	repaired, ok := repairJSON(response)
+	switch {
+	case !ok:
+		cli.logger.Warn("Resposta não é um JSON válido", zap.String("provider", cli.provider))
+		fmt.Println("Aviso: a resposta não é um JSON válido e não pôde ser reparada; ela é exibida como recebida.")
+		return response
+	case repaired != strings.TrimSpace(response):
+		cli.logger.Info("Resposta JSON reparada", zap.String("provider", cli.provider))
+		fmt.Println("Aviso: a resposta não era um JSON válido; o JSON foi extraído do texto e reparado.")
+	}
+	return repaired
+}
+
+// renderResponse renderiza a resposta em Markdown; no modo JSON, como um bloco de código JSON
+func (cli *ChatCLI) renderResponse(response string) string {
+	if cli.responseFormat == client.ResponseFormatJSON && json.Valid([]byte(response)) {
+		return cli.renderMarkdown("```json\n" + response + "\n```")
+	}
+	return cli.renderMarkdown(response)
+}
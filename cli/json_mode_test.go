@@ -0,0 +1,23 @@
+package cli
+
+import "testing"
+
+func TestRepairJSON(t *testing.T) {
+	cases := []struct {
+		input string
+		want  string
+		ok    bool
+	}{
+		{`{"a": 1}`, `{"a": 1}`, true},
+		{"Aqui está:\n```json\n{\"a\": [1, 2]}\n```\nEspero que ajude.", `{"a": [1, 2]}`, true},
+		{`O resultado é {"a": 1, "b": [1, 2,],} conforme pedido.`, `{"a": 1, "b": [1, 2]}`, true},
+		{`[{"id": 1}]`, `[{"id": 1}]`, true},
+		{"não há JSON aqui", "não há JSON aqui", false},
+	}
+	for _, c := range cases {
+		got, ok := repairJSON(c.input)
+		if got != c.want || ok != c.ok {
+			t.Errorf("repairJSON(%q) = %q, %v; esperado %q, %v", c.input, got, ok, c.want, c.ok)
+		}
+	}
+}
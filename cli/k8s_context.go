@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/diillson/chatcli/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultK8sLogsTail é a quantidade de linhas incluídas por @k8s logs sem --tail nem --since
+	defaultK8sLogsTail = 200
+	// k8sTimeout é o tempo limite de cada chamada ao kubectl
+	k8sTimeout = 30 * time.Second
+)
+
+// extractK8sRequests extrai os comandos '@k8s <verbo> [argumentos]' da entrada. Os argumentos vão até o
+// fim da entrada, até o próximo @k8s ou até um '>' isolado, depois do qual o texto volta a ser o prompt.
+func extractK8sRequests(input string) ([][]string, string, error) {
+	tokens, err := parseFields(input)
+	if err != nil {
+		return nil, input, err
+	}
+
+	var requests [][]string
+	var rest []string
+	for i := 0; i < len(tokens); i++ {
+		if !strings.EqualFold(tokens[i], "@k8s") {
+			rest = append(rest, tokens[i])
+			continue
+		}
+		var args []string
+		for i+1 < len(tokens) && !strings.EqualFold(tokens[i+1], "@k8s") {
+			i++
+			if tokens[i] == ">" {
+				break
+			}
+			args = append(args, tokens[i])
+		}
+		if err := utils.ValidateKubectlArgs(args); err != nil {
+			return nil, input, err
+		}
+		requests = append(requests, withDefaultLogsTail(args))
+	}
+	return requests, strings.Join(rest, " "), nil
+}
+
+// withDefaultLogsTail limita @k8s logs às últimas linhas quando nem --tail nem --since foram informados
+func withDefaultLogsTail(args []string) []string {
+	if !strings.EqualFold(args[0], "logs") {
+		return args
+	}
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "--tail") || strings.HasPrefix(arg, "--since") {
+			return args
+		}
+	}
+	return append(args, fmt.Sprintf("--tail=%d", defaultK8sLogsTail))
+}
+
+// processK8sCommand adiciona ao contexto a saída dos comandos de leitura do kubectl pedidos com @k8s.
+// A saída segue o limite de saída de @command.
+func (cli *ChatCLI) processK8sCommand(userInput string) (string, string) {
+	if !strings.Contains(strings.ToLower(userInput), "@k8s") {
+		return userInput, ""
+	}
+
+	requests, rest, err := extractK8sRequests(userInput)
+	if err != nil {
+		cli.logger.Error("Erro ao processar o comando @k8s", zap.Error(err))
+		fmt.Println("Erro no comando @k8s:", err)
+		return userInput, ""
+	}
+
+	var additionalContext string
+	for _, args := range requests {
+		command := "kubectl " + strings.Join(args, " ")
+		ctx, cancel := context.WithTimeout(context.Background(), k8sTimeout)
+		output, err := utils.Kubectl(ctx, args)
+		cancel()
+		if err != nil {
+			cli.logger.Warn("Erro ao executar o kubectl", zap.String("comando", command), zap.Error(err))
+			fmt.Printf("Erro no comando @k8s %s: %v\n", strings.Join(args, " "), err)
+			continue
+		}
+		if strings.TrimSpace(output) == "" {
+			fmt.Printf("O comando %s não retornou nada.\n", command)
+			continue
+		}
+		additionalContext += fmt.Sprintf("\nSaída de '%s':%s\n```\n%s\n```\n",
+			command, cli.tagSource("@k8s "+strings.Join(args, " ")),
+			cli.outputForModel(strings.TrimRight(output, "\n"), commandOptions{}))
+	}
+	return rest, additionalContext
+}
@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractK8sRequests(t *testing.T) {
+	requests, rest, err := extractK8sRequests("@k8s describe pod api -n loja > por que está Pending? @k8s logs api --context prod")
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if rest != "por que está Pending?" {
+		t.Errorf("Entrada inesperada: %q", rest)
+	}
+	expected := [][]string{
+		{"describe", "pod", "api", "-n", "loja"},
+		{"logs", "api", "--context", "prod", "--tail=200"},
+	}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Errorf("Pedidos inesperados: %q", requests)
+	}
+
+	if requests, _, _ := extractK8sRequests("@k8s logs api --since=10m"); len(requests[0]) != 3 {
+		t.Errorf("--tail não deveria ser acrescentado com --since: %q", requests[0])
+	}
+	for _, input := range []string{"@k8s", "@k8s delete pod api", "@k8s get pods -w", "@k8s logs api -f", "@k8s get secret db -o yaml"} {
+		if _, _, err := extractK8sRequests(input); err == nil {
+			t.Errorf("Esperado erro para %q", input)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/diillson/chatcli/llm/client"
+)
+
+// keyCheckTimeout é o tempo limite da verificação de cada provedor
+const keyCheckTimeout = 20 * time.Second
+
+// keyCheckResult é o resultado da verificação das credenciais de um provedor. Nunca contém a chave.
+type keyCheckResult struct {
+	provider   string
+	status     string
+	httpStatus int
+	detail     string
+}
+
+// keyFormatProblem aponta credenciais com formato claramente inválido (prefixo, tamanho, espaços ou
+// aspas copiados junto), evitando uma chamada que certamente falharia. Com um endpoint personalizado,
+// o prefixo não é verificado, já que gateways compatíveis usam chaves próprias. Com OPENAI_API_KEYS,
+// cada chave da lista é verificada.
+func keyFormatProblem(provider string) string {
+	var keys []string
+	prefix, baseURLEnv, minLength := "", "", 0
+	switch provider {
+	case "OPENAI":
+		keys, prefix, baseURLEnv, minLength = []string{"OPENAI_API_KEY"}, "sk-", "OPENAI_BASE_URL", 20
+		if os.Getenv("OPENAI_API_KEYS") != "" {
+			keys = []string{"OPENAI_API_KEYS"}
+		}
+	case "CLAUDEAI":
+		keys, prefix, baseURLEnv, minLength = []string{"CLAUDEAI_API_KEY"}, "sk-ant-", "CLAUDEAI_BASE_URL", 20
+	case "STACKSPOT":
+		keys = []string{"CLIENT_ID", "CLIENT_SECRET"}
+	}
+
+	for _, env := range keys {
+		values := []string{os.Getenv(env)}
+		if env == "OPENAI_API_KEYS" {
+			values = client.ParseKeys(os.Getenv(env))
+		}
+		for i, value := range values {
+			name := env
+			if len(values) > 1 {
+				name = fmt.Sprintf("%s (chave %d)", env, i+1)
+			}
+			switch {
+			case strings.ContainsAny(value, " \t\r\n\"'"):
+				return fmt.Sprintf("%s contém espaços ou aspas", name)
+			case len(value) < minLength:
+				return fmt.Sprintf("%s é curta demais (%d caracteres)", name, len(value))
+			case prefix != "" && os.Getenv(baseURLEnv) == "" && !strings.HasPrefix(value, prefix):
+				return fmt.Sprintf("%s não começa com %q", name, prefix)
+			}
+		}
+	}
+	return ""
+}
+
+// classifyKeyError converte o erro da chamada de verificação em um resultado, usando os erros tipados
+// dos provedores. O corpo da resposta não é exibido, pois alguns provedores repetem parte da chave nele.
+func classifyKeyError(provider string, err error) keyCheckResult {
+	result := keyCheckResult{provider: provider, status: "válida"}
+	if err == nil {
+		return result
+	}
+	var providerErr *client.ProviderError
+	if errors.As(err, &providerErr) {
+		result.httpStatus = providerErr.StatusCode
+	}
+
+	switch {
+	case errors.Is(err, client.ErrAuth) && result.httpStatus == http.StatusForbidden:
+		result.status, result.detail = "sem permissão", "a chave foi aceita, mas não tem acesso ao recurso"
+	case errors.Is(err, client.ErrAuth):
+		result.status, result.detail = "inválida ou expirada", "gere uma nova chave e atualize o .env"
+	case errors.Is(err, client.ErrRateLimited):
+		result.status, result.detail = "válida", "limite de requisições atingido no momento"
+	case errors.Is(err, client.ErrTimeout):
+		result.status, result.detail = "não verificada", "o provedor não respondeu a tempo"
+	case result.httpStatus != 0:
+		result.status, result.detail = "não verificada", http.StatusText(result.httpStatus)
+	default:
+		result.status, result.detail = "não verificada", err.Error()
+	}
+	return result
+}
+
+// checkKeys verifica simultaneamente as credenciais de cada provedor conhecido
+func (cli *ChatCLI) checkKeys(ctx context.Context) []keyCheckResult {
+	available := make(map[string]bool)
+	for _, p := range cli.manager.GetAvailableProviders() {
+		available[p] = true
+	}
+
+	results := make([]keyCheckResult, len(knownProviders))
+	var wg sync.WaitGroup
+	for i, p := range knownProviders {
+		result := keyCheckResult{provider: p.name}
+		var missing []string
+		for _, env := range p.credentials {
+			if !credentialSet(env) {
+				missing = append(missing, env)
+			}
+		}
+
+		switch {
+		case len(p.credentials) == 0:
+			result.status, result.detail = "sem chave", "o provedor não usa credenciais"
+		case len(missing) > 0:
+			result.status, result.detail = "não configurada", "faltando: "+strings.Join(missing, ", ")
+		case keyFormatProblem(p.name) != "":
+			result.status, result.detail = "malformada", keyFormatProblem(p.name)
+		case !available[p.name]:
+			result.status, result.detail = "não verificada", "provedor indisponível; veja os logs"
+		default:
+			wg.Add(1)
+			go func(i int, provider string) {
+				defer wg.Done()
+				results[i] = cli.checkProviderKey(ctx, provider)
+			}(i, p.name)
+			continue
+		}
+		results[i] = result
+	}
+	wg.Wait()
+	return results
+}
+
+// checkProviderKey faz a chamada mínima autenticada de um provedor
+func (cli *ChatCLI) checkProviderKey(ctx context.Context, provider string) keyCheckResult {
+	llmClient, err := cli.manager.GetClient(provider, "")
+	if err != nil {
+		return keyCheckResult{provider: provider, status: "não verificada", detail: err.Error()}
+	}
+	checker, ok := llmClient.(client.KeyChecker)
+	if !ok {
+		return keyCheckResult{provider: provider, status: "não verificada", detail: "o provedor não oferece verificação"}
+	}
+	checkCtx, cancel := context.WithTimeout(ctx, keyCheckTimeout)
+	defer cancel()
+	return classifyKeyError(provider, checker.CheckKey(checkCtx))
+}
+
+// handleKeysCommand trata /keys check
+func (cli *ChatCLI) handleKeysCommand(userInput string) {
+	args := strings.Fields(userInput)
+	if len(args) != 2 || args[1] != "check" {
+		fmt.Println("Uso: /keys check - verifica as credenciais configuradas de cada provedor")
+		return
+	}
+
+	fmt.Println("Verificando as credenciais...")
+	results := cli.checkKeys(context.Background())
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROVEDOR\tRESULTADO\tHTTP\tDETALHE")
+	for _, r := range results {
+		status := "-"
+		if r.httpStatus != 0 {
+			status = strconv.Itoa(r.httpStatus)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.provider, r.status, status, r.detail)
+	}
+	w.Flush()
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/diillson/chatcli/llm/client"
+)
+
+func TestKeyFormatProblem(t *testing.T) {
+	t.Setenv("OPENAI_BASE_URL", "")
+	tests := []struct {
+		key      string
+		expected bool
+	}{
+		{"sk-proj-abcdefghijklmnopqrstuvwxyz", false},
+		{"abcdefghijklmnopqrstuvwxyz", true},
+		{"sk-curta", true},
+		{"\"sk-proj-abcdefghijklmnopqrstuvwxyz\"", true},
+	}
+	for _, tt := range tests {
+		t.Setenv("OPENAI_API_KEY", tt.key)
+		if problem := keyFormatProblem("OPENAI"); (problem != "") != tt.expected {
+			t.Errorf("Chave %q: problema inesperado %q", tt.key, problem)
+		}
+	}
+
+	// Com um endpoint personalizado, o prefixo não é exigido
+	t.Setenv("OPENAI_API_KEY", "chave-do-gateway-interno-123")
+	t.Setenv("OPENAI_BASE_URL", "http://localhost:8080/v1")
+	if problem := keyFormatProblem("OPENAI"); problem != "" {
+		t.Errorf("Problema inesperado com endpoint personalizado: %q", problem)
+	}
+}
+
+func TestClassifyKeyError(t *testing.T) {
+	tests := []struct {
+		err      error
+		status   string
+		httpCode int
+	}{
+		{nil, "válida", 0},
+		{client.NewHTTPError("OpenAI", http.StatusUnauthorized, nil, []byte("Incorrect API key provided: sk-abc***xyz")), "inválida ou expirada", 401},
+		{client.NewHTTPError("OpenAI", http.StatusForbidden, nil, nil), "sem permissão", 403},
+		{client.NewHTTPError("OpenAI", http.StatusTooManyRequests, http.Header{}, nil), "válida", 429},
+		{errors.New("conexão recusada"), "não verificada", 0},
+	}
+	for _, tt := range tests {
+		r := classifyKeyError("OPENAI", tt.err)
+		if r.status != tt.status || r.httpStatus != tt.httpCode {
+			t.Errorf("Erro %v: esperado %s/%d, obtido %s/%d", tt.err, tt.status, tt.httpCode, r.status, r.httpStatus)
+		}
+		if tt.err != nil && r.httpStatus != 0 && r.detail == "" {
+			t.Errorf("Detalhe ausente para %v", tt.err)
+		}
+	}
+}
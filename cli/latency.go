@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// latencySample é a duração de uma chamada ao provedor feita durante a sessão
+type latencySample struct {
+	provider string
+	model    string
+	duration time.Duration
+	failed   bool
+}
+
+// latencyStats resume as chamadas de um provedor e modelo. As estatísticas de tempo consideram apenas
+// as chamadas bem-sucedidas, já que as falhas costumam terminar por tempo limite ou logo no início.
+type latencyStats struct {
+	provider string
+	model    string
+	calls    int
+	failed   int
+	min      time.Duration
+	max      time.Duration
+	avg      time.Duration
+	p95      time.Duration
+}
+
+// recordLatency registra a duração de uma chamada ao provedor e modelo atuais
+func (cli *ChatCLI) recordLatency(duration time.Duration, err error) {
+	cli.latencies = append(cli.latencies, latencySample{
+		provider: cli.provider,
+		model:    cli.client.GetModelName(),
+		duration: duration,
+		failed:   err != nil,
+	})
+}
+
+// summarizeLatencies agrupa as amostras por provedor e modelo, na ordem da primeira chamada de cada um
+func summarizeLatencies(samples []latencySample) []latencyStats {
+	var order []string
+	groups := make(map[string][]latencySample)
+	for _, s := range samples {
+		key := s.provider + "\x00" + s.model
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], s)
+	}
+
+	stats := make([]latencyStats, 0, len(order))
+	for _, key := range order {
+		group := groups[key]
+		st := latencyStats{provider: group[0].provider, model: group[0].model, calls: len(group)}
+		var durations []time.Duration
+		var total time.Duration
+		for _, s := range group {
+			if s.failed {
+				st.failed++
+				continue
+			}
+			durations = append(durations, s.duration)
+			total += s.duration
+		}
+		if len(durations) > 0 {
+			sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+			st.min = durations[0]
+			st.max = durations[len(durations)-1]
+			st.avg = total / time.Duration(len(durations))
+			// p95 pelo método do posto mais próximo
+			st.p95 = durations[(len(durations)*95+99)/100-1]
+		}
+		stats = append(stats, st)
+	}
+	return stats
+}
+
+// handleLatencyCommand trata /latency, que mostra a latência das chamadas da sessão por provedor e modelo
+func (cli *ChatCLI) handleLatencyCommand() {
+	if len(cli.latencies) == 0 {
+		fmt.Println("Nenhuma chamada ao provedor nesta sessão.")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROVEDOR\tMODELO\tCHAMADAS\tFALHAS\tMÍN\tMÉDIA\tP95\tMÁX")
+	for _, st := range summarizeLatencies(cli.latencies) {
+		if st.calls == st.failed {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%d\t-\t-\t-\t-\n", st.provider, st.model, st.calls, st.failed)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\t%s\t%s\t%s\n", st.provider, st.model, st.calls, st.failed,
+			formatLatency(st.min), formatLatency(st.avg), formatLatency(st.p95), formatLatency(st.max))
+	}
+	w.Flush()
+}
+
+// formatLatency arredonda a duração para leitura na tabela
+func formatLatency(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(10 * time.Millisecond).String()
+}
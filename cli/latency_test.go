@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSummarizeLatencies(t *testing.T) {
+	var samples []latencySample
+	for i := 1; i <= 20; i++ {
+		samples = append(samples, latencySample{provider: "OPENAI", model: "gpt-4o", duration: time.Duration(i) * 100 * time.Millisecond})
+	}
+	samples = append(samples,
+		latencySample{provider: "OPENAI", model: "gpt-4o", duration: time.Minute, failed: true},
+		latencySample{provider: "CLAUDEAI", model: "claude-3-5-sonnet", failed: true},
+	)
+
+	stats := summarizeLatencies(samples)
+	if len(stats) != 2 || stats[0].provider != "OPENAI" || stats[1].provider != "CLAUDEAI" {
+		t.Fatalf("Grupos inesperados: %+v", stats)
+	}
+	openai := stats[0]
+	if openai.calls != 21 || openai.failed != 1 {
+		t.Errorf("Contagem inesperada: %d chamadas, %d falhas", openai.calls, openai.failed)
+	}
+	if openai.min != 100*time.Millisecond || openai.max != 2*time.Second || openai.avg != 1050*time.Millisecond || openai.p95 != 1900*time.Millisecond {
+		t.Errorf("Estatísticas inesperadas: %+v", openai)
+	}
+	if stats[1].calls != 1 || stats[1].failed != 1 || stats[1].max != 0 {
+		t.Errorf("Provedor só com falhas inesperado: %+v", stats[1])
+	}
+}
@@ -0,0 +1,278 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diillson/chatcli/models"
+	"github.com/diillson/chatcli/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultMemoryFile = "~/.chatcli/memory.json"
+	// memoryWarnTokens é o tamanho estimado a partir do qual o usuário é avisado de que a memória está grande
+	memoryWarnTokens = 1000
+	// memoryMaxTokens é o tamanho estimado máximo da memória; novos fatos acima dele são recusados
+	memoryMaxTokens = 4000
+)
+
+// MemoryFact é um fato fixado pelo usuário com /remember
+type MemoryFact struct {
+	ID        int       `json:"id"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// MemoryStore mantém os fatos persistidos entre sessões, injetados no contexto de sistema.
+// Com CHATCLI_ENCRYPTION_KEY definida, o arquivo é gravado criptografado.
+type MemoryStore struct {
+	path   string
+	facts  []MemoryFact
+	logger *zap.Logger
+	// locked indica que o arquivo está criptografado e não pôde ser lido; nesse caso a memória
+	// não é gravada, para não sobrescrever os fatos existentes
+	locked bool
+}
+
+// NewMemoryStore cria o armazenamento de memória no caminho informado (ou no padrão, se vazio)
+func NewMemoryStore(path string, logger *zap.Logger) *MemoryStore {
+	if path == "" {
+		path = defaultMemoryFile
+	}
+	return &MemoryStore{path: path, logger: logger}
+}
+
+// Load lê os fatos do arquivo. Um arquivo inexistente resulta em uma memória vazia.
+func (m *MemoryStore) Load() error {
+	path, err := m.resolvedPath()
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			m.facts = nil
+			return nil
+		}
+		return fmt.Errorf("erro ao ler a memória em %s: %w", path, err)
+	}
+	m.locked = false
+	if utils.IsEncrypted(data) {
+		data, err = utils.DecryptData(data, os.Getenv("CHATCLI_ENCRYPTION_KEY"))
+		if err != nil {
+			m.facts = nil
+			m.locked = true
+			return fmt.Errorf("erro ao ler a memória em %s: %w", path, err)
+		}
+	}
+	var facts []MemoryFact
+	if err := json.Unmarshal(data, &facts); err != nil {
+		return fmt.Errorf("erro ao decodificar a memória em %s: %w", path, err)
+	}
+	m.facts = facts
+	return nil
+}
+
+// save grava os fatos no arquivo, criando o diretório se necessário e criptografando-o se
+// CHATCLI_ENCRYPTION_KEY estiver definida
+func (m *MemoryStore) save() error {
+	if m.locked {
+		return fmt.Errorf("a memória está criptografada e não pôde ser lida; defina a CHATCLI_ENCRYPTION_KEY correta e use /reload")
+	}
+	path, err := m.resolvedPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("erro ao criar o diretório da memória: %w", err)
+	}
+	data, err := json.MarshalIndent(m.facts, "", "  ")
+	if err != nil {
+		return fmt.Errorf("erro ao codificar a memória: %w", err)
+	}
+	if key := os.Getenv("CHATCLI_ENCRYPTION_KEY"); key != "" {
+		if data, err = utils.EncryptData(data, key); err != nil {
+			return fmt.Errorf("erro ao criptografar a memória: %w", err)
+		}
+	}
+	if err := utils.WriteFileAtomic(path, data, 0600); err != nil {
+		return fmt.Errorf("erro ao gravar a memória em %s: %w", path, err)
+	}
+	return nil
+}
+
+// Encrypt regrava o arquivo de memória criptografado com CHATCLI_ENCRYPTION_KEY, migrando um
+// arquivo em texto puro. Retorna false se não houver arquivo ou se ele já estiver criptografado.
+func (m *MemoryStore) Encrypt() (bool, error) {
+	if os.Getenv("CHATCLI_ENCRYPTION_KEY") == "" {
+		return false, utils.ErrEncryptionKeyMissing
+	}
+	path, err := m.resolvedPath()
+	if err != nil {
+		return false, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("erro ao ler a memória em %s: %w", path, err)
+	}
+	if utils.IsEncrypted(data) {
+		return false, nil
+	}
+	if err := m.Load(); err != nil {
+		return false, err
+	}
+	return true, m.save()
+}
+
+func (m *MemoryStore) resolvedPath() (string, error) {
+	return utils.ExpandPath(m.path)
+}
+
+// Facts retorna os fatos memorizados
+func (m *MemoryStore) Facts() []MemoryFact {
+	return m.facts
+}
+
+// Add memoriza um novo fato, recusando-o se a memória ultrapassar o tamanho máximo
+func (m *MemoryStore) Add(text string) (MemoryFact, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return MemoryFact{}, fmt.Errorf("o fato não pode ser vazio")
+	}
+
+	nextID := 1
+	for _, f := range m.facts {
+		if f.ID >= nextID {
+			nextID = f.ID + 1
+		}
+	}
+	fact := MemoryFact{ID: nextID, Text: text, CreatedAt: time.Now()}
+
+	if tokens := m.estimatedTokens() + estimateTokens([]models.Message{{Content: text}}); tokens > memoryMaxTokens {
+		return MemoryFact{}, fmt.Errorf("a memória ficaria com cerca de %d tokens (máximo %d); remova fatos com /forget", tokens, memoryMaxTokens)
+	}
+
+	m.facts = append(m.facts, fact)
+	if err := m.save(); err != nil {
+		m.facts = m.facts[:len(m.facts)-1]
+		return MemoryFact{}, err
+	}
+	return fact, nil
+}
+
+// Remove esquece o fato com o ID informado. Retorna false se ele não existir.
+func (m *MemoryStore) Remove(id int) (bool, error) {
+	for i, f := range m.facts {
+		if f.ID == id {
+			previous := m.facts
+			m.facts = append(append([]MemoryFact(nil), m.facts[:i]...), m.facts[i+1:]...)
+			if err := m.save(); err != nil {
+				m.facts = previous
+				return false, err
+			}
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// estimatedTokens estima o tamanho da memória quando injetada no contexto
+func (m *MemoryStore) estimatedTokens() int {
+	return estimateTokens([]models.Message{{Content: m.SystemContext()}})
+}
+
+// SystemContext formata os fatos para inclusão no contexto de sistema
+func (m *MemoryStore) SystemContext() string {
+	if m == nil || len(m.facts) == 0 {
+		return ""
+	}
+	var builder strings.Builder
+	builder.WriteString("Fatos que o usuário pediu para lembrar:\n")
+	for _, f := range m.facts {
+		builder.WriteString(fmt.Sprintf("- %s\n", f.Text))
+	}
+	return strings.TrimSpace(builder.String())
+}
+
+// handleRememberCommand trata o comando /remember <texto>
+func (cli *ChatCLI) handleRememberCommand(userInput string) {
+	text := strings.TrimSpace(strings.TrimPrefix(userInput, "/remember"))
+	if text == "" {
+		fmt.Println("Uso: /remember <texto>")
+		return
+	}
+	fact, err := cli.memory.Add(text)
+	if err != nil {
+		fmt.Println("Não foi possível memorizar:", err)
+		return
+	}
+	fmt.Printf("Memorizado [%d]: %s\n", fact.ID, fact.Text)
+	if tokens := cli.memory.estimatedTokens(); tokens > memoryWarnTokens {
+		fmt.Printf("Aviso: a memória já ocupa cerca de %d tokens em cada requisição. Considere remover fatos com /forget.\n", tokens)
+	}
+}
+
+// handleForgetCommand trata o comando /forget <id>
+func (cli *ChatCLI) handleForgetCommand(userInput string) {
+	args := strings.Fields(userInput)
+	if len(args) != 2 {
+		fmt.Println("Uso: /forget <id>")
+		return
+	}
+	id, err := strconv.Atoi(args[1])
+	if err != nil {
+		fmt.Println("Uso: /forget <id> - o id deve ser um número (veja /memory list)")
+		return
+	}
+	removed, err := cli.memory.Remove(id)
+	if err != nil {
+		fmt.Println("Não foi possível esquecer o fato:", err)
+		return
+	}
+	if !removed {
+		fmt.Printf("Nenhum fato com id %d.\n", id)
+		return
+	}
+	fmt.Printf("Fato %d esquecido.\n", id)
+}
+
+// handleMemoryCommand trata os comandos /memory list e /memory encrypt
+func (cli *ChatCLI) handleMemoryCommand(userInput string) {
+	args := strings.Fields(userInput)
+	if len(args) > 1 && args[1] == "encrypt" {
+		migrated, err := cli.memory.Encrypt()
+		if err != nil {
+			fmt.Println("Não foi possível criptografar a memória:", err)
+			return
+		}
+		if migrated {
+			fmt.Println("Memória criptografada com CHATCLI_ENCRYPTION_KEY.")
+		} else {
+			fmt.Println("Nada a fazer: a memória não existe ou já está criptografada.")
+		}
+		return
+	}
+	if len(args) > 1 && args[1] != "list" {
+		fmt.Println("Uso: /memory [list|encrypt]")
+		return
+	}
+	facts := cli.memory.Facts()
+	if len(facts) == 0 {
+		fmt.Println("Nenhum fato memorizado. Use /remember <texto> para adicionar.")
+		return
+	}
+	fmt.Println("Fatos memorizados:")
+	for _, f := range facts {
+		fmt.Printf("  [%d] %s\n", f.ID, f.Text)
+	}
+	fmt.Printf("Tamanho estimado: %d tokens\n", cli.memory.estimatedTokens())
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/diillson/chatcli/utils"
+	"go.uber.org/zap"
+)
+
+func TestMemoryStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "memoria", "memory.json")
+	store := NewMemoryStore(path, zap.NewNop())
+	if err := store.Load(); err != nil {
+		t.Fatalf("Erro ao carregar memória inexistente: %v", err)
+	}
+
+	first, err := store.Add("O projeto usa Go 1.23")
+	if err != nil {
+		t.Fatalf("Erro ao adicionar fato: %v", err)
+	}
+	second, _ := store.Add("Os testes rodam com go test ./...")
+	if first.ID != 1 || second.ID != 2 {
+		t.Errorf("IDs inesperados: %d e %d", first.ID, second.ID)
+	}
+
+	// Os fatos persistem entre instâncias
+	reloaded := NewMemoryStore(path, zap.NewNop())
+	if err := reloaded.Load(); err != nil || len(reloaded.Facts()) != 2 {
+		t.Fatalf("Esperado recarregar 2 fatos, obtido %d (erro: %v)", len(reloaded.Facts()), err)
+	}
+	if !strings.Contains(reloaded.SystemContext(), "O projeto usa Go 1.23") {
+		t.Errorf("Contexto de sistema sem o fato memorizado: %s", reloaded.SystemContext())
+	}
+
+	if removed, _ := reloaded.Remove(1); !removed {
+		t.Error("Esperado remover o fato 1")
+	}
+	if removed, _ := reloaded.Remove(1); removed {
+		t.Error("Não deveria remover um fato inexistente")
+	}
+	third, _ := reloaded.Add("novo fato")
+	if third.ID != 3 {
+		t.Errorf("Esperado id 3 para o novo fato, obtido %d", third.ID)
+	}
+
+	if _, err := reloaded.Add(strings.Repeat("a", memoryMaxTokens*4)); err == nil {
+		t.Error("Esperado recusar fato que ultrapassa o tamanho máximo da memória")
+	}
+	if _, err := reloaded.Add("   "); err == nil {
+		t.Error("Esperado recusar fato vazio")
+	}
+}
+
+func TestMemoryStore_encrypted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "memory.json")
+	store := NewMemoryStore(path, zap.NewNop())
+	if _, err := store.Add("fato em texto puro"); err != nil {
+		t.Fatalf("Erro ao adicionar fato: %v", err)
+	}
+
+	t.Setenv("CHATCLI_ENCRYPTION_KEY", "senha")
+	if migrated, err := store.Encrypt(); err != nil || !migrated {
+		t.Fatalf("Esperado migrar a memória para o formato criptografado (erro: %v)", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !utils.IsEncrypted(data) || strings.Contains(string(data), "texto puro") {
+		t.Fatal("O arquivo de memória deveria estar criptografado")
+	}
+
+	reloaded := NewMemoryStore(path, zap.NewNop())
+	if err := reloaded.Load(); err != nil || len(reloaded.Facts()) != 1 {
+		t.Fatalf("Esperado recarregar 1 fato, obtido %d (erro: %v)", len(reloaded.Facts()), err)
+	}
+
+	// Com a chave incorreta, a memória não é carregada nem sobrescrita
+	t.Setenv("CHATCLI_ENCRYPTION_KEY", "outra")
+	locked := NewMemoryStore(path, zap.NewNop())
+	if err := locked.Load(); !errors.Is(err, utils.ErrEncryptionKeyWrong) {
+		t.Fatalf("Esperado ErrEncryptionKeyWrong, obtido %v", err)
+	}
+	if _, err := locked.Add("novo fato"); err == nil {
+		t.Error("Não deveria gravar a memória sem conseguir lê-la")
+	}
+	if after, _ := os.ReadFile(path); string(after) != string(data) {
+		t.Error("O arquivo de memória não deveria ter sido alterado")
+	}
+}
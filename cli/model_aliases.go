@@ -0,0 +1,223 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/diillson/chatcli/utils"
+	"go.uber.org/zap"
+)
+
+// defaultAliasesFile é o arquivo de apelidos usado quando CHATCLI_ALIASES_FILE não está definido
+const defaultAliasesFile = "~/.chatcli/aliases.json"
+
+// aliasNamePattern restringe os nomes de apelidos, que também são usados em @provider <apelido>
+var aliasNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+
+// aliasesPath retorna o arquivo de apelidos configurado, já expandido
+func aliasesPath() (string, error) {
+	return utils.ExpandPath(utils.GetEnvOrDefault("CHATCLI_ALIASES_FILE", defaultAliasesFile))
+}
+
+// loadAliases lê os apelidos de modelos, como {"fast": "OPENAI/gpt-4o-mini"}. Um arquivo inexistente
+// resulta em nenhum apelido.
+func loadAliases(path string) (map[string]string, error) {
+	aliases := make(map[string]string)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return aliases, nil
+		}
+		return aliases, fmt.Errorf("erro ao ler os apelidos em %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &aliases); err != nil {
+		return make(map[string]string), fmt.Errorf("erro ao decodificar os apelidos em %s: %w", path, err)
+	}
+	normalized := make(map[string]string, len(aliases))
+	for name, target := range aliases {
+		normalized[strings.ToLower(name)] = target
+	}
+	return normalized, nil
+}
+
+// saveAliases grava os apelidos, criando o diretório se necessário
+func saveAliases(path string, aliases map[string]string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("erro ao criar o diretório dos apelidos: %w", err)
+	}
+	data, err := json.MarshalIndent(aliases, "", "  ")
+	if err != nil {
+		return fmt.Errorf("erro ao codificar os apelidos: %w", err)
+	}
+	if err := utils.WriteFileAtomic(path, data, 0600); err != nil {
+		return fmt.Errorf("erro ao gravar os apelidos em %s: %w", path, err)
+	}
+	return nil
+}
+
+// parseModelTarget interpreta "PROVEDOR/modelo" ou apenas "modelo". O prefixo só é tratado como
+// provedor se for um provedor conhecido, já que ids de modelos também podem conter "/".
+func parseModelTarget(value string) route {
+	if prefix, model, ok := strings.Cut(value, "/"); ok {
+		for _, p := range allProviders() {
+			if strings.EqualFold(prefix, p.name) {
+				return route{Provider: p.name, Model: model}
+			}
+		}
+	}
+	return route{Model: value}
+}
+
+// lookupAlias retorna o destino do apelido, se ele estiver definido
+func lookupAlias(name string) (string, bool, error) {
+	path, err := aliasesPath()
+	if err != nil {
+		return "", false, err
+	}
+	aliases, err := loadAliases(path)
+	if err != nil {
+		return "", false, err
+	}
+	target, ok := aliases[strings.ToLower(name)]
+	return target, ok, nil
+}
+
+// resolveModel resolve um apelido ou um modelo de /switch --model no par provedor e modelo. Sem
+// provedor explícito, vale o provedor atual.
+func (cli *ChatCLI) resolveModel(value string) (route, error) {
+	target, ok, err := lookupAlias(value)
+	if err != nil {
+		return route{}, err
+	}
+	if !ok {
+		target = value
+	}
+	r := parseModelTarget(target)
+	if r.Provider == "" {
+		r.Provider = cli.provider
+	}
+	if r.Model == "" {
+		return route{}, fmt.Errorf("o apelido %s não define um modelo", value)
+	}
+	return r, nil
+}
+
+// aliasRoute troca a rota de @provider <apelido> pela rota do apelido. Nomes de provedores têm
+// precedência sobre apelidos.
+func (cli *ChatCLI) aliasRoute(r route) route {
+	for _, p := range allProviders() {
+		if p.name == r.Provider {
+			return r
+		}
+	}
+	if _, ok, err := lookupAlias(r.Provider); err != nil || !ok {
+		return r
+	}
+	if resolved, err := cli.resolveModel(r.Provider); err == nil {
+		return resolved
+	}
+	return r
+}
+
+// switchModel trata /switch --model <modelo|apelido>, mantendo o histórico da conversa
+func (cli *ChatCLI) switchModel(value string) bool {
+	r, err := cli.resolveModel(value)
+	if err != nil {
+		fmt.Println("Erro:", err)
+		return false
+	}
+	if !cli.isAvailable(r.Provider) {
+		fmt.Printf("Erro: o provedor %s não está configurado.\n", r.Provider)
+		return false
+	}
+	if r.Provider == "STACKSPOT" {
+		fmt.Println("Erro: o provedor STACKSPOT não permite escolher o modelo.")
+		return false
+	}
+
+	newClient, err := cli.manager.GetClient(r.Provider, r.Model)
+	if err != nil {
+		cli.logger.Error("Erro ao trocar de modelo", zap.Error(err))
+		fmt.Println("Erro ao trocar de modelo:", err)
+		return false
+	}
+	cli.client, cli.provider, cli.model = newClient, r.Provider, r.Model
+	cli.applyGenerationParams()
+	cli.autoRouting = false
+	fmt.Printf("Trocado para %s (%s)\n", cli.client.GetModelName(), cli.provider)
+	return true
+}
+
+// handleAliasCommand trata /alias list, /alias set <nome> <PROVEDOR/modelo> e /alias remove <nome>
+func (cli *ChatCLI) handleAliasCommand(userInput string) {
+	args := strings.Fields(userInput)
+	path, err := aliasesPath()
+	if err != nil {
+		fmt.Println("Erro:", err)
+		return
+	}
+	aliases, err := loadAliases(path)
+	if err != nil {
+		fmt.Println("Erro:", err)
+		return
+	}
+
+	switch {
+	case len(args) == 1 || len(args) == 2 && args[1] == "list":
+		if len(aliases) == 0 {
+			fmt.Printf("Nenhum apelido definido em %s. Use /alias set <nome> <PROVEDOR/modelo>.\n", path)
+			return
+		}
+		names := make([]string, 0, len(aliases))
+		for name := range aliases {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Printf("Apelidos de modelos (%s):\n", path)
+		for _, name := range names {
+			fmt.Printf("  %s -> %s\n", name, aliases[name])
+		}
+	case len(args) == 4 && args[1] == "set":
+		name := strings.ToLower(args[2])
+		if !aliasNamePattern.MatchString(name) {
+			fmt.Println("Erro: o nome do apelido deve começar com uma letra e conter apenas letras, números, _ e -.")
+			return
+		}
+		for _, p := range allProviders() {
+			if strings.EqualFold(name, p.name) {
+				fmt.Printf("Erro: %s é o nome de um provedor.\n", p.name)
+				return
+			}
+		}
+		aliases[name] = args[3]
+		if err := saveAliases(path, aliases); err != nil {
+			fmt.Println("Erro:", err)
+			return
+		}
+		target := parseModelTarget(args[3])
+		if target.Provider == "" {
+			fmt.Printf("Apelido %s definido para o modelo %s no provedor atual de cada uso.\n", name, target.Model)
+			return
+		}
+		fmt.Printf("Apelido %s definido para %s (%s).\n", name, target.Model, target.Provider)
+	case len(args) == 3 && args[1] == "remove":
+		name := strings.ToLower(args[2])
+		if _, ok := aliases[name]; !ok {
+			fmt.Printf("Apelido %s não encontrado.\n", name)
+			return
+		}
+		delete(aliases, name)
+		if err := saveAliases(path, aliases); err != nil {
+			fmt.Println("Erro:", err)
+			return
+		}
+		fmt.Printf("Apelido %s removido.\n", name)
+	default:
+		fmt.Println("Uso: /alias [list] | /alias set <nome> <PROVEDOR/modelo> | /alias remove <nome>")
+	}
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestParseModelTarget(t *testing.T) {
+	cases := []struct {
+		value string
+		want  route
+	}{
+		{"OPENAI/gpt-4o", route{Provider: "OPENAI", Model: "gpt-4o"}},
+		{"claudeai/claude-3-opus", route{Provider: "CLAUDEAI", Model: "claude-3-opus"}},
+		{"gpt-4o-mini", route{Model: "gpt-4o-mini"}},
+		{"library/llama3:8b", route{Model: "library/llama3:8b"}},
+	}
+	for _, c := range cases {
+		if got := parseModelTarget(c.value); got != c.want {
+			t.Errorf("parseModelTarget(%q) = %+v, esperado %+v", c.value, got, c.want)
+		}
+	}
+}
+
+func TestResolveModel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "aliases.json")
+	t.Setenv("CHATCLI_ALIASES_FILE", path)
+	if err := saveAliases(path, map[string]string{"fast": "OPENAI/gpt-4o-mini", "local": "llama3.1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cli := &ChatCLI{logger: zap.NewNop(), provider: "OLLAMA"}
+	cases := []struct {
+		value string
+		want  route
+	}{
+		{"FAST", route{Provider: "OPENAI", Model: "gpt-4o-mini"}},
+		{"local", route{Provider: "OLLAMA", Model: "llama3.1"}},
+		{"CLAUDEAI/claude-3-opus", route{Provider: "CLAUDEAI", Model: "claude-3-opus"}},
+		{"mistral", route{Provider: "OLLAMA", Model: "mistral"}},
+	}
+	for _, c := range cases {
+		if got, err := cli.resolveModel(c.value); err != nil || got != c.want {
+			t.Errorf("resolveModel(%q) = %+v (erro: %v), esperado %+v", c.value, got, err, c.want)
+		}
+	}
+
+	if got := cli.aliasRoute(route{Provider: "FAST"}); got != (route{Provider: "OPENAI", Model: "gpt-4o-mini"}) {
+		t.Errorf("aliasRoute(FAST) = %+v", got)
+	}
+	if got := cli.aliasRoute(route{Provider: "CLAUDEAI"}); got != (route{Provider: "CLAUDEAI"}) {
+		t.Errorf("aliasRoute não deveria alterar um provedor conhecido: %+v", got)
+	}
+	if got := cli.aliasRoute(route{Provider: "DESCONHECIDO"}); got != (route{Provider: "DESCONHECIDO"}) {
+		t.Errorf("aliasRoute não deveria alterar um nome sem apelido: %+v", got)
+	}
+}
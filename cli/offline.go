@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// queueOffline guarda o prompt para ser enviado com /offline flush. Os comandos @ são expandidos só no
+// envio, para que arquivos e saídas reflitam o estado de quando a conexão voltar.
+func (cli *ChatCLI) queueOffline(input string) {
+	cli.offlineQueue = append(cli.offlineQueue, input)
+	fmt.Printf("Modo offline: o prompt não pode ser respondido sem conexão e foi colocado na fila (%d pendente(s)). Use /offline flush quando a conexão voltar.\n",
+		len(cli.offlineQueue))
+}
+
+// handleOfflineCommand trata /offline [on|off|flush]
+func (cli *ChatCLI) handleOfflineCommand(userInput string) {
+	args := strings.Fields(userInput)
+	if len(args) == 1 {
+		state := "desativado"
+		if cli.offline {
+			state = "ativado"
+		}
+		fmt.Printf("Modo offline %s; %d prompt(s) na fila.\n", state, len(cli.offlineQueue))
+		return
+	}
+	if len(args) != 2 {
+		fmt.Println("Uso: /offline [on|off|flush]")
+		return
+	}
+
+	switch args[1] {
+	case "on":
+		cli.offline = true
+		fmt.Println("Modo offline ativado: os prompts serão colocados na fila em vez de enviados.")
+	case "off":
+		cli.offline = false
+		fmt.Printf("Modo offline desativado; %d prompt(s) na fila. Use /offline flush para enviá-los.\n", len(cli.offlineQueue))
+	case "flush":
+		cli.flushOfflineQueue()
+	default:
+		fmt.Println("Uso: /offline [on|off|flush]")
+	}
+}
+
+// flushOfflineQueue desativa o modo offline e envia os prompts da fila em ordem. Se um envio falhar,
+// ele e os seguintes permanecem na fila.
+func (cli *ChatCLI) flushOfflineQueue() {
+	cli.offline = false
+	if len(cli.offlineQueue) == 0 {
+		fmt.Println("Nenhum prompt na fila.")
+		return
+	}
+
+	queue := cli.offlineQueue
+	cli.offlineQueue = nil
+	for i, input := range queue {
+		fmt.Printf("Enviando prompt %d de %d: %s\n", i+1, len(queue), input)
+		calls := len(cli.latencies)
+		cli.sendPrompt(context.Background(), input)
+		if !cli.answeredSince(calls) {
+			cli.offlineQueue = append(cli.offlineQueue, queue[i:]...)
+			fmt.Printf("O envio falhou; %d prompt(s) continuam na fila.\n", len(cli.offlineQueue))
+			return
+		}
+	}
+	fmt.Println("Todos os prompts da fila foram enviados.")
+}
+
+// answeredSince indica se houve uma chamada bem-sucedida ao provedor depois das calls já registradas
+func (cli *ChatCLI) answeredSince(calls int) bool {
+	return len(cli.latencies) > calls && !cli.latencies[len(cli.latencies)-1].failed
+}
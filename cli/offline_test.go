@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestOfflineQueueAndFlush(t *testing.T) {
+	cli, err := NewChatCLI(&MockLLMManager{}, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli.handleOfflineCommand("/offline on")
+	cli.sendPrompt(context.Background(), "primeiro")
+	cli.sendPrompt(context.Background(), "segundo")
+	if len(cli.offlineQueue) != 2 || len(cli.history) != 0 {
+		t.Fatalf("Esperado enfileirar sem enviar, fila %v, histórico %d", cli.offlineQueue, len(cli.history))
+	}
+
+	// Uma falha no envio mantém o prompt e os seguintes na fila
+	cli.client = &MockLLMClient{err: errors.New("sem conexão")}
+	cli.handleOfflineCommand("/offline flush")
+	if cli.offline || len(cli.offlineQueue) != 2 || cli.offlineQueue[0] != "primeiro" {
+		t.Fatalf("Esperado manter a fila após a falha, obteve %v", cli.offlineQueue)
+	}
+
+	cli.client = &MockLLMClient{response: "ok"}
+	cli.handleOfflineCommand("/offline flush")
+	if len(cli.offlineQueue) != 0 || len(cli.history) < 4 {
+		t.Errorf("Esperado enviar toda a fila, fila %v, histórico %d", cli.offlineQueue, len(cli.history))
+	}
+}
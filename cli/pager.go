@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/diillson/chatcli/utils"
+	"go.uber.org/zap"
+	"golang.org/x/term"
+)
+
+// pagerCommand retorna o comando do pager: $PAGER, se definido, ou less -R (que preserva as cores
+// da renderização), recorrendo a more quando less não está instalado
+func pagerCommand() []string {
+	if pager := strings.Fields(os.Getenv("PAGER")); len(pager) > 0 {
+		return pager
+	}
+	if _, err := exec.LookPath("less"); err == nil {
+		return []string{"less", "-R"}
+	}
+	return []string{"more"}
+}
+
+// exceedsHeight indica se o texto tem mais linhas do que cabem no terminal
+func exceedsHeight(text string, height int) bool {
+	return height > 0 && strings.Count(text, "\n")+1 > height
+}
+
+// showInPager abre o texto no pager, que assume o terminal até o usuário sair
+func showInPager(text string) error {
+	args := pagerCommand()
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// displayResponse exibe a resposta renderizada. Se ela não couber no terminal, oferece abri-la no pager;
+// caso contrário (ou se a saída não for um terminal), usa o efeito de digitação, que pode ser
+// interrompido com Ctrl+C sem deixar o terminal com cores ou cursor alterados.
+func (cli *ChatCLI) displayResponse(ctx context.Context, rendered string) {
+	text := fmt.Sprintf("\n%s:\n%s\n", cli.client.GetModelName(), rendered)
+
+	if term.IsTerminal(int(os.Stdout.Fd())) {
+		_, height, err := utils.GetTerminalSize()
+		if err == nil && exceedsHeight(text, height) {
+			question := fmt.Sprintf("A resposta tem %d linhas. Abrir no pager? (s/N): ", strings.Count(text, "\n")+1)
+			if cli.confirm(question) {
+				err := showInPager(text)
+				if err == nil {
+					fmt.Println("Use /page para ver a resposta novamente.")
+					return
+				}
+				cli.logger.Warn("Erro ao abrir o pager", zap.Error(err))
+				fmt.Println("Não foi possível abrir o pager:", err)
+			}
+		}
+	}
+
+	if !cli.typewriterEffect(ctx, text, 2*time.Millisecond) {
+		cli.cancelOperation(os.Stdout, "Exibição interrompida. Use /page para ver a resposta completa.")
+	}
+}
+
+// handlePageCommand trata /page, que abre a última resposta do assistente no pager
+func (cli *ChatCLI) handlePageCommand() {
+	response, ok := cli.lastAssistantResponse()
+	if !ok {
+		fmt.Println("Nenhuma resposta do assistente nesta sessão.")
+		return
+	}
+	text := fmt.Sprintf("%s:\n%s\n", cli.client.GetModelName(), cli.renderMarkdown(response))
+	if err := showInPager(text); err != nil {
+		cli.logger.Warn("Erro ao abrir o pager", zap.Error(err))
+		fmt.Println("Não foi possível abrir o pager:", err)
+	}
+}
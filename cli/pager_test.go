@@ -0,0 +1,19 @@
+package cli
+
+import "testing"
+
+func TestPagerCommand(t *testing.T) {
+	t.Setenv("PAGER", "most -s")
+	if got := pagerCommand(); len(got) != 2 || got[0] != "most" || got[1] != "-s" {
+		t.Errorf("Esperado o pager de $PAGER, obtido %v", got)
+	}
+}
+
+func TestExceedsHeight(t *testing.T) {
+	if !exceedsHeight("a\nb\nc", 2) {
+		t.Error("Texto de 3 linhas deveria exceder um terminal de 2 linhas")
+	}
+	if exceedsHeight("a\nb", 2) || exceedsHeight("a\nb\nc", 0) {
+		t.Error("Texto não deveria exceder a altura")
+	}
+}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// shellBuiltins são palavras que não correspondem a executáveis no PATH e por isso não são verificadas
+var shellBuiltins = map[string]bool{
+	"cd": true, "export": true, "echo": true, "printf": true, "source": true, ".": true, "alias": true,
+	"set": true, "unset": true, "read": true, "pwd": true, "exit": true, "return": true, "local": true,
+	"eval": true, "test": true, "[": true, "[[": true, "true": true, "false": true, "type": true,
+	"if": true, "then": true, "else": true, "elif": true, "fi": true, "for": true, "while": true,
+	"until": true, "do": true, "done": true, "case": true, "esac": true, "in": true, "!": true,
+	"{": true, "}": true, "(": true, ")": true,
+}
+
+// commandWrappers são prefixos que executam o comando seguinte; o binário verificado é o que vem depois
+var commandWrappers = map[string]bool{
+	"sudo": true, "env": true, "time": true, "nohup": true, "exec": true, "command": true, "xargs": true,
+}
+
+// splitShellSegments divide a linha de comando nos operadores &&, ||, ;, | e &, respeitando aspas
+func splitShellSegments(command string) []string {
+	var segments []string
+	var current strings.Builder
+	var quote byte
+
+	flush := func() {
+		if s := strings.TrimSpace(current.String()); s != "" {
+			segments = append(segments, s)
+		}
+		current.Reset()
+	}
+
+	for i := 0; i < len(command); i++ {
+		c := command[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+			current.WriteByte(c)
+		case c == '\'' || c == '"':
+			quote = c
+			current.WriteByte(c)
+		case c == ';' || c == '|' || c == '&' || c == '\n':
+			flush()
+		default:
+			current.WriteByte(c)
+		}
+	}
+	flush()
+	return segments
+}
+
+// commandBinaries retorna, sem repetição, os executáveis que iniciam cada trecho do comando,
+// ignorando atribuições de variáveis, builtins do shell, caminhos explícitos e expansões
+func commandBinaries(command string) []string {
+	var binaries []string
+	seen := make(map[string]bool)
+
+	for _, segment := range splitShellSegments(command) {
+		for _, word := range strings.Fields(segment) {
+			word = strings.TrimLeft(word, "({")
+			if word == "" || (strings.Contains(word, "=") && !strings.HasPrefix(word, "=")) {
+				continue // atribuição como FOO=bar antes do comando
+			}
+			if commandWrappers[word] {
+				continue
+			}
+			if strings.HasPrefix(word, "-") {
+				break // flags de um wrapper (ex: sudo -u root) tornam o binário ambíguo
+			}
+			if !shellBuiltins[word] && !strings.ContainsAny(word, "/$`'\"") && !seen[word] {
+				seen[word] = true
+				binaries = append(binaries, word)
+			}
+			break
+		}
+	}
+	return binaries
+}
+
+// missingTools retorna os executáveis dos comandos que não foram encontrados pelo lookPath
+func missingTools(commands []string, lookPath func(string) (string, error)) []string {
+	var missing []string
+	seen := make(map[string]bool)
+	for _, command := range commands {
+		for _, binary := range commandBinaries(command) {
+			if seen[binary] {
+				continue
+			}
+			seen[binary] = true
+			if _, err := lookPath(binary); err != nil {
+				missing = append(missing, binary)
+			}
+		}
+	}
+	return missing
+}
+
+// preflight verifica se as ferramentas usadas pelos comandos estão instaladas, informando todas
+// as ausentes de uma vez. Retorna false se alguma estiver faltando.
+func preflight(commands []string) bool {
+	missing := missingTools(commands, exec.LookPath)
+	if len(missing) == 0 {
+		return true
+	}
+	fmt.Printf("Ferramentas não encontradas no PATH: %s\n", strings.Join(missing, ", "))
+	fmt.Println("Instale-as ou use --skip-preflight para executar mesmo assim (por exemplo, se forem aliases ou funções do shell).")
+	return false
+}
@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCommandBinaries(t *testing.T) {
+	tests := []struct {
+		command string
+		want    []string
+	}{
+		{"kubectl get pods | grep api", []string{"kubectl", "grep"}},
+		{"cd /tmp && FOO=bar docker build . ; sudo systemctl restart x", []string{"docker", "systemctl"}},
+		{`echo "a | b" && ./script.sh`, nil},
+		{"sudo -u root kubectl get pods", nil},
+		{"ls; ls", []string{"ls"}},
+	}
+	for _, tt := range tests {
+		if got := commandBinaries(tt.command); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("commandBinaries(%q) = %v, esperado %v", tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestMissingTools(t *testing.T) {
+	installed := map[string]bool{"grep": true}
+	lookPath := func(name string) (string, error) {
+		if installed[name] {
+			return "/usr/bin/" + name, nil
+		}
+		return "", errors.New("não encontrado")
+	}
+
+	missing := missingTools([]string{"kubectl get pods | grep api", "docker ps", "kubectl logs x"}, lookPath)
+	if !reflect.DeepEqual(missing, []string{"kubectl", "docker"}) {
+		t.Errorf("Ferramentas ausentes inesperadas: %v", missing)
+	}
+}
@@ -0,0 +1,304 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/diillson/chatcli/config"
+	"github.com/diillson/chatcli/llm/claudeai"
+	"github.com/diillson/chatcli/llm/manager"
+	"github.com/diillson/chatcli/llm/ollama"
+	"github.com/diillson/chatcli/llm/openai"
+	"github.com/diillson/chatcli/llm/stackspotai"
+	"github.com/diillson/chatcli/utils"
+)
+
+// knownProvider descreve um provedor suportado e as variáveis de ambiente exigidas por ele
+type knownProvider struct {
+	name         string
+	credentials  []string
+	modelEnv     string
+	defaultModel string
+	baseURLEnv   string
+	// baseURL é a URL fixa dos provedores compatíveis com a OpenAI registrados com /providers add
+	baseURL string
+	// defaultBaseURL é o endpoint do cliente usado sem baseURLEnv, verificado por /doctor
+	defaultBaseURL string
+	// normalizeURL completa o valor de baseURLEnv como o cliente faz, como o OLLAMA_HOST sem esquema
+	normalizeURL func(string) (string, error)
+}
+
+var knownProviders = []knownProvider{
+	{name: "OPENAI", credentials: []string{"OPENAI_API_KEY"}, modelEnv: "OPENAI_MODEL", defaultModel: defaultOpenAIModel, baseURLEnv: "OPENAI_BASE_URL", defaultBaseURL: openai.DefaultBaseURL},
+	{name: "STACKSPOT", credentials: []string{"CLIENT_ID", "CLIENT_SECRET"}, defaultModel: "StackSpotAI", defaultBaseURL: stackspotai.BaseURL},
+	{name: "CLAUDEAI", credentials: []string{"CLAUDEAI_API_KEY"}, modelEnv: "CLAUDEAI_MODEL", defaultModel: defaultClaudeAIModel, baseURLEnv: "CLAUDEAI_BASE_URL", defaultBaseURL: claudeai.DefaultBaseURL},
+	{name: "OLLAMA", modelEnv: "OLLAMA_MODEL", defaultModel: defaultOllamaModel, baseURLEnv: "OLLAMA_HOST", defaultBaseURL: ollama.DefaultHost, normalizeURL: ollama.NormalizeHost},
+}
+
+// endpoint retorna a URL efetiva do provedor: a de baseURLEnv, a registrada ou a padrão
+func (p knownProvider) endpoint() string {
+	if p.baseURLEnv != "" {
+		if value := os.Getenv(p.baseURLEnv); value != "" {
+			if p.normalizeURL != nil {
+				if normalized, err := p.normalizeURL(value); err == nil {
+					return normalized
+				}
+			}
+			return value
+		}
+	}
+	if p.baseURL != "" {
+		return p.baseURL
+	}
+	return p.defaultBaseURL
+}
+
+// providersUsage é exibido quando /providers recebe argumentos inválidos
+const providersUsage = "Uso: /providers [list] | /providers add <nome> --base-url <url> [--api-key-env <VARIÁVEL>] --model <modelo> | /providers remove <nome>"
+
+// allProviders retorna os provedores embutidos seguidos dos registrados com /providers add
+func allProviders() []knownProvider {
+	providers := append([]knownProvider(nil), knownProviders...)
+	path, err := config.ProvidersPath()
+	if err != nil {
+		return providers
+	}
+	custom, err := config.LoadCustomProviders(path)
+	if err != nil {
+		return providers
+	}
+	for _, p := range custom {
+		provider := knownProvider{name: p.Name, defaultModel: p.Model, baseURL: p.BaseURL}
+		if p.APIKeyEnv != "" {
+			provider.credentials = []string{p.APIKeyEnv}
+		}
+		providers = append(providers, provider)
+	}
+	return providers
+}
+
+// credentialAlternatives lista variáveis que podem substituir uma credencial, como a lista de chaves
+// em rodízio no lugar de uma única chave
+var credentialAlternatives = map[string]string{
+	"OPENAI_API_KEY": "OPENAI_API_KEYS",
+}
+
+// credentialSet indica se a credencial, ou sua alternativa, está definida
+func credentialSet(env string) bool {
+	if os.Getenv(env) != "" {
+		return true
+	}
+	alt, ok := credentialAlternatives[env]
+	return ok && os.Getenv(alt) != ""
+}
+
+// providerStatus é o estado de um provedor exibido por /switch --list
+type providerStatus struct {
+	name         string
+	available    bool
+	active       bool
+	model        string
+	endpoint     string
+	missingCreds []string
+}
+
+// providerStatuses monta o estado de cada provedor conhecido a partir dos provedores disponíveis no manager
+func providerStatuses(available []string, active, activeModel string) []providerStatus {
+	availableSet := make(map[string]bool, len(available))
+	for _, p := range available {
+		availableSet[p] = true
+	}
+
+	providers := allProviders()
+	statuses := make([]providerStatus, 0, len(providers))
+	for _, p := range providers {
+		status := providerStatus{
+			name:      p.name,
+			available: availableSet[p.name],
+			active:    p.name == active,
+			model:     p.defaultModel,
+		}
+		if p.modelEnv != "" {
+			status.model = utils.GetEnvOrDefault(p.modelEnv, p.defaultModel)
+		}
+		if p.baseURLEnv != "" {
+			status.endpoint = os.Getenv(p.baseURLEnv)
+		}
+		if p.baseURL != "" {
+			status.endpoint = p.baseURL
+		}
+		if status.active && activeModel != "" {
+			status.model = activeModel
+		}
+		for _, env := range p.credentials {
+			if !credentialSet(env) {
+				status.missingCreds = append(status.missingCreds, env)
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// showProviders exibe os provedores conhecidos, suas credenciais e qual está ativo
+func (cli *ChatCLI) showProviders() {
+	statuses := providerStatuses(cli.manager.GetAvailableProviders(), cli.provider, cli.model)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\tPROVEDOR\tMODELO\tENDPOINT\tCREDENCIAIS")
+	for _, s := range statuses {
+		marker := ""
+		if s.active {
+			marker = "*"
+		}
+		creds := "ok"
+		if len(s.missingCreds) > 0 {
+			creds = "faltando: " + strings.Join(s.missingCreds, ", ")
+		} else if !s.available {
+			creds = "definidas, mas provedor indisponível"
+		}
+		endpoint := s.endpoint
+		if endpoint == "" {
+			endpoint = "padrão"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", marker, s.name, s.model, endpoint, creds)
+	}
+	w.Flush()
+	fmt.Println("* provedor ativo. Defina as variáveis faltantes no .env e use /reload para habilitar um provedor.")
+}
+
+// handleProvidersCommand trata /providers [list], /providers add e /providers remove
+func (cli *ChatCLI) handleProvidersCommand(userInput string) {
+	args := strings.Fields(userInput)
+	switch {
+	case len(args) == 1 || len(args) == 2 && args[1] == "list":
+		cli.showProviders()
+	case args[1] == "add":
+		cli.addCustomProvider(args[2:])
+	case len(args) == 3 && args[1] == "remove":
+		cli.removeCustomProvider(args[2])
+	default:
+		fmt.Println(providersUsage)
+	}
+}
+
+// parseProviderAddArgs interpreta '<nome> --base-url <url> [--api-key-env <VARIÁVEL>] --model <modelo>'
+func parseProviderAddArgs(args []string) (config.CustomProvider, error) {
+	var p config.CustomProvider
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--base-url", "--api-key-env", "--model":
+			if i+1 >= len(args) {
+				return p, fmt.Errorf("valor ausente para %s", args[i])
+			}
+			switch args[i] {
+			case "--base-url":
+				p.BaseURL = args[i+1]
+			case "--api-key-env":
+				p.APIKeyEnv = args[i+1]
+			case "--model":
+				p.Model = args[i+1]
+			}
+			i++
+		default:
+			if p.Name != "" || strings.HasPrefix(args[i], "-") {
+				return p, fmt.Errorf("argumento inesperado: %s", args[i])
+			}
+			p.Name = args[i]
+		}
+	}
+	if p.Name == "" {
+		return p, fmt.Errorf("informe o nome do provedor")
+	}
+	return p, p.Validate()
+}
+
+// addCustomProvider grava o provedor em CHATCLI_PROVIDERS_FILE e o disponibiliza na sessão. Um
+// provedor com o mesmo nome é substituído.
+func (cli *ChatCLI) addCustomProvider(args []string) {
+	p, err := parseProviderAddArgs(args)
+	if err != nil {
+		fmt.Println("Erro:", err)
+		fmt.Println(providersUsage)
+		return
+	}
+	path, err := config.ProvidersPath()
+	if err != nil {
+		fmt.Println("Erro:", err)
+		return
+	}
+	providers, err := config.LoadCustomProviders(path)
+	if err != nil {
+		fmt.Println("Erro:", err)
+		return
+	}
+
+	replaced := false
+	for i := range providers {
+		if providers[i].Name == p.Name {
+			providers[i], replaced = p, true
+		}
+	}
+	if !replaced {
+		providers = append(providers, p)
+	}
+	if err := config.SaveCustomProviders(path, providers); err != nil {
+		fmt.Println("Erro:", err)
+		return
+	}
+	action := "registrado"
+	if replaced {
+		action = "atualizado"
+	}
+	fmt.Printf("Provedor %s %s em %s (%s, modelo padrão %s).\n", p.Name, action, path, p.BaseURL, p.Model)
+
+	registry, ok := cli.manager.(manager.CustomProviderRegistry)
+	if !ok {
+		fmt.Println("Use /reload para disponibilizá-lo nesta sessão.")
+		return
+	}
+	if err := registry.RegisterCustomProvider(p); err != nil {
+		fmt.Printf("Aviso: %v. Defina a variável no .env e use /reload.\n", err)
+		return
+	}
+	fmt.Printf("Disponível em /switch, /switch --model %s/<modelo> e @provider %s.\n", p.Name, p.Name)
+}
+
+// removeCustomProvider remove o provedor de CHATCLI_PROVIDERS_FILE e da sessão
+func (cli *ChatCLI) removeCustomProvider(name string) {
+	name = strings.ToUpper(name)
+	path, err := config.ProvidersPath()
+	if err != nil {
+		fmt.Println("Erro:", err)
+		return
+	}
+	providers, err := config.LoadCustomProviders(path)
+	if err != nil {
+		fmt.Println("Erro:", err)
+		return
+	}
+
+	kept := providers[:0]
+	for _, p := range providers {
+		if p.Name != name {
+			kept = append(kept, p)
+		}
+	}
+	if len(kept) == len(providers) {
+		fmt.Printf("Provedor %s não está registrado. Apenas provedores adicionados com /providers add podem ser removidos.\n", name)
+		return
+	}
+	if err := config.SaveCustomProviders(path, kept); err != nil {
+		fmt.Println("Erro:", err)
+		return
+	}
+	if registry, ok := cli.manager.(manager.CustomProviderRegistry); ok {
+		registry.UnregisterCustomProvider(name)
+	}
+	fmt.Printf("Provedor %s removido.\n", name)
+	if cli.provider == name {
+		fmt.Println("Ele continua em uso até a próxima troca com /switch.")
+	}
+}
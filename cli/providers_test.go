@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/diillson/chatcli/config"
+)
+
+func TestProviderStatuses(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "chave")
+	t.Setenv("OPENAI_MODEL", "gpt-4o")
+	t.Setenv("CLIENT_ID", "")
+	t.Setenv("CLIENT_SECRET", "segredo")
+	t.Setenv("CLAUDEAI_API_KEY", "")
+	t.Setenv("OPENAI_BASE_URL", "http://localhost:8000/v1")
+	t.Setenv("CLAUDEAI_BASE_URL", "")
+
+	statuses := providerStatuses([]string{"OPENAI"}, "OPENAI", "")
+	byName := make(map[string]providerStatus)
+	for _, s := range statuses {
+		byName[s.name] = s
+	}
+
+	openai := byName["OPENAI"]
+	if !openai.available || !openai.active || openai.model != "gpt-4o" || openai.endpoint != "http://localhost:8000/v1" || len(openai.missingCreds) != 0 {
+		t.Errorf("Estado inesperado para OPENAI: %+v", openai)
+	}
+	stackspot := byName["STACKSPOT"]
+	if stackspot.available || len(stackspot.missingCreds) != 1 || stackspot.missingCreds[0] != "CLIENT_ID" {
+		t.Errorf("Estado inesperado para STACKSPOT: %+v", stackspot)
+	}
+	claude := byName["CLAUDEAI"]
+	if claude.active || claude.model != defaultClaudeAIModel || len(claude.missingCreds) != 1 {
+		t.Errorf("Estado inesperado para CLAUDEAI: %+v", claude)
+	}
+}
+
+func TestHandleProvidersCommand(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "providers.json")
+	t.Setenv("CHATCLI_PROVIDERS_FILE", path)
+	cli := &ChatCLI{manager: &MockLLMManager{}}
+
+	cli.handleProvidersCommand("/providers add together --base-url https://api.together.xyz/v1 --api-key-env TOGETHER_API_KEY --model meta-llama/Llama-3-8b")
+	providers, err := config.LoadCustomProviders(path)
+	if err != nil || len(providers) != 1 || providers[0].Name != "TOGETHER" || providers[0].APIKeyEnv != "TOGETHER_API_KEY" {
+		t.Fatalf("Provedor não registrado: %v (%v)", providers, err)
+	}
+
+	// O provedor registrado aparece na listagem e é reconhecido como prefixo de modelo
+	var status *providerStatus
+	for _, s := range providerStatuses(nil, "", "") {
+		if s.name == "TOGETHER" {
+			status = &s
+		}
+	}
+	if status == nil || status.endpoint != "https://api.together.xyz/v1" || status.model != "meta-llama/Llama-3-8b" {
+		t.Errorf("Estado inesperado para TOGETHER: %+v", status)
+	}
+	if r := parseModelTarget("together/outro-modelo"); r.Provider != "TOGETHER" || r.Model != "outro-modelo" {
+		t.Errorf("Rota inesperada: %+v", r)
+	}
+	if modelForProvider("TOGETHER") != "meta-llama/Llama-3-8b" {
+		t.Errorf("Modelo padrão inesperado: %s", modelForProvider("TOGETHER"))
+	}
+
+	// Argumentos inválidos não alteram o arquivo
+	cli.handleProvidersCommand("/providers add groq --base-url ftp://x --model m")
+	if providers, _ := config.LoadCustomProviders(path); len(providers) != 1 {
+		t.Errorf("Provedor inválido não deveria ser gravado: %v", providers)
+	}
+
+	cli.handleProvidersCommand("/providers remove together")
+	if providers, _ := config.LoadCustomProviders(path); len(providers) != 0 {
+		t.Errorf("Provedor não removido: %v", providers)
+	}
+}
@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// recordedCommand é um comando executado com @command durante a sessão, guardado para /replay
+type recordedCommand struct {
+	command string
+	opts    commandOptions
+}
+
+// replayOptions são as flags aceitas por /replay
+type replayOptions struct {
+	scriptPath      string
+	continueOnError bool
+	skipPreflight   bool
+}
+
+// parseReplayArgs interpreta /replay [--to <arquivo>] [--continue] [--skip-preflight]
+func parseReplayArgs(userInput string) (replayOptions, error) {
+	var opts replayOptions
+	args := strings.Fields(userInput)
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case "--to":
+			if i+1 >= len(args) {
+				return opts, fmt.Errorf("valor ausente para --to")
+			}
+			opts.scriptPath = args[i+1]
+			i++
+		case "--continue":
+			opts.continueOnError = true
+		case "--skip-preflight":
+			opts.skipPreflight = true
+		default:
+			return opts, fmt.Errorf("argumento desconhecido: %s", args[i])
+		}
+	}
+	return opts, nil
+}
+
+// replayScript gera um script de shell que reexecuta os comandos na mesma ordem, preservando
+// o diretório e o tempo limite de cada um
+func replayScript(commands []recordedCommand, continueOnError bool) string {
+	var builder strings.Builder
+	builder.WriteString("#!/bin/sh\n")
+	builder.WriteString("# Comandos executados em uma sessão do ChatCLI\n")
+	if !continueOnError {
+		builder.WriteString("set -e\n")
+	}
+	builder.WriteString("\n")
+
+	for _, c := range commands {
+		line := c.command
+		switch c.opts.shell {
+		case "":
+		case "pwsh":
+			line = "pwsh -NoProfile -Command " + shellQuote(line)
+		default:
+			line = c.opts.shell + " -c " + shellQuote(line)
+		}
+		if c.opts.timeout > 0 {
+			line = fmt.Sprintf("timeout %d sh -c %s", int(c.opts.timeout.Seconds()+0.5), shellQuote(line))
+		}
+		if c.opts.dir != "" {
+			line = fmt.Sprintf("(cd %s && %s)", shellQuote(c.opts.dir), line)
+		}
+		if c.opts.interactive {
+			builder.WriteString("# comando interativo\n")
+		}
+		builder.WriteString(line + "\n")
+	}
+	return builder.String()
+}
+
+// shellQuote envolve o texto em aspas simples, escapando as aspas existentes
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// handleReplayCommand trata o comando /replay, que reexecuta (ou grava em um script) os comandos da sessão
+func (cli *ChatCLI) handleReplayCommand(userInput string) {
+	opts, err := parseReplayArgs(userInput)
+	if err != nil {
+		fmt.Println("Erro:", err)
+		fmt.Println("Uso: /replay [--to <arquivo.sh>] [--continue] [--skip-preflight]")
+		return
+	}
+
+	if len(cli.executedCommands) == 0 {
+		fmt.Println("Nenhum comando foi executado com @command nesta sessão.")
+		return
+	}
+
+	if opts.scriptPath != "" {
+		script := replayScript(cli.executedCommands, opts.continueOnError)
+		if err := os.WriteFile(opts.scriptPath, []byte(script), 0755); err != nil {
+			cli.logger.Error("Erro ao gravar o script de replay", zap.Error(err))
+			fmt.Println("Erro ao gravar o script:", err)
+			return
+		}
+		fmt.Printf("%d comando(s) gravado(s) em %s\n", len(cli.executedCommands), opts.scriptPath)
+		return
+	}
+
+	fmt.Println("Comandos executados nesta sessão:")
+	for i, c := range cli.executedCommands {
+		fmt.Printf("  %d. %s\n", i+1, describeRecordedCommand(c))
+	}
+
+	if !opts.skipPreflight && !preflight(preflightCommands(cli.executedCommands)) {
+		return
+	}
+
+	if !cli.confirm(fmt.Sprintf("Executar novamente os %d comando(s)? (s/N): ", len(cli.executedCommands))) {
+		fmt.Println("Replay cancelado.")
+		return
+	}
+
+	// Copiar a lista, pois os comandos reexecutados não devem ser registrados novamente
+	commands := append([]recordedCommand(nil), cli.executedCommands...)
+	failures := 0
+	for i, c := range commands {
+		fmt.Printf("\n[%d/%d] Executando: %s\n", i+1, len(commands), describeRecordedCommand(c))
+		runOpts := c.opts
+		runOpts.sendToAI = false
+		if err := cli.runDirectCommand(c.command, runOpts, ""); err != nil {
+			failures++
+			if !opts.continueOnError {
+				fmt.Printf("Replay interrompido no comando %d: %v\n", i+1, err)
+				fmt.Println("Use '/replay --continue' para seguir mesmo após falhas.")
+				return
+			}
+		}
+	}
+	fmt.Printf("\nReplay concluído: %d comando(s), %d falha(s).\n", len(commands), failures)
+}
+
+// preflightCommands retorna os comandos verificados antes do replay. Como em @command, os executados com
+// --shell pwsh ficam de fora, pois os cmdlets do PowerShell não são executáveis no PATH.
+func preflightCommands(recorded []recordedCommand) []string {
+	var commands []string
+	for _, c := range recorded {
+		if c.opts.shell != "pwsh" {
+			commands = append(commands, c.command)
+		}
+	}
+	return commands
+}
+
+// describeRecordedCommand descreve o comando com as opções relevantes para a reexecução
+func describeRecordedCommand(c recordedCommand) string {
+	var parts []string
+	if c.opts.interactive {
+		parts = append(parts, "interativo")
+	}
+	if c.opts.dir != "" {
+		parts = append(parts, "dir: "+c.opts.dir)
+	}
+	if c.opts.shell != "" {
+		parts = append(parts, "shell: "+c.opts.shell)
+	}
+	if c.opts.timeout > 0 {
+		parts = append(parts, "timeout: "+c.opts.timeout.String())
+	}
+	if len(parts) == 0 {
+		return c.command
+	}
+	return fmt.Sprintf("%s (%s)", c.command, strings.Join(parts, ", "))
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReplayScript(t *testing.T) {
+	commands := []recordedCommand{
+		{command: "go build ./..."},
+		{command: "make test", opts: commandOptions{dir: "/tmp/projeto", timeout: 2 * time.Minute}},
+		{command: "echo 'olá'"},
+		{command: "Get-Date", opts: commandOptions{shell: "pwsh"}},
+	}
+
+	script := replayScript(commands, false)
+	expected := []string{
+		"#!/bin/sh",
+		"set -e",
+		"go build ./...",
+		"(cd '/tmp/projeto' && timeout 120 sh -c 'make test')",
+		"echo 'olá'",
+		"pwsh -NoProfile -Command 'Get-Date'",
+	}
+	for _, line := range expected {
+		if !strings.Contains(script, line) {
+			t.Errorf("Script não contém %q:\n%s", line, script)
+		}
+	}
+
+	if strings.Contains(replayScript(commands, true), "set -e") {
+		t.Error("Script com --continue não deveria usar set -e")
+	}
+	if got := shellQuote("it's"); got != `'it'\''s'` {
+		t.Errorf("Aspas inesperadas: %s", got)
+	}
+}
+
+func TestParseReplayArgs(t *testing.T) {
+	opts, err := parseReplayArgs("/replay --to script.sh --continue")
+	if err != nil || opts.scriptPath != "script.sh" || !opts.continueOnError {
+		t.Errorf("Opções inesperadas: %+v (erro: %v)", opts, err)
+	}
+	if _, err := parseReplayArgs("/replay --to"); err == nil {
+		t.Error("Esperado erro para --to sem arquivo")
+	}
+	if _, err := parseReplayArgs("/replay --foo"); err == nil {
+		t.Error("Esperado erro para argumento desconhecido")
+	}
+}
+
+func TestPreflightCommands(t *testing.T) {
+	commands := preflightCommands([]recordedCommand{
+		{command: "go build ./..."},
+		{command: "Get-Date", opts: commandOptions{shell: "pwsh"}},
+		{command: "make test", opts: commandOptions{shell: "bash"}},
+	})
+	if len(commands) != 2 || commands[0] != "go build ./..." || commands[1] != "make test" {
+		t.Errorf("Comandos pwsh não deveriam passar pela verificação: %v", commands)
+	}
+}
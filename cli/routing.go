@@ -0,0 +1,227 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/diillson/chatcli/models"
+	"github.com/diillson/chatcli/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultRoutingFile é o arquivo de roteamento usado quando CHATCLI_ROUTING_FILE não está definido
+	defaultRoutingFile = "~/.chatcli/routing.json"
+	// autoProvider é o valor de LLM_PROVIDER que ativa o roteamento automático
+	autoProvider = "AUTO"
+)
+
+// route é o destino de um tipo de tarefa; um modelo vazio usa o modelo configurado para o provedor
+type route struct {
+	Provider string `json:"provider"`
+	Model    string `json:"model,omitempty"`
+}
+
+// routingConfig associa cada tipo de tarefa a um provedor, com os limites usados na classificação
+type routingConfig struct {
+	LongContext       route `json:"long_context"`
+	Code              route `json:"code"`
+	Quick             route `json:"quick"`
+	Default           route `json:"default"`
+	LongContextTokens int   `json:"long_context_tokens"`
+	QuickMaxChars     int   `json:"quick_max_chars"`
+}
+
+// defaultRouting é usado sem arquivo de roteamento: contexto longo e código vão para a ClaudeAI, que tem
+// janela de 200k tokens, e perguntas rápidas para o gpt-4o-mini. Rotas de provedores não configurados
+// são ignoradas.
+var defaultRouting = routingConfig{
+	LongContext:       route{Provider: "CLAUDEAI"},
+	Code:              route{Provider: "CLAUDEAI"},
+	Quick:             route{Provider: "OPENAI", Model: "gpt-4o-mini"},
+	LongContextTokens: 20000,
+	QuickMaxChars:     200,
+}
+
+// loadRouting lê o arquivo de roteamento, como {"code": {"provider": "OPENAI", "model": "gpt-4o"}}.
+// Os campos omitidos mantêm os valores padrão, e um arquivo inexistente resulta no roteamento padrão.
+func loadRouting(path string) (routingConfig, error) {
+	if path == "" {
+		path = defaultRoutingFile
+	}
+	expanded, err := utils.ExpandPath(path)
+	if err != nil {
+		return defaultRouting, err
+	}
+	data, err := os.ReadFile(expanded)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return defaultRouting, nil
+		}
+		return defaultRouting, fmt.Errorf("erro ao ler o roteamento em %s: %w", expanded, err)
+	}
+
+	cfg := defaultRouting
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return defaultRouting, fmt.Errorf("erro ao decodificar o roteamento em %s: %w", expanded, err)
+	}
+	for _, r := range []*route{&cfg.LongContext, &cfg.Code, &cfg.Quick, &cfg.Default} {
+		r.Provider = strings.ToUpper(r.Provider)
+	}
+	return cfg, nil
+}
+
+// codePattern identifica prompts sobre código: blocos de código, trechos com sintaxe comum ou
+// pedidos típicos de programação
+var codePattern = regexp.MustCompile("(?i)```|\\bfunc \\w+\\(|\\bdef \\w+\\(|\\bclass \\w+|stack ?trace|traceback|" +
+	"\\b(refator\\w*|debug\\w*|compil\\w*|c[oó]digo|fun[cç][aã]o|bug|regex|teste unit[aá]rio|unit test)\\b")
+
+// classifyPrompt decide o tipo da tarefa a partir do prompt, do contexto injetado e do tamanho da
+// conversa, retornando também o motivo, registrado no log e exibido ao usuário
+func classifyPrompt(prompt, additionalContext string, historyTokens int, cfg routingConfig) (task string, reason string) {
+	tokens := historyTokens + estimateTokens([]models.Message{{Content: prompt + additionalContext}})
+	if tokens >= cfg.LongContextTokens {
+		return "long_context", fmt.Sprintf("~%d tokens de contexto (limite %d)", tokens, cfg.LongContextTokens)
+	}
+	if match := codePattern.FindString(prompt + additionalContext); match != "" {
+		return "code", fmt.Sprintf("tarefa de código (%q)", strings.TrimSpace(match))
+	}
+	if additionalContext == "" && len([]rune(prompt)) <= cfg.QuickMaxChars {
+		return "quick", fmt.Sprintf("prompt curto sem contexto (até %d caracteres)", cfg.QuickMaxChars)
+	}
+	return "default", "nenhuma heurística se aplica"
+}
+
+// routeFor retorna a rota do tipo de tarefa
+func (cfg routingConfig) routeFor(task string) route {
+	switch task {
+	case "long_context":
+		return cfg.LongContext
+	case "code":
+		return cfg.Code
+	case "quick":
+		return cfg.Quick
+	default:
+		return cfg.Default
+	}
+}
+
+// providerOverridePattern corresponde a @provider <NOME>[:<modelo>], que escolhe o provedor (ou o apelido
+// de /alias) de um único prompt
+var providerOverridePattern = regexp.MustCompile(`(?i)(?:^|\s)@provider\s+([A-Za-z][A-Za-z0-9_-]*)(?::(\S+))?`)
+
+// extractProviderOverride remove @provider do prompt e retorna a rota pedida, se houver
+func extractProviderOverride(input string) (string, *route) {
+	match := providerOverridePattern.FindStringSubmatch(input)
+	if match == nil {
+		return input, nil
+	}
+	input = strings.TrimSpace(providerOverridePattern.ReplaceAllString(input, " "))
+	return input, &route{Provider: strings.ToUpper(match[1]), Model: match[2]}
+}
+
+// modelForProvider retorna o modelo configurado por variável de ambiente para o provedor, ou o padrão
+func modelForProvider(provider string) string {
+	for _, p := range allProviders() {
+		if p.name == provider && p.modelEnv != "" {
+			return utils.GetEnvOrDefault(p.modelEnv, p.defaultModel)
+		}
+		// Os provedores registrados com /providers add têm o modelo padrão fixado no registro
+		if p.name == provider && p.baseURL != "" {
+			return p.defaultModel
+		}
+	}
+	return ""
+}
+
+// isAvailable indica se o provedor está configurado
+func (cli *ChatCLI) isAvailable(provider string) bool {
+	for _, p := range cli.manager.GetAvailableProviders() {
+		if p == provider {
+			return true
+		}
+	}
+	return false
+}
+
+// useRoute troca o cliente da sessão para a rota informada até que a função retornada seja chamada.
+// Retorna nil quando a rota já é a atual ou o cliente não pode ser obtido.
+func (cli *ChatCLI) useRoute(r route, reason string) func() {
+	model := r.Model
+	if model == "" {
+		model = modelForProvider(r.Provider)
+	}
+	if r.Provider == cli.provider && model == cli.model {
+		cli.logger.Info("Roteamento mantém o provedor atual", zap.String("provider", cli.provider), zap.String("motivo", reason))
+		return nil
+	}
+
+	newClient, err := cli.manager.GetClient(r.Provider, model)
+	if err != nil {
+		cli.logger.Warn("Rota ignorada", zap.String("provider", r.Provider), zap.Error(err))
+		fmt.Printf("Aviso: não foi possível usar %s (%v); mantendo %s.\n", r.Provider, err, cli.provider)
+		return nil
+	}
+
+	previousClient, previousProvider, previousModel := cli.client, cli.provider, cli.model
+	cli.client, cli.provider, cli.model = newClient, r.Provider, model
+	cli.applyGenerationParams()
+	cli.logger.Info("Prompt roteado", zap.String("provider", r.Provider), zap.String("model", newClient.GetModelName()), zap.String("motivo", reason))
+	fmt.Printf("Usando %s (%s): %s\n", newClient.GetModelName(), r.Provider, reason)
+
+	return func() {
+		cli.client, cli.provider, cli.model = previousClient, previousProvider, previousModel
+		cli.applyGenerationParams()
+	}
+}
+
+// autoRoute escolhe o provedor do prompt no modo automático. Rotas de provedores não configurados
+// caem na rota padrão, e sem ela o provedor atual é mantido.
+func (cli *ChatCLI) autoRoute(prompt, additionalContext string) func() {
+	cfg, err := loadRouting(os.Getenv("CHATCLI_ROUTING_FILE"))
+	if err != nil {
+		cli.logger.Warn("Roteamento personalizado ignorado", zap.Error(err))
+		fmt.Println("Aviso: roteamento personalizado ignorado:", err)
+	}
+
+	task, reason := classifyPrompt(prompt, additionalContext, estimateTokens(cli.historyForRequest()), cfg)
+	r := cfg.routeFor(task)
+	if r.Provider == "" || !cli.isAvailable(r.Provider) {
+		if task != "default" && r.Provider != "" {
+			reason += fmt.Sprintf("; %s não está configurado", r.Provider)
+		}
+		r = cfg.Default
+	}
+	if r.Provider == "" || !cli.isAvailable(r.Provider) {
+		cli.logger.Info("Roteamento mantém o provedor atual", zap.String("tarefa", task), zap.String("motivo", reason))
+		return nil
+	}
+	return cli.useRoute(r, reason)
+}
+
+// handleAutoSwitch trata /switch --auto [off], que ativa ou desativa o roteamento automático
+func (cli *ChatCLI) handleAutoSwitch(off bool) {
+	cli.autoRouting = !off
+	if off {
+		fmt.Printf("Roteamento automático desativado. Você está conversando com %s (%s).\n", cli.client.GetModelName(), cli.provider)
+		return
+	}
+	fmt.Println("Roteamento automático ativado: cada prompt será enviado ao provedor indicado pelas heurísticas (contexto longo, código ou pergunta rápida).")
+	fmt.Println("Use @provider <NOME>[:<modelo>] para escolher o provedor de um prompt e /switch --auto off para desativar.")
+}
+
+// initialAutoProvider escolhe o provedor inicial do modo automático: o da rota padrão, se configurado,
+// ou o primeiro disponível
+func (cli *ChatCLI) initialAutoProvider() string {
+	cfg, _ := loadRouting(os.Getenv("CHATCLI_ROUTING_FILE"))
+	if cfg.Default.Provider != "" && cli.isAvailable(cfg.Default.Provider) {
+		return cfg.Default.Provider
+	}
+	if available := cli.manager.GetAvailableProviders(); len(available) > 0 {
+		return available[0]
+	}
+	return "STACKSPOT"
+}
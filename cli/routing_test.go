@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestClassifyPrompt(t *testing.T) {
+	cfg := defaultRouting
+	cases := []struct {
+		prompt, context string
+		historyTokens   int
+		want            string
+	}{
+		{"qual a capital da França?", "", 0, "quick"},
+		{"refatore esta função para usar generics", "", 0, "code"},
+		{"explique o arquivo", "\n```go\npackage main\n```\n", 0, "code"},
+		{"resuma a conversa", "", 25000, "long_context"},
+		{strings.Repeat("conte uma história sobre o mar ", 10), "", 0, "default"},
+	}
+	for _, c := range cases {
+		if task, reason := classifyPrompt(c.prompt, c.context, c.historyTokens, cfg); task != c.want || reason == "" {
+			t.Errorf("classifyPrompt(%q) = %s (%s), esperado %s", c.prompt, task, reason, c.want)
+		}
+	}
+}
+
+func TestLoadRouting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "routing.json")
+	if cfg, err := loadRouting(path); err != nil || cfg != defaultRouting {
+		t.Errorf("Esperado o roteamento padrão sem arquivo, obteve %+v (erro: %v)", cfg, err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"code": {"provider": "openai", "model": "gpt-4o"}, "long_context_tokens": 50000}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := loadRouting(path)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if cfg.Code != (route{Provider: "OPENAI", Model: "gpt-4o"}) || cfg.LongContextTokens != 50000 {
+		t.Errorf("Roteamento inesperado: %+v", cfg)
+	}
+	if cfg.Quick != defaultRouting.Quick || cfg.QuickMaxChars != defaultRouting.QuickMaxChars {
+		t.Errorf("Campos omitidos deveriam manter o padrão: %+v", cfg)
+	}
+}
+
+func TestExtractProviderOverride(t *testing.T) {
+	input, override := extractProviderOverride("@provider claudeai:claude-3-opus revise o código")
+	if input != "revise o código" || override == nil || *override != (route{Provider: "CLAUDEAI", Model: "claude-3-opus"}) {
+		t.Errorf("Resultado inesperado: %q %+v", input, override)
+	}
+	if input, override := extractProviderOverride("envie para o email@provider.com"); override != nil || input != "envie para o email@provider.com" {
+		t.Errorf("Não deveria reconhecer @provider dentro de outra palavra: %q %+v", input, override)
+	}
+}
+
+func TestUseRouteRestoresProvider(t *testing.T) {
+	cli := &ChatCLI{manager: &MockLLMManager{}, logger: zap.NewNop(), provider: "OPENAI", model: "gpt-4o-mini"}
+	original := &MockLLMClient{}
+	cli.client = original
+
+	restore := cli.useRoute(route{Provider: "CLAUDEAI", Model: "claude-3-opus"}, "teste")
+	if restore == nil || cli.provider != "CLAUDEAI" || cli.model != "claude-3-opus" || cli.client == original {
+		t.Fatalf("Esperado trocar para a rota, provedor atual %s (%s)", cli.provider, cli.model)
+	}
+	restore()
+	if cli.provider != "OPENAI" || cli.model != "gpt-4o-mini" || cli.client != original {
+		t.Errorf("Esperado restaurar o provedor anterior, obteve %s (%s)", cli.provider, cli.model)
+	}
+
+	if cli.useRoute(route{Provider: "OPENAI", Model: "gpt-4o-mini"}, "teste") != nil {
+		t.Error("A rota atual não deveria trocar o cliente")
+	}
+}
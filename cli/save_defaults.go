@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/diillson/chatcli/config"
+	"github.com/diillson/chatcli/models"
+	"go.uber.org/zap"
+)
+
+// configChange é uma alteração a ser gravada no .env; um valor vazio remove a chave
+type configChange struct {
+	key   string
+	value string
+}
+
+// defaultsToSave monta as alterações que tornam provedor, modelo e parâmetros de geração da sessão
+// o padrão das próximas execuções. Parâmetros não definidos são removidos do .env.
+func defaultsToSave(provider, model string, params models.GenerationParams, extra map[string]string) []configChange {
+	changes := []configChange{{key: "LLM_PROVIDER", value: provider}}
+	for _, p := range knownProviders {
+		if p.name == provider && p.modelEnv != "" && model != "" {
+			changes = append(changes, configChange{key: p.modelEnv, value: model})
+		}
+	}
+	for _, key := range generationEnvKeys {
+		var value string
+		if v := *key.field(&params); v != nil {
+			value = strconv.FormatFloat(*v, 'f', -1, 64)
+		}
+		changes = append(changes, configChange{key: key.name, value: value})
+	}
+	var maxTokens string
+	if params.MaxTokens != nil {
+		maxTokens = strconv.Itoa(*params.MaxTokens)
+	}
+	changes = append(changes, configChange{key: maxTokensEnv, value: maxTokens})
+	for _, name := range []string{"SLUG_NAME", "TENANT_NAME"} {
+		if value, ok := extra[name]; ok {
+			changes = append(changes, configChange{key: name, value: value})
+		}
+	}
+	return changes
+}
+
+// saveDefaults mostra o que será gravado no .env (respeitando CHATCLI_DOTENV) e, após confirmação,
+// grava as configurações da sessão como padrão
+func (cli *ChatCLI) saveDefaults(extra map[string]string) {
+	path := config.DotenvPath()
+	changes := defaultsToSave(cli.provider, cli.model, cli.generationParams, extra)
+
+	fmt.Printf("As seguintes configurações serão gravadas em %s:\n", path)
+	for _, c := range changes {
+		if c.value == "" {
+			fmt.Printf("  %s (removida)\n", c.key)
+		} else {
+			fmt.Printf("  %s=%s\n", c.key, c.value)
+		}
+	}
+	if !cli.confirm("Confirmar? (s/N): ") {
+		fmt.Println("Nada foi gravado.")
+		return
+	}
+
+	for _, c := range changes {
+		var err error
+		if c.value == "" {
+			_, err = config.Unset(path, c.key)
+		} else {
+			err = config.Set(path, c.key, c.value)
+		}
+		if err != nil {
+			cli.logger.Error("Erro ao gravar a configuração padrão", zap.String("key", c.key), zap.Error(err))
+			fmt.Println("Erro ao gravar a configuração:", err)
+			return
+		}
+	}
+	fmt.Println("Configurações gravadas como padrão para as próximas execuções.")
+	if cli.project != nil && (cli.project.Provider != "" || cli.project.Model != "") {
+		fmt.Printf("Atenção: %s define provedor/modelo e continua tendo precedência neste projeto.\n", cli.project.Path)
+	}
+}
@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/diillson/chatcli/models"
+)
+
+func TestDefaultsToSave(t *testing.T) {
+	temperature := 0.2
+	changes := defaultsToSave("CLAUDEAI", "claude-3-opus", models.GenerationParams{Temperature: &temperature}, map[string]string{"SLUG_NAME": "meu-slug"})
+
+	want := []configChange{
+		{key: "LLM_PROVIDER", value: "CLAUDEAI"},
+		{key: "CLAUDEAI_MODEL", value: "claude-3-opus"},
+		{key: "CHATCLI_TEMPERATURE", value: "0.2"},
+		{key: "CHATCLI_TOP_P"},
+		{key: "CHATCLI_PRESENCE_PENALTY"},
+		{key: "CHATCLI_FREQUENCY_PENALTY"},
+		{key: "CHATCLI_MAX_TOKENS"},
+		{key: "SLUG_NAME", value: "meu-slug"},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("Alterações inesperadas:\n%+v\nesperado:\n%+v", changes, want)
+	}
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/diillson/chatcli/utils"
+	"go.uber.org/zap"
+)
+
+// scrubEnabled indica se CHATCLI_SCRUB_SECRETS pede que os segredos sejam ocultados do contexto
+func scrubEnabled() bool {
+	enabled, err := strconv.ParseBool(os.Getenv("CHATCLI_SCRUB_SECRETS"))
+	return err == nil && enabled
+}
+
+// scrubAllowlist lê CHATCLI_SCRUB_ALLOWLIST, os valores que não devem ser ocultados (falsos positivos)
+func scrubAllowlist() []string {
+	var allowlist []string
+	for _, value := range strings.Split(os.Getenv("CHATCLI_SCRUB_ALLOWLIST"), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			allowlist = append(allowlist, value)
+		}
+	}
+	return allowlist
+}
+
+// scrubSecrets oculta os segredos do texto antes que ele seja enviado ao provedor, se
+// CHATCLI_SCRUB_SECRETS estiver ativo, avisando quantos e de que tipo foram ocultados em origin
+func (cli *ChatCLI) scrubSecrets(text, origin string) string {
+	if text == "" || !scrubEnabled() {
+		return text
+	}
+	scrubbed, found := utils.ScrubSecrets(text, scrubAllowlist())
+	if len(found) == 0 {
+		return text
+	}
+
+	counts := make(map[string]int)
+	for _, kind := range found {
+		counts[kind]++
+	}
+	kinds := make([]string, 0, len(counts))
+	for kind, n := range counts {
+		kinds = append(kinds, fmt.Sprintf("%s: %d", kind, n))
+	}
+	sort.Strings(kinds)
+	cli.logger.Warn("Segredos ocultados antes do envio", zap.String("origem", origin), zap.Strings("tipos", kinds))
+	fmt.Printf("Aviso: %d segredo(s) ocultado(s) em %s antes do envio (%s). Use CHATCLI_SCRUB_ALLOWLIST para falsos positivos.\n",
+		len(found), origin, strings.Join(kinds, ", "))
+	return scrubbed
+}
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// varNamePattern define os nomes aceitos em '@command --as <NOME>' e '@var <NOME>'
+var varNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// varReferencePattern encontra as referências '@var <NOME>' na entrada do usuário
+var varReferencePattern = regexp.MustCompile(`(?i)@var\s+(\S+)`)
+
+// sessionVar é a saída de um comando guardada com '@command --as <NOME>' durante a sessão
+type sessionVar struct {
+	command string
+	output  string
+}
+
+// setVar guarda a saída do comando sob o nome informado, substituindo um valor anterior
+func (cli *ChatCLI) setVar(name, command, output string) {
+	if cli.vars == nil {
+		cli.vars = make(map[string]sessionVar)
+	}
+	cli.vars[name] = sessionVar{command: command, output: output}
+	fmt.Printf("Saída guardada em %s (%d bytes). Use @var %s para incluí-la em um prompt.\n", name, len(output), name)
+}
+
+// processVarCommand substitui as referências '@var <NOME>' pela saída guardada, sem reexecutar o comando
+func (cli *ChatCLI) processVarCommand(userInput string) (string, string) {
+	matches := varReferencePattern.FindAllStringSubmatch(userInput, -1)
+	if len(matches) == 0 {
+		return userInput, ""
+	}
+
+	var additionalContext string
+	included := make(map[string]bool)
+	for _, match := range matches {
+		name := match[1]
+		v, ok := cli.vars[name]
+		if !ok {
+			fmt.Printf("Variável %s não definida. Use /vars para ver as disponíveis.\n", name)
+			continue
+		}
+		if included[name] {
+			continue
+		}
+		included[name] = true
+		additionalContext += fmt.Sprintf("\nSaída do comando '%s' (%s):%s\n```\n%s\n```\n",
+			v.command, name, cli.tagSource("@var "+name), strings.TrimRight(v.output, "\n"))
+	}
+
+	userInput = varReferencePattern.ReplaceAllString(userInput, " ")
+	userInput = strings.TrimSpace(regexp.MustCompile(`\s+`).ReplaceAllString(userInput, " "))
+	return userInput, additionalContext
+}
+
+// handleVarsCommand trata /vars, que lista as saídas guardadas na sessão
+func (cli *ChatCLI) handleVarsCommand() {
+	if len(cli.vars) == 0 {
+		fmt.Println("Nenhuma variável definida. Use @command --as <NOME> <comando> para guardar a saída de um comando.")
+		return
+	}
+	names := make([]string, 0, len(cli.vars))
+	for name := range cli.vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Println("Variáveis da sessão:")
+	for _, name := range names {
+		v := cli.vars[name]
+		fmt.Printf("  %s - %s (%d bytes)\n", name, v.command, len(v.output))
+	}
+}
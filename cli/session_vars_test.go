@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProcessVarCommand(t *testing.T) {
+	cli := &ChatCLI{}
+	cli.setVar("BUILD_LOG", "make build", "erro: x indefinido\n")
+
+	input, context := cli.processVarCommand("explique @var BUILD_LOG e @var BUILD_LOG @var NADA por favor")
+	if input != "explique e por favor" {
+		t.Errorf("Entrada inesperada: %q", input)
+	}
+	if strings.Count(context, "erro: x indefinido") != 1 || !strings.Contains(context, "make build") {
+		t.Errorf("Contexto inesperado:\n%s", context)
+	}
+
+	if input, context := cli.processVarCommand("sem variáveis"); input != "sem variáveis" || context != "" {
+		t.Errorf("Entrada sem @var não deveria mudar: %q %q", input, context)
+	}
+}
+
+func TestParseCommandOptions_as(t *testing.T) {
+	opts, command, err := parseCommandOptions("--as BUILD_LOG make build")
+	if err != nil || opts.as != "BUILD_LOG" || command != "make build" {
+		t.Errorf("Esperado --as BUILD_LOG, obtido %+v, %q, %v", opts, command, err)
+	}
+	for _, input := range []string{"--as", "--as 1LOG ls", "--as build-log ls"} {
+		if _, _, err := parseCommandOptions(input); err == nil {
+			t.Errorf("Esperado erro para %q", input)
+		}
+	}
+}
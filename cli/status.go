@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/diillson/chatcli/models"
+)
+
+// statusEntry é uma linha do painel de /status
+type statusEntry struct {
+	label string
+	value string
+}
+
+// statusEntries reúne o estado atual da sessão exibido por /status
+func (cli *ChatCLI) statusEntries() []statusEntry {
+	model := cli.model
+	if cli.client != nil {
+		model = cli.client.GetModelName()
+	}
+
+	project := "nenhum"
+	if cli.project != nil {
+		project = cli.project.Path
+	}
+
+	systemPrompt := "não"
+	if systemContext := cli.buildSystemContext(); systemContext != "" {
+		systemPrompt = fmt.Sprintf("sim (~%d tokens)", estimateTokens([]models.Message{{Content: systemContext}}))
+	}
+
+	facts := 0
+	if cli.memory != nil {
+		facts = len(cli.memory.Facts())
+	}
+
+	cite := "desativadas"
+	if cli.citeMode {
+		cite = "ativadas"
+	}
+
+	responseFormat := "texto livre"
+	if cli.responseFormat != "" {
+		responseFormat = strings.ToUpper(cli.responseFormat)
+	}
+
+	offline := "desativado"
+	if cli.offline {
+		offline = "ativado"
+	}
+	if len(cli.offlineQueue) > 0 {
+		offline += fmt.Sprintf(", %d prompt(s) na fila", len(cli.offlineQueue))
+	}
+
+	provider := cli.provider
+	if cli.autoRouting {
+		provider += " (roteamento automático)"
+	}
+
+	return []statusEntry{
+		{"Provedor", provider},
+		{"Modelo", model},
+		{"Parâmetros de geração", cli.generationParams.String()},
+		{"Formato da resposta", responseFormat},
+		{"Projeto", project},
+		{"Contexto de sistema", systemPrompt},
+		{"Memória", fmt.Sprintf("%d fato(s)", facts)},
+		{"Histórico", fmt.Sprintf("%d mensagem(ns), estratégia %s", len(cli.history), cli.historyStrategy)},
+		{"Tokens por requisição", fmt.Sprintf("~%d", estimateTokens(cli.historyForRequest()))},
+		{"Variáveis (@var)", fmt.Sprintf("%d", len(cli.vars))},
+		{"Citações (/cite)", cite},
+		{"Tema", markdownStyle()},
+		{"Modo offline", offline},
+	}
+}
+
+// handleStatusCommand trata /status, que resume o estado atual da sessão
+func (cli *ChatCLI) handleStatusCommand() {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, e := range cli.statusEntries() {
+		fmt.Fprintf(w, "%s:\t%s\n", e.label, e.value)
+	}
+	w.Flush()
+}
@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/diillson/chatcli/llm/client"
+	"github.com/diillson/chatcli/models"
+)
+
+func TestStatusEntries(t *testing.T) {
+	temperature := 0.2
+	cli := &ChatCLI{
+		provider:         "OPENAI",
+		client:           &client.MockLLMClient{},
+		generationParams: models.GenerationParams{Temperature: &temperature},
+		history:          []models.Message{{Role: "user", Content: "olá"}, {Role: "assistant", Content: "oi"}},
+		historyStrategy:  historyStrategy{name: historyStrategyLastN, lastN: 5},
+		citeMode:         true,
+	}
+
+	values := make(map[string]string)
+	for _, e := range cli.statusEntries() {
+		values[e.label] = e.value
+	}
+	if values["Provedor"] != "OPENAI" || values["Contexto de sistema"] != "não" || values["Citações (/cite)"] != "ativadas" {
+		t.Errorf("Estado inesperado: %v", values)
+	}
+	if !strings.Contains(values["Histórico"], "2 mensagem(ns)") || !strings.Contains(values["Histórico"], "last-n") {
+		t.Errorf("Histórico inesperado: %q", values["Histórico"])
+	}
+	if !strings.Contains(values["Parâmetros de geração"], "0.2") {
+		t.Errorf("Parâmetros inesperados: %q", values["Parâmetros de geração"])
+	}
+}
@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diillson/chatcli/models"
+	"go.uber.org/zap"
+)
+
+const (
+	// summaryPrefix marca as mensagens de resumo geradas por /summarize, que não são resumidas novamente
+	summaryPrefix = "[Resumo da conversa anterior]"
+	// summarizeKeepRecent é a quantidade de trocas recentes mantidas literalmente quando N não é informado
+	summarizeKeepRecent = 2
+	// defaultAutoSummarizeThreshold é o limite de tokens estimados usado quando CHATCLI_AUTO_SUMMARIZE=true
+	defaultAutoSummarizeThreshold = 12000
+
+	summarizeInstruction = "Resuma de forma concisa a conversa abaixo, preservando fatos, decisões, nomes de arquivos, " +
+		"trechos de código relevantes e perguntas em aberto. Responda apenas com o resumo, sem introduções."
+)
+
+// isSummaryMessage indica se a mensagem é um resumo gerado por /summarize
+func isSummaryMessage(msg models.Message) bool {
+	return msg.Role == "system" && strings.HasPrefix(msg.Content, summaryPrefix)
+}
+
+// splitSummarizable separa o histórico em duas partes: as mensagens até o último resumo (inclusive),
+// que não são resumidas novamente, e as trocas seguintes, cada uma iniciada por uma mensagem do usuário.
+func splitSummarizable(history []models.Message) ([]models.Message, [][]models.Message) {
+	start := 0
+	for i := len(history) - 1; i >= 0; i-- {
+		if isSummaryMessage(history[i]) {
+			start = i + 1
+			break
+		}
+	}
+
+	var exchanges [][]models.Message
+	for _, msg := range history[start:] {
+		if msg.Role == "user" || len(exchanges) == 0 {
+			exchanges = append(exchanges, nil)
+		}
+		exchanges[len(exchanges)-1] = append(exchanges[len(exchanges)-1], msg)
+	}
+	return history[:start], exchanges
+}
+
+// estimateTokens faz uma estimativa grosseira (cerca de 4 caracteres por token) do tamanho das mensagens
+func estimateTokens(messages []models.Message) int {
+	total := 0
+	for _, msg := range messages {
+		total += len([]rune(msg.Content))/4 + 1
+	}
+	return total
+}
+
+// formatTranscript converte as mensagens em texto corrido para ser enviado ao modelo
+func formatTranscript(messages []models.Message) string {
+	var builder strings.Builder
+	for _, msg := range messages {
+		role := "Usuário"
+		if msg.Role == "assistant" {
+			role = "Assistente"
+		} else if msg.Role == "system" {
+			role = "Sistema"
+		}
+		builder.WriteString(fmt.Sprintf("%s: %s\n\n", role, msg.Content))
+	}
+	return strings.TrimSpace(builder.String())
+}
+
+// summarizeOldest pede ao modelo um resumo das n trocas mais antigas ainda não resumidas e as
+// substitui por uma única mensagem de resumo, mantendo as trocas recentes literalmente.
+// Retorna a quantidade de trocas resumidas.
+func (cli *ChatCLI) summarizeOldest(ctx context.Context, n int) (int, error) {
+	prefix, exchanges := splitSummarizable(cli.history)
+	if n > len(exchanges) {
+		n = len(exchanges)
+	}
+	if n <= 0 {
+		return 0, fmt.Errorf("não há trocas suficientes para resumir")
+	}
+
+	var old []models.Message
+	for _, exchange := range exchanges[:n] {
+		old = append(old, exchange...)
+	}
+
+	summary, err := cli.client.SendPrompt(ctx, summarizeInstruction+"\n\n"+formatTranscript(old), nil)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao gerar o resumo: %w", err)
+	}
+
+	history := append([]models.Message(nil), prefix...)
+	history = append(history, models.Message{
+		Role:    "system",
+		Content: summaryPrefix + "\n" + strings.TrimSpace(summary),
+	})
+	for _, exchange := range exchanges[n:] {
+		history = append(history, exchange...)
+	}
+	cli.history = history
+	return n, nil
+}
+
+// handleSummarizeCommand trata o comando /summarize [N]
+func (cli *ChatCLI) handleSummarizeCommand(userInput string) {
+	_, exchanges := splitSummarizable(cli.history)
+	n := len(exchanges) - summarizeKeepRecent
+
+	args := strings.Fields(userInput)
+	if len(args) > 1 {
+		parsed, err := strconv.Atoi(args[1])
+		if err != nil || parsed < 1 {
+			fmt.Println("Uso: /summarize [N] - N deve ser um número maior que zero.")
+			return
+		}
+		n = parsed
+	}
+	if n <= 0 {
+		fmt.Printf("Não há trocas suficientes para resumir (as %d mais recentes são mantidas).\n", summarizeKeepRecent)
+		return
+	}
+
+	before := estimateTokens(cli.history)
+	cli.animation.ShowThinkingAnimation(cli.client.GetModelName())
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	summarized, err := cli.summarizeOldest(ctx, n)
+	cli.animation.StopThinkingAnimation()
+
+	if err != nil {
+		cli.logger.Error("Erro ao resumir o histórico", zap.Error(err))
+		fmt.Println("Não foi possível resumir o histórico:", err)
+		return
+	}
+	fmt.Printf("%d troca(s) resumida(s). Tokens estimados: %d -> %d\n", summarized, before, estimateTokens(cli.history))
+}
+
+// autoSummarizeThreshold lê CHATCLI_AUTO_SUMMARIZE: um número define o limite de tokens estimados,
+// "true" usa o limite padrão e valores vazios, "false" ou "0" desativam o modo automático.
+func autoSummarizeThreshold() int {
+	value := strings.ToLower(strings.TrimSpace(os.Getenv("CHATCLI_AUTO_SUMMARIZE")))
+	switch value {
+	case "", "false", "0":
+		return 0
+	case "true":
+		return defaultAutoSummarizeThreshold
+	}
+	threshold, err := strconv.Atoi(value)
+	if err != nil || threshold < 0 {
+		return 0
+	}
+	return threshold
+}
+
+// autoSummarizeIfNeeded resume as trocas mais antigas quando o histórico enviado ao provedor
+// ultrapassa o limite definido em CHATCLI_AUTO_SUMMARIZE (ou o padrão, com a estratégia summarize)
+func (cli *ChatCLI) autoSummarizeIfNeeded(ctx context.Context) {
+	threshold := autoSummarizeThreshold()
+	if threshold == 0 && cli.historyStrategy.name == historyStrategySummarize {
+		threshold = defaultAutoSummarizeThreshold
+	}
+	if threshold == 0 || estimateTokens(cli.historyForRequest()) < threshold {
+		return
+	}
+	_, exchanges := splitSummarizable(cli.history)
+	n := len(exchanges) - summarizeKeepRecent
+	if n <= 0 {
+		return
+	}
+
+	fmt.Println("Histórico próximo do limite de contexto, resumindo as trocas mais antigas...")
+	summarizeCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+	if _, err := cli.summarizeOldest(summarizeCtx, n); err != nil {
+		cli.logger.Warn("Falha no resumo automático do histórico", zap.Error(err))
+	}
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/diillson/chatcli/llm/client"
+	"github.com/diillson/chatcli/models"
+)
+
+func TestChatCLI_summarizeOldest(t *testing.T) {
+	cli := &ChatCLI{
+		client: &client.MockLLMClient{Response: "resumo das trocas"},
+		history: []models.Message{
+			{Role: "user", Content: "primeira"},
+			{Role: "assistant", Content: "resposta 1"},
+			{Role: "user", Content: "segunda"},
+			{Role: "assistant", Content: "resposta 2"},
+			{Role: "user", Content: "terceira"},
+			{Role: "assistant", Content: "resposta 3"},
+		},
+	}
+
+	n, err := cli.summarizeOldest(context.Background(), 2)
+	if err != nil || n != 2 {
+		t.Fatalf("Esperado resumir 2 trocas, obtido %d (erro: %v)", n, err)
+	}
+	if len(cli.history) != 3 || !isSummaryMessage(cli.history[0]) {
+		t.Fatalf("Esperado resumo seguido da troca recente, obtido: %+v", cli.history)
+	}
+	if !strings.Contains(cli.history[0].Content, "resumo das trocas") || cli.history[1].Content != "terceira" {
+		t.Errorf("Histórico inesperado após o resumo: %+v", cli.history)
+	}
+
+	// O resumo existente não entra em um novo resumo
+	prefix, exchanges := splitSummarizable(cli.history)
+	if len(prefix) != 1 || len(exchanges) != 1 {
+		t.Errorf("Esperado 1 resumo preservado e 1 troca resumível, obtido %d e %d", len(prefix), len(exchanges))
+	}
+	if _, err := cli.summarizeOldest(context.Background(), 5); err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if len(cli.history) != 2 || !isSummaryMessage(cli.history[0]) || !isSummaryMessage(cli.history[1]) {
+		t.Errorf("Esperado manter o resumo anterior e adicionar um novo, obtido: %+v", cli.history)
+	}
+	if _, err := cli.summarizeOldest(context.Background(), 1); err == nil {
+		t.Error("Esperado erro quando não há trocas para resumir")
+	}
+}
+
+func TestAutoSummarizeThreshold(t *testing.T) {
+	cases := map[string]int{"": 0, "false": 0, "true": defaultAutoSummarizeThreshold, "8000": 8000, "abc": 0}
+	for value, expected := range cases {
+		t.Setenv("CHATCLI_AUTO_SUMMARIZE", value)
+		if got := autoSummarizeThreshold(); got != expected {
+			t.Errorf("CHATCLI_AUTO_SUMMARIZE=%q: esperado %d, obtido %d", value, expected, got)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/diillson/chatcli/utils"
+	"go.uber.org/zap"
+)
+
+// maxSystemFileSize limita o tamanho do arquivo de prompt de sistema
+const maxSystemFileSize = 256 * 1024
+
+// systemFileSource retorna o arquivo de prompt de sistema configurado e sua origem: CHATCLI_SYSTEM_FILE
+// ou system_prompt_file da configuração de projeto, relativo ao arquivo de configuração
+func (cli *ChatCLI) systemFileSource() (string, string) {
+	if path := os.Getenv("CHATCLI_SYSTEM_FILE"); path != "" {
+		return path, "CHATCLI_SYSTEM_FILE"
+	}
+	if cli.project != nil && cli.project.SystemPromptFile != "" {
+		path := cli.project.SystemPromptFile
+		if expanded, err := utils.ExpandPath(path); err == nil {
+			path = expanded
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(filepath.Dir(cli.project.Path), path)
+		}
+		return path, "system_prompt_file"
+	}
+	return "", ""
+}
+
+// loadSystemFile lê o arquivo de prompt de sistema, se houver um configurado. Em caso de erro, o
+// conteúdo lido anteriormente é descartado e vale o system_prompt da configuração de projeto.
+func (cli *ChatCLI) loadSystemFile() error {
+	path, _ := cli.systemFileSource()
+	cli.systemFile, cli.systemFileContent = path, ""
+	if path == "" {
+		return nil
+	}
+
+	content, err := utils.ReadFileContent(path, maxSystemFileSize)
+	if err != nil {
+		cli.logger.Warn("Erro ao ler o arquivo de prompt de sistema", zap.String("path", path), zap.Error(err))
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	cli.systemFileContent = strings.TrimSpace(content)
+	return nil
+}
+
+// systemPrompt retorna o prompt de sistema efetivo: o do arquivo, quando carregado, ou o system_prompt
+// da configuração de projeto
+func (cli *ChatCLI) systemPrompt() string {
+	if cli.systemFileContent != "" {
+		return cli.systemFileContent
+	}
+	if cli.project != nil {
+		return cli.project.SystemPrompt
+	}
+	return ""
+}
+
+// handleSystemCommand trata /system show|reload
+func (cli *ChatCLI) handleSystemCommand(userInput string) {
+	args := strings.Fields(userInput)
+	if len(args) > 2 || (len(args) == 2 && args[1] != "show" && args[1] != "reload") {
+		fmt.Println("Uso: /system show | reload")
+		return
+	}
+
+	if len(args) == 2 && args[1] == "reload" {
+		if _, origin := cli.systemFileSource(); origin == "" {
+			fmt.Println("O prompt de sistema não vem de um arquivo. Defina CHATCLI_SYSTEM_FILE ou system_prompt_file na configuração de projeto.")
+			return
+		}
+		if err := cli.loadSystemFile(); err != nil {
+			fmt.Println("Erro ao recarregar o prompt de sistema:", err)
+			return
+		}
+		fmt.Printf("Prompt de sistema recarregado de %s (%d caracteres).\n", cli.systemFile, len(cli.systemFileContent))
+		return
+	}
+
+	path, origin := cli.systemFileSource()
+	prompt := cli.systemPrompt()
+	switch {
+	case cli.systemFileContent != "":
+		fmt.Printf("Prompt de sistema de %s (%s):\n", path, origin)
+	case path != "":
+		fmt.Printf("O arquivo %s (%s) não pôde ser lido ou está vazio; use /system reload após corrigi-lo.\n", path, origin)
+		if prompt != "" {
+			fmt.Println("Em uso, o system_prompt da configuração de projeto:")
+		}
+	case prompt != "":
+		fmt.Printf("Prompt de sistema da configuração de projeto (%s):\n", cli.project.Path)
+	default:
+		fmt.Println("Nenhum prompt de sistema definido.")
+		return
+	}
+	if prompt != "" {
+		fmt.Println(prompt)
+	}
+}
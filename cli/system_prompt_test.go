@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/diillson/chatcli/config"
+	"go.uber.org/zap"
+)
+
+func TestLoadSystemFile(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, ".chatcli.yaml")
+	persona := filepath.Join(dir, "persona.md")
+	if err := os.WriteFile(persona, []byte("Você é um SRE.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cli := &ChatCLI{logger: zap.NewNop(), project: &config.ProjectConfig{
+		Path: configPath, SystemPrompt: "inline", SystemPromptFile: "persona.md",
+	}}
+	t.Setenv("CHATCLI_SYSTEM_FILE", "")
+	if err := cli.loadSystemFile(); err != nil || cli.systemPrompt() != "Você é um SRE." {
+		t.Fatalf("Esperado o prompt do arquivo relativo ao projeto, obtido %q, %v", cli.systemPrompt(), err)
+	}
+
+	// A edição do arquivo só vale após recarregar
+	if err := os.WriteFile(persona, []byte("Você é um DBA."), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if cli.systemPrompt() != "Você é um SRE." {
+		t.Errorf("O prompt não deveria mudar antes do reload: %q", cli.systemPrompt())
+	}
+	if err := cli.loadSystemFile(); err != nil || cli.systemPrompt() != "Você é um DBA." {
+		t.Errorf("Esperado o prompt editado, obtido %q, %v", cli.systemPrompt(), err)
+	}
+
+	// CHATCLI_SYSTEM_FILE tem precedência; um arquivo inexistente volta ao system_prompt inline
+	t.Setenv("CHATCLI_SYSTEM_FILE", filepath.Join(dir, "nao-existe.md"))
+	if err := cli.loadSystemFile(); err == nil {
+		t.Error("Esperado erro para arquivo inexistente")
+	}
+	if cli.systemPrompt() != "inline" {
+		t.Errorf("Esperado o system_prompt inline, obtido %q", cli.systemPrompt())
+	}
+}
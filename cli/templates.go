@@ -0,0 +1,228 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/diillson/chatcli/utils"
+)
+
+const (
+	defaultTemplatesDir = "~/.chatcli/templates"
+	templateExtension   = ".txt"
+)
+
+// templateNamePattern define os nomes aceitos para os templates, que também são nomes de arquivo
+var templateNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// templateSavePattern separa o nome e o texto (que pode conter aspas e quebras) de /template save
+var templateSavePattern = regexp.MustCompile(`(?s)^/template\s+save\s+(\S+)\s*(.*)$`)
+
+// templatePlaceholderPattern encontra os placeholders {{nome}} de um template
+var templatePlaceholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_-]+)\s*\}\}`)
+
+// templatesDir retorna o diretório dos templates (CHATCLI_TEMPLATES_DIR ou o padrão)
+func templatesDir() (string, error) {
+	return utils.ExpandPath(utils.GetEnvOrDefault("CHATCLI_TEMPLATES_DIR", defaultTemplatesDir))
+}
+
+// templatePath valida o nome e retorna o caminho do arquivo do template
+func templatePath(name string) (string, error) {
+	if !templateNamePattern.MatchString(name) {
+		return "", fmt.Errorf("nome de template inválido: %s (use letras, números, _ e -)", name)
+	}
+	dir, err := templatesDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name+templateExtension), nil
+}
+
+// templatePlaceholders retorna os nomes dos placeholders do template, sem repetição e na ordem em que aparecem
+func templatePlaceholders(text string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, match := range templatePlaceholderPattern.FindAllStringSubmatch(text, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			names = append(names, match[1])
+		}
+	}
+	return names
+}
+
+// fillTemplate substitui os placeholders pelos valores informados. Retorna erro listando os que faltarem.
+func fillTemplate(text string, values map[string]string) (string, error) {
+	var missing []string
+	for _, name := range templatePlaceholders(text) {
+		if _, ok := values[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("valores ausentes para: %s (use %s=<valor>)", strings.Join(missing, ", "), missing[0])
+	}
+	return templatePlaceholderPattern.ReplaceAllStringFunc(text, func(placeholder string) string {
+		return values[templatePlaceholderPattern.FindStringSubmatch(placeholder)[1]]
+	}), nil
+}
+
+// parseTemplateValues interpreta os argumentos chave=valor de /template run
+func parseTemplateValues(args []string) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("argumento inválido: %s (use chave=valor)", arg)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// handleTemplateCommand trata /template save|run|list|delete
+func (cli *ChatCLI) handleTemplateCommand(userInput string) {
+	args := strings.Fields(userInput)
+	if len(args) < 2 {
+		fmt.Println("Uso: /template save <nome> [texto] | run <nome> [chave=valor...] | list | delete <nome>")
+		return
+	}
+
+	switch args[1] {
+	case "list":
+		cli.listTemplates()
+	case "save":
+		match := templateSavePattern.FindStringSubmatch(strings.TrimSpace(userInput))
+		if match == nil {
+			fmt.Println("Uso: /template save <nome> [texto] - sem texto, salva o último prompt enviado")
+			return
+		}
+		text := strings.TrimSpace(match[2])
+		if text == "" {
+			text = cli.lastPrompt
+		}
+		cli.saveTemplate(match[1], text)
+	case "run":
+		// Os valores podem conter espaços quando entre aspas: chave="valor com espaços"
+		fields, err := parseFields(userInput)
+		if err != nil {
+			fmt.Println("Erro:", err)
+			return
+		}
+		if len(fields) < 3 {
+			fmt.Println("Uso: /template run <nome> [chave=valor...]")
+			return
+		}
+		cli.runTemplate(fields[2], fields[3:])
+	case "delete":
+		if len(args) != 3 {
+			fmt.Println("Uso: /template delete <nome>")
+			return
+		}
+		path, err := templatePath(args[2])
+		if err != nil {
+			fmt.Println("Erro:", err)
+			return
+		}
+		if err := os.Remove(path); err != nil {
+			if os.IsNotExist(err) {
+				fmt.Printf("Template %s não encontrado.\n", args[2])
+				return
+			}
+			fmt.Println("Erro ao remover o template:", err)
+			return
+		}
+		fmt.Printf("Template %s removido.\n", args[2])
+	default:
+		fmt.Println("Uso: /template save <nome> [texto] | run <nome> [chave=valor...] | list | delete <nome>")
+	}
+}
+
+// saveTemplate grava o texto como template, substituindo um template existente com o mesmo nome
+func (cli *ChatCLI) saveTemplate(name, text string) {
+	if strings.TrimSpace(text) == "" {
+		fmt.Println("Nada para salvar: informe o texto ou envie um prompt antes.")
+		return
+	}
+	path, err := templatePath(name)
+	if err != nil {
+		fmt.Println("Erro:", err)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		fmt.Println("Erro ao criar o diretório de templates:", err)
+		return
+	}
+	if err := os.WriteFile(path, []byte(text+"\n"), 0600); err != nil {
+		fmt.Println("Erro ao salvar o template:", err)
+		return
+	}
+	fmt.Printf("Template %s salvo em %s.\n", name, path)
+	if placeholders := templatePlaceholders(text); len(placeholders) > 0 {
+		fmt.Printf("Placeholders: %s\n", strings.Join(placeholders, ", "))
+	}
+}
+
+// runTemplate preenche o template e o envia como um prompt comum, de modo que @file, @git e os demais
+// comandos especiais contidos nele sejam processados
+func (cli *ChatCLI) runTemplate(name string, args []string) {
+	path, err := templatePath(name)
+	if err != nil {
+		fmt.Println("Erro:", err)
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			fmt.Printf("Template %s não encontrado. Use /template list para ver os disponíveis.\n", name)
+			return
+		}
+		fmt.Println("Erro ao ler o template:", err)
+		return
+	}
+	values, err := parseTemplateValues(args)
+	if err != nil {
+		fmt.Println("Erro:", err)
+		return
+	}
+	prompt, err := fillTemplate(strings.TrimSpace(string(data)), values)
+	if err != nil {
+		fmt.Printf("Erro no template %s: %v\n", name, err)
+		return
+	}
+
+	fmt.Println("Você:", prompt)
+	cli.sendPrompt(context.Background(), prompt)
+}
+
+// listTemplates lista os templates salvos com a primeira linha de cada um
+func (cli *ChatCLI) listTemplates() {
+	dir, err := templatesDir()
+	if err != nil {
+		fmt.Println("Erro:", err)
+		return
+	}
+	matches, _ := filepath.Glob(filepath.Join(dir, "*"+templateExtension))
+	if len(matches) == 0 {
+		fmt.Println("Nenhum template salvo. Use /template save <nome> [texto] para criar um.")
+		return
+	}
+	sort.Strings(matches)
+	fmt.Println("Templates:")
+	for _, path := range matches {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		firstLine, _, _ := strings.Cut(strings.TrimSpace(string(data)), "\n")
+		if runes := []rune(firstLine); len(runes) > 70 {
+			firstLine = string(runes[:70]) + "..."
+		}
+		fmt.Printf("  %s - %s\n", strings.TrimSuffix(filepath.Base(path), templateExtension), firstLine)
+	}
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFillTemplate(t *testing.T) {
+	text := "Escreva testes para {{arquivo}} usando {{ framework }}. Foque em {{arquivo}}."
+	if got := templatePlaceholders(text); strings.Join(got, ",") != "arquivo,framework" {
+		t.Errorf("Placeholders inesperados: %v", got)
+	}
+
+	filled, err := fillTemplate(text, map[string]string{"arquivo": "main.go", "framework": "testing"})
+	if err != nil || filled != "Escreva testes para main.go usando testing. Foque em main.go." {
+		t.Errorf("Template preenchido inesperado: %q (erro: %v)", filled, err)
+	}
+	if _, err := fillTemplate(text, map[string]string{"arquivo": "main.go"}); err == nil || !strings.Contains(err.Error(), "framework") {
+		t.Errorf("Esperado erro listando o placeholder ausente, obtido %v", err)
+	}
+
+	values, err := parseTemplateValues([]string{"foco=segurança", "vazio="})
+	if err != nil || values["foco"] != "segurança" || values["vazio"] != "" {
+		t.Errorf("Valores inesperados: %v (erro: %v)", values, err)
+	}
+	if _, err := parseTemplateValues([]string{"semigual"}); err == nil {
+		t.Error("Esperado erro para argumento sem =")
+	}
+}
+
+func TestHandleTemplateCommand_save(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("CHATCLI_TEMPLATES_DIR", dir)
+
+	cli := &ChatCLI{lastPrompt: "explique @file main.go"}
+	cli.handleTemplateCommand(`/template save revisao Revise "{{alvo}}" com atenção`)
+	cli.handleTemplateCommand("/template save explicar")
+	cli.handleTemplateCommand("/template save ../fora texto")
+
+	data, err := os.ReadFile(filepath.Join(dir, "revisao.txt"))
+	if err != nil || strings.TrimSpace(string(data)) != `Revise "{{alvo}}" com atenção` {
+		t.Errorf("Template salvo inesperado: %q (erro: %v)", data, err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "explicar.txt")); strings.TrimSpace(string(data)) != "explique @file main.go" {
+		t.Errorf("Esperado salvar o último prompt, obtido %q", data)
+	}
+	if matches, _ := filepath.Glob(filepath.Join(filepath.Dir(dir), "fora*")); len(matches) != 0 {
+		t.Error("Nomes com separadores de caminho não deveriam ser aceitos")
+	}
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/charmbracelet/glamour"
+	"github.com/diillson/chatcli/utils"
+)
+
+// defaultThemeFile é o arquivo de estilo usado quando CHATCLI_THEME não está definido
+const defaultThemeFile = "~/.chatcli/theme.json"
+
+// markdownStyle retorna o estilo de renderização das respostas: "notty" (sem cores) se NO_COLOR
+// estiver definido, o valor de CHATCLI_THEME (um estilo padrão do glamour, como dark, light ou
+// dracula, ou o caminho de um arquivo JSON de estilo), ~/.chatcli/theme.json se existir, ou "auto".
+func markdownStyle() string {
+	if os.Getenv("NO_COLOR") != "" {
+		return "notty"
+	}
+	theme := os.Getenv("CHATCLI_THEME")
+	if theme == "" {
+		theme = defaultThemeFile
+		if path, err := utils.ExpandPath(theme); err != nil {
+			return "auto"
+		} else if _, err := os.Stat(path); err != nil {
+			return "auto"
+		}
+	}
+	if path, err := utils.ExpandPath(theme); err == nil {
+		return path
+	}
+	return theme
+}
+
+// markdownStyleOption converte o estilo na opção do renderizador. Estilos que não são padrão do
+// glamour são lidos como arquivo JSON.
+func markdownStyleOption(style string) glamour.TermRendererOption {
+	if style == "auto" {
+		return glamour.WithAutoStyle()
+	}
+	return glamour.WithStylePath(style)
+}
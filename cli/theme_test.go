@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMarkdownStyle(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("CHATCLI_THEME", "")
+
+	if style := markdownStyle(); style != "auto" {
+		t.Errorf("Esperado auto sem tema configurado, obtido %q", style)
+	}
+
+	themeFile := filepath.Join(home, ".chatcli", "theme.json")
+	os.MkdirAll(filepath.Dir(themeFile), 0700)
+	os.WriteFile(themeFile, []byte(`{}`), 0600)
+	if style := markdownStyle(); style != themeFile {
+		t.Errorf("Esperado usar %s, obtido %q", themeFile, style)
+	}
+
+	t.Setenv("CHATCLI_THEME", "dracula")
+	if style := markdownStyle(); style != "dracula" {
+		t.Errorf("Esperado dracula, obtido %q", style)
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	if style := markdownStyle(); style != "notty" {
+		t.Errorf("Esperado notty com NO_COLOR, obtido %q", style)
+	}
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/diillson/chatcli/models"
+	"github.com/diillson/chatcli/utils"
+)
+
+// requestMessages monta as mensagens na ordem em que os clientes as enviam: o histórico (precedido do
+// contexto de sistema) seguido do prompt como nova mensagem do usuário
+func requestMessages(history []models.Message, prompt string) []models.Message {
+	messages := make([]models.Message, 0, len(history)+1)
+	messages = append(messages, history...)
+	return append(messages, models.Message{Role: "user", Content: prompt})
+}
+
+// recordRequest guarda as mensagens da requisição enviada, exibidas por /trace last
+func (cli *ChatCLI) recordRequest(history []models.Message, prompt string) {
+	cli.lastRequest = requestMessages(history, prompt)
+}
+
+// previewRequest monta a requisição que seria enviada para o prompt, expandindo os comandos @ como
+// sendPrompt faz, mas sem enviá-la nem alterar o histórico da sessão
+func (cli *ChatCLI) previewRequest(input string) []models.Message {
+	input, _ = extractProviderOverride(input)
+	images, input, err := extractImages(input)
+	if err != nil {
+		fmt.Println("Erro no comando @image:", err)
+		return nil
+	}
+
+	savedPriming, savedSources, savedAttached := cli.primingMessages, cli.contextSources, cli.attachedFiles
+	defer func() {
+		cli.primingMessages, cli.contextSources, cli.attachedFiles = savedPriming, savedSources, savedAttached
+	}()
+
+	userInput, additionalContext := cli.processSpecialCommands(input)
+	additionalContext = cli.scrubSecrets(additionalContext, "o contexto do prompt")
+
+	// O histórico recebe as partes de @file --mode chunked e o prompt antes de a requisição ser montada
+	savedHistory := cli.history
+	defer func() { cli.history = savedHistory }()
+	cli.history = append(cli.history[:len(cli.history):len(cli.history)], cli.primingMessages...)
+	cli.history = append(cli.history, models.Message{Role: "user", Content: userInput + additionalContext + imageNote(images)})
+
+	return requestMessages(cli.historyForRequest(), userInput+additionalContext)
+}
+
+// handleTraceCommand trata /trace [prompt] e /trace last. Sem prompt, mostra o contexto de sistema e o
+// histórico que acompanharão o próximo prompt.
+func (cli *ChatCLI) handleTraceCommand(userInput string) {
+	arg := strings.TrimSpace(strings.TrimPrefix(userInput, "/trace"))
+	switch arg {
+	case "last":
+		if cli.lastRequest == nil {
+			fmt.Println("Nenhuma requisição enviada nesta sessão.")
+			return
+		}
+		fmt.Println("Última requisição enviada ao provedor:")
+		printTrace(cli.lastRequest)
+	case "":
+		messages := cli.historyForRequest()
+		if len(messages) == 0 {
+			fmt.Println("O próximo prompt será enviado sem contexto de sistema nem histórico.")
+			return
+		}
+		fmt.Println("Mensagens que acompanharão o próximo prompt (use /trace <prompt> para incluí-lo):")
+		printTrace(messages)
+	default:
+		messages := cli.previewRequest(arg)
+		if messages == nil {
+			return
+		}
+		fmt.Println("Requisição que seria enviada ao provedor (nada foi enviado):")
+		printTrace(messages)
+	}
+}
+
+// printTrace exibe cada mensagem com o papel e os tokens estimados, ocultando os segredos
+func printTrace(messages []models.Message) {
+	total := 0
+	for i, msg := range messages {
+		tokens := estimateTokens([]models.Message{msg})
+		total += tokens
+		content, _ := utils.ScrubSecrets(msg.Content, scrubAllowlist())
+		fmt.Printf("\n[%d] %s (~%d tokens)\n%s\n", i+1, msg.Role, tokens, content)
+	}
+	fmt.Printf("\nTotal: %d mensagem(ns), ~%d tokens\n", len(messages), total)
+}
@@ -0,0 +1,23 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/diillson/chatcli/models"
+	"go.uber.org/zap"
+)
+
+func TestPreviewRequestDoesNotChangeHistory(t *testing.T) {
+	cli := &ChatCLI{logger: zap.NewNop(), memory: NewMemoryStore("", zap.NewNop())}
+	cli.history = []models.Message{{Role: "user", Content: "olá"}, {Role: "assistant", Content: "oi"}}
+	cli.setVar("nome", "echo mundo", "mundo")
+
+	messages := cli.previewRequest("diga @var nome")
+	if len(cli.history) != 2 {
+		t.Fatalf("O histórico não deveria mudar, obteve %d mensagens", len(cli.history))
+	}
+	// Histórico, a mensagem do usuário guardada no histórico e o prompt enviado à parte
+	if len(messages) != 4 || messages[3].Role != "user" || messages[2].Content != messages[3].Content {
+		t.Errorf("Requisição inesperada: %+v", messages)
+	}
+}
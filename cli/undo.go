@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/diillson/chatcli/models"
+)
+
+const undoPreviewLength = 80
+
+// undoExchanges remove as últimas n trocas (mensagem do usuário e tudo o que veio depois dela)
+// do histórico, empilhando-as para um eventual /redo. Retorna as trocas removidas, da mais recente à mais antiga.
+func (cli *ChatCLI) undoExchanges(n int) [][]models.Message {
+	var removed [][]models.Message
+	for i := 0; i < n; i++ {
+		idx := -1
+		for j := len(cli.history) - 1; j >= 0; j-- {
+			if cli.history[j].Role == "user" {
+				idx = j
+				break
+			}
+		}
+		if idx == -1 {
+			break
+		}
+		exchange := append([]models.Message(nil), cli.history[idx:]...)
+		cli.history = cli.history[:idx]
+		cli.redoStack = append(cli.redoStack, exchange)
+		removed = append(removed, exchange)
+	}
+	return removed
+}
+
+// redoExchange restaura a última troca removida por /undo
+func (cli *ChatCLI) redoExchange() []models.Message {
+	if len(cli.redoStack) == 0 {
+		return nil
+	}
+	exchange := cli.redoStack[len(cli.redoStack)-1]
+	cli.redoStack = cli.redoStack[:len(cli.redoStack)-1]
+	cli.history = append(cli.history, exchange...)
+	return exchange
+}
+
+// handleUndoCommand trata os comandos /undo e /undo N
+func (cli *ChatCLI) handleUndoCommand(userInput string) {
+	args := strings.Fields(userInput)
+	n := 1
+	if len(args) > 1 {
+		parsed, err := strconv.Atoi(args[1])
+		if err != nil || parsed < 1 {
+			fmt.Println("Uso: /undo [N] - N deve ser um número maior que zero.")
+			return
+		}
+		n = parsed
+	}
+
+	removed := cli.undoExchanges(n)
+	if len(removed) == 0 {
+		fmt.Println("Não há trocas no histórico para desfazer.")
+		return
+	}
+
+	fmt.Printf("%d troca(s) removida(s) do histórico:\n", len(removed))
+	for _, exchange := range removed {
+		printExchangePreview(exchange)
+	}
+	fmt.Println("Use '/redo' para restaurar.")
+}
+
+// handleRedoCommand trata o comando /redo
+func (cli *ChatCLI) handleRedoCommand() {
+	exchange := cli.redoExchange()
+	if exchange == nil {
+		fmt.Println("Não há trocas para refazer.")
+		return
+	}
+	fmt.Println("Troca restaurada no histórico:")
+	printExchangePreview(exchange)
+}
+
+// printExchangePreview exibe um resumo de uma troca do histórico
+func printExchangePreview(exchange []models.Message) {
+	for _, msg := range exchange {
+		role := "Você"
+		if msg.Role == "assistant" {
+			role = "IA"
+		} else if msg.Role == "system" {
+			role = "Sistema"
+		}
+		fmt.Printf("  - %s: %s\n", role, truncatePreview(msg.Content, undoPreviewLength))
+	}
+}
+
+// truncatePreview reduz um texto a uma única linha com no máximo max caracteres
+func truncatePreview(text string, max int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return string(runes[:max]) + "..."
+}
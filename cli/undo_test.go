@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/diillson/chatcli/models"
+)
+
+func TestChatCLI_undoAndRedo(t *testing.T) {
+	cli := &ChatCLI{history: []models.Message{
+		{Role: "user", Content: "primeira"},
+		{Role: "assistant", Content: "resposta 1"},
+		{Role: "system", Content: "Comando: ls"},
+		{Role: "user", Content: "segunda"},
+		{Role: "assistant", Content: "resposta 2"},
+	}}
+
+	removed := cli.undoExchanges(1)
+	if len(removed) != 1 || len(cli.history) != 3 {
+		t.Fatalf("Esperado remover 1 troca e manter 3 mensagens, obtido %d trocas e %d mensagens", len(removed), len(cli.history))
+	}
+
+	removed = cli.undoExchanges(5)
+	if len(removed) != 1 || len(cli.history) != 0 {
+		t.Fatalf("Esperado remover a troca restante, obtido %d trocas e %d mensagens", len(removed), len(cli.history))
+	}
+
+	cli.redoExchange()
+	cli.redoExchange()
+	if len(cli.history) != 5 || cli.history[4].Content != "resposta 2" {
+		t.Errorf("Histórico não restaurado corretamente: %+v", cli.history)
+	}
+	if cli.redoExchange() != nil {
+		t.Error("Esperado pilha de redo vazia")
+	}
+}
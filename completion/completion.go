@@ -0,0 +1,129 @@
+package completion
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// SupportedShells lista os shells para os quais é possível gerar o script de completion
+var SupportedShells = []string{"bash", "zsh", "fish"}
+
+// Command descreve um subcomando do chatcli e os argumentos aceitos por ele
+type Command struct {
+	Name        string
+	Subcommands []string
+	// SubcommandArgs lista os valores aceitos como argumento de cada subcomando (ex: as chaves de 'config get')
+	SubcommandArgs map[string][]string
+}
+
+// Spec descreve a interface de linha de comando usada para gerar os scripts de completion
+type Spec struct {
+	Program  string
+	Flags    []string
+	Commands []Command
+}
+
+// Generate escreve o script de completion para o shell informado
+func Generate(shell string, spec Spec, out io.Writer) error {
+	switch shell {
+	case "bash":
+		return generateBash(spec, out)
+	case "zsh":
+		return generateZsh(spec, out)
+	case "fish":
+		return generateFish(spec, out)
+	default:
+		return fmt.Errorf("shell não suportado: %s (use %s)", shell, strings.Join(SupportedShells, ", "))
+	}
+}
+
+func (s Spec) commandNames() []string {
+	names := make([]string, len(s.Commands))
+	for i, c := range s.Commands {
+		names[i] = c.Name
+	}
+	return names
+}
+
+func generateBash(spec Spec, out io.Writer) error {
+	fn := "_" + strings.ReplaceAll(spec.Program, "-", "_")
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Completion de bash para %s. Uso: source <(%s completion bash)\n", spec.Program, spec.Program)
+	fmt.Fprintf(&b, "%s() {\n", fn)
+	b.WriteString("    local cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	b.WriteString("    COMPREPLY=()\n")
+	if len(spec.Flags) > 0 {
+		fmt.Fprintf(&b, "    if [[ \"$cur\" == -* ]]; then\n        COMPREPLY=( $(compgen -W \"%s\" -- \"$cur\") )\n        return\n    fi\n", strings.Join(spec.Flags, " "))
+	}
+	fmt.Fprintf(&b, "    if [ \"$COMP_CWORD\" -eq 1 ]; then\n        COMPREPLY=( $(compgen -W \"%s\" -- \"$cur\") )\n        return\n    fi\n", strings.Join(spec.commandNames(), " "))
+	b.WriteString("    case \"${COMP_WORDS[1]}\" in\n")
+	for _, c := range spec.Commands {
+		fmt.Fprintf(&b, "        %s)\n", c.Name)
+		fmt.Fprintf(&b, "            if [ \"$COMP_CWORD\" -eq 2 ]; then\n                COMPREPLY=( $(compgen -W \"%s\" -- \"$cur\") )\n", strings.Join(c.Subcommands, " "))
+		if len(c.SubcommandArgs) > 0 {
+			b.WriteString("            elif [ \"$COMP_CWORD\" -eq 3 ]; then\n                case \"${COMP_WORDS[2]}\" in\n")
+			for _, sub := range c.Subcommands {
+				if args, ok := c.SubcommandArgs[sub]; ok {
+					fmt.Fprintf(&b, "                    %s) COMPREPLY=( $(compgen -W \"%s\" -- \"$cur\") ) ;;\n", sub, strings.Join(args, " "))
+				}
+			}
+			b.WriteString("                esac\n")
+		}
+		b.WriteString("            fi\n            ;;\n")
+	}
+	b.WriteString("    esac\n}\n")
+	fmt.Fprintf(&b, "complete -F %s %s\n", fn, spec.Program)
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+func generateZsh(spec Spec, out io.Writer) error {
+	fn := "_" + strings.ReplaceAll(spec.Program, "-", "_")
+	var b strings.Builder
+	fmt.Fprintf(&b, "#compdef %s\n", spec.Program)
+	fmt.Fprintf(&b, "# Completion de zsh para %s. Uso: source <(%s completion zsh)\n", spec.Program, spec.Program)
+	fmt.Fprintf(&b, "%s() {\n", fn)
+	b.WriteString("    local -a opts\n")
+	b.WriteString("    case $CURRENT in\n")
+	fmt.Fprintf(&b, "        2) opts=(%s) ;;\n", strings.Join(append(spec.commandNames(), spec.Flags...), " "))
+	b.WriteString("        3)\n            case ${words[2]} in\n")
+	for _, c := range spec.Commands {
+		fmt.Fprintf(&b, "                %s) opts=(%s) ;;\n", c.Name, strings.Join(c.Subcommands, " "))
+	}
+	b.WriteString("            esac\n            ;;\n")
+	b.WriteString("        4)\n            case \"${words[2]} ${words[3]}\" in\n")
+	for _, c := range spec.Commands {
+		for _, sub := range c.Subcommands {
+			if args, ok := c.SubcommandArgs[sub]; ok {
+				fmt.Fprintf(&b, "                \"%s %s\") opts=(%s) ;;\n", c.Name, sub, strings.Join(args, " "))
+			}
+		}
+	}
+	b.WriteString("            esac\n            ;;\n")
+	b.WriteString("    esac\n    compadd -a opts\n}\n")
+	fmt.Fprintf(&b, "compdef %s %s\n", fn, spec.Program)
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+func generateFish(spec Spec, out io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Completion de fish para %s. Uso: %s completion fish | source\n", spec.Program, spec.Program)
+	fmt.Fprintf(&b, "complete -c %s -f\n", spec.Program)
+	for _, flag := range spec.Flags {
+		fmt.Fprintf(&b, "complete -c %s -l %s\n", spec.Program, strings.TrimLeft(flag, "-"))
+	}
+	fmt.Fprintf(&b, "complete -c %s -n \"__fish_use_subcommand\" -a \"%s\"\n", spec.Program, strings.Join(spec.commandNames(), " "))
+	for _, c := range spec.Commands {
+		subs := strings.Join(c.Subcommands, " ")
+		fmt.Fprintf(&b, "complete -c %s -n \"__fish_seen_subcommand_from %s; and not __fish_seen_subcommand_from %s\" -a \"%s\"\n", spec.Program, c.Name, subs, subs)
+		for _, sub := range c.Subcommands {
+			if args, ok := c.SubcommandArgs[sub]; ok {
+				fmt.Fprintf(&b, "complete -c %s -n \"__fish_seen_subcommand_from %s; and __fish_seen_subcommand_from %s\" -a \"%s\"\n", spec.Program, c.Name, sub, strings.Join(args, " "))
+			}
+		}
+	}
+	_, err := io.WriteString(out, b.String())
+	return err
+}
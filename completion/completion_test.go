@@ -0,0 +1,43 @@
+package completion
+
+import (
+	"strings"
+	"testing"
+)
+
+func testSpec() Spec {
+	return Spec{
+		Program: "chatcli",
+		Flags:   []string{"--help"},
+		Commands: []Command{
+			{
+				Name:           "config",
+				Subcommands:    []string{"list", "get"},
+				SubcommandArgs: map[string][]string{"get": {"LOG_LEVEL", "OPENAI_MODEL"}},
+			},
+			{Name: "completion", Subcommands: []string{"bash", "zsh", "fish"}},
+		},
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	for _, shell := range SupportedShells {
+		var out strings.Builder
+		if err := Generate(shell, testSpec(), &out); err != nil {
+			t.Fatalf("Erro ao gerar completion para %s: %v", shell, err)
+		}
+		script := out.String()
+		for _, expected := range []string{"config", "completion", "list get", "LOG_LEVEL OPENAI_MODEL", "help"} {
+			if !strings.Contains(script, expected) {
+				t.Errorf("Script de %s não contém '%s':\n%s", shell, expected, script)
+			}
+		}
+	}
+}
+
+func TestGenerateUnsupportedShell(t *testing.T) {
+	var out strings.Builder
+	if err := Generate("powershell", testSpec(), &out); err == nil {
+		t.Error("Esperado erro para shell não suportado")
+	}
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+const commandUsage = `Uso: chatcli config <subcomando> [argumentos]
+
+Subcomandos:
+  list               Lista os valores efetivos e sua origem (env, arquivo ou padrão)
+  get <CHAVE>        Exibe o valor efetivo de uma chave
+  set <CHAVE> <VAL>  Grava a chave no arquivo .env (ou em CHATCLI_DOTENV)
+  unset <CHAVE>      Remove a chave do arquivo .env`
+
+// Subcommands lista os subcomandos aceitos por 'chatcli config'
+var Subcommands = []string{"list", "get", "set", "unset"}
+
+// RunCommand executa o subcomando 'config' (list, get, set, unset) sobre o arquivo .env
+func RunCommand(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("subcomando ausente\n\n%s", commandUsage)
+	}
+
+	path := DotenvPath()
+
+	switch args[0] {
+	case "list":
+		entries, err := List(path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Arquivo de configuração: %s\n\n", path)
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "CHAVE\tVALOR\tORIGEM")
+		for _, e := range entries {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", e.Key.Name, e.DisplayValue(), e.Source)
+		}
+		return w.Flush()
+	case "get":
+		if len(args) != 2 {
+			return fmt.Errorf("uso: chatcli config get <CHAVE>")
+		}
+		key, ok := LookupKey(args[1])
+		if !ok {
+			return fmt.Errorf("chave desconhecida: %s", args[1])
+		}
+		fileValues, err := ReadFile(path)
+		if err != nil {
+			return err
+		}
+		e := Resolve(key, fileValues)
+		fmt.Fprintf(out, "%s=%s (%s)\n", key.Name, e.DisplayValue(), e.Source)
+		return nil
+	case "set":
+		if len(args) < 3 {
+			return fmt.Errorf("uso: chatcli config set <CHAVE> <VALOR>")
+		}
+		value := strings.Join(args[2:], " ")
+		if err := Set(path, args[1], value); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "%s atualizado em %s\n", strings.ToUpper(args[1]), path)
+		return nil
+	case "unset":
+		if len(args) != 2 {
+			return fmt.Errorf("uso: chatcli config unset <CHAVE>")
+		}
+		removed, err := Unset(path, args[1])
+		if err != nil {
+			return err
+		}
+		if removed {
+			fmt.Fprintf(out, "%s removido de %s\n", strings.ToUpper(args[1]), path)
+		} else {
+			fmt.Fprintf(out, "%s não está definido em %s\n", strings.ToUpper(args[1]), path)
+		}
+		return nil
+	case "help", "-h", "--help":
+		fmt.Fprintln(out, commandUsage)
+		return nil
+	default:
+		return fmt.Errorf("subcomando desconhecido: %s\n\n%s", args[0], commandUsage)
+	}
+}
@@ -0,0 +1,426 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diillson/chatcli/llm/ollama"
+	"github.com/diillson/chatcli/utils"
+	"github.com/joho/godotenv"
+)
+
+// Fontes possíveis para o valor efetivo de uma chave de configuração
+const (
+	SourceEnv     = "env"
+	SourceFile    = "arquivo"
+	SourceDefault = "padrão"
+	SourceUnset   = "não definido"
+)
+
+// Key descreve uma chave de configuração conhecida pelo ChatCLI
+type Key struct {
+	Name         string
+	DefaultValue string
+	Secret       bool
+	Validate     func(value string) error
+}
+
+// Entry representa o valor efetivo de uma chave e de onde ele veio
+type Entry struct {
+	Key    Key
+	Value  string
+	Source string
+}
+
+var modelIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:/-]+$`)
+
+// knownKeys lista as chaves de configuração suportadas, na ordem em que são exibidas
+var knownKeys = []Key{
+	{Name: "LLM_PROVIDER", DefaultValue: "STACKSPOT", Validate: validProvider},
+	{Name: "LOG_LEVEL", DefaultValue: "info", Validate: oneOf("debug", "info", "warn", "error", "dpanic", "panic", "fatal")},
+	{Name: "ENV", DefaultValue: "dev", Validate: oneOf("dev", "prod")},
+	{Name: "LOG_FILE", DefaultValue: "app.log", Validate: notEmpty},
+	{Name: "LOG_MAX_SIZE", DefaultValue: "50MB", Validate: validSize},
+	{Name: "HISTORY_MAX_SIZE", DefaultValue: "50MB", Validate: validSize},
+	{Name: "OPENAI_API_KEY", Secret: true, Validate: notEmpty},
+	{Name: "OPENAI_API_KEYS", Secret: true, Validate: notEmpty},
+	{Name: "OPENAI_MODEL", DefaultValue: "gpt-4o-mini", Validate: validModelID},
+	{Name: "OPENAI_BASE_URL", Validate: validBaseURL},
+	{Name: "CLAUDEAI_API_KEY", Secret: true, Validate: notEmpty},
+	{Name: "CLAUDEAI_MODEL", DefaultValue: "claude-3-5-sonnet-20241022", Validate: validModelID},
+	{Name: "CLAUDEAI_BASE_URL", Validate: validBaseURL},
+	{Name: "OLLAMA_HOST", DefaultValue: "http://localhost:11434", Validate: validOllamaHost},
+	{Name: "OLLAMA_MODEL", DefaultValue: "llama3.1", Validate: validModelID},
+	{Name: "OLLAMA_ENABLED", Validate: oneOf("true", "false")},
+	{Name: "CHATCLI_TEMPERATURE", Validate: floatInRange(0, 2)},
+	{Name: "CHATCLI_TOP_P", Validate: floatInRange(0, 1)},
+	{Name: "CHATCLI_PRESENCE_PENALTY", Validate: floatInRange(-2, 2)},
+	{Name: "CHATCLI_FREQUENCY_PENALTY", Validate: floatInRange(-2, 2)},
+	{Name: "CHATCLI_MAX_TOKENS", Validate: positiveInt},
+	{Name: "GITHUB_TOKEN", Secret: true, Validate: notEmpty},
+	{Name: "GITHUB_API_URL", DefaultValue: "https://api.github.com", Validate: validBaseURL},
+	{Name: "CLIENT_ID", Validate: notEmpty},
+	{Name: "CLIENT_SECRET", Secret: true, Validate: notEmpty},
+	{Name: "SLUG_NAME", DefaultValue: "testeai", Validate: notEmpty},
+	{Name: "TENANT_NAME", DefaultValue: "zup", Validate: notEmpty},
+	{Name: "CHATCLI_CONNECT_TIMEOUT", DefaultValue: "30s", Validate: validDuration},
+	{Name: "CHATCLI_IDLE_TIMEOUT", DefaultValue: "5m", Validate: validDuration},
+	{Name: "CHATCLI_COMMAND_OUTPUT_LIMIT", DefaultValue: "64KB", Validate: validSize},
+	{Name: "CHATCLI_AUTO_SUMMARIZE", DefaultValue: "false", Validate: validAutoSummarize},
+	{Name: "CHATCLI_HISTORY_STRATEGY", DefaultValue: "full", Validate: oneOf("full", "last-n", "summarize")},
+	{Name: "CHATCLI_HISTORY_LAST_N", DefaultValue: "10", Validate: positiveInt},
+	{Name: "CHATCLI_INPUT_HISTORY_FILE", DefaultValue: "~/.chatcli/input_history", Validate: notEmpty},
+	{Name: "CHATCLI_HISTORY_EXCLUDE_SECRETS", DefaultValue: "true", Validate: validBool},
+	{Name: "CHATCLI_CA_BUNDLE", Validate: notEmpty},
+	{Name: "CHATCLI_DEBUG_HTTP", DefaultValue: "false", Validate: validBool},
+	{Name: "CHATCLI_SCRUB_SECRETS", DefaultValue: "false", Validate: validBool},
+	{Name: "CHATCLI_SCRUB_ALLOWLIST", Validate: notEmpty},
+	{Name: "CHATCLI_THEME", DefaultValue: "auto", Validate: notEmpty},
+	{Name: "CHATCLI_SPINNER", DefaultValue: "line", Validate: oneOf("dots", "line", "moon")},
+	{Name: "CHATCLI_THINKING_TEXT", DefaultValue: "está pensando...", Validate: notEmpty},
+	{Name: "CHATCLI_ENCRYPTION_KEY", Secret: true, Validate: notEmpty},
+	{Name: "CHATCLI_TEMPLATES_DIR", DefaultValue: "~/.chatcli/templates", Validate: notEmpty},
+	{Name: "CHATCLI_SYSTEM_FILE", Validate: notEmpty},
+	{Name: "CHATCLI_ROUTING_FILE", DefaultValue: "~/.chatcli/routing.json", Validate: notEmpty},
+	{Name: "CHATCLI_ALIASES_FILE", DefaultValue: "~/.chatcli/aliases.json", Validate: notEmpty},
+	{Name: "CHATCLI_PROVIDERS_FILE", DefaultValue: DefaultProvidersFile, Validate: notEmpty},
+	{Name: "CHATCLI_FORMATTERS_FILE", DefaultValue: "~/.chatcli/formatters.json", Validate: notEmpty},
+	{Name: "CHATCLI_DEFAULT_CONTEXT", Validate: notEmpty},
+	{Name: "CHATCLI_DEFAULT_CONTEXT_MAX_TOKENS", DefaultValue: "8000", Validate: positiveInt},
+	{Name: "CHATCLI_CONTEXT_MAX_TOKENS", Validate: positiveInt},
+	{Name: "CHATCLI_MAX_CONTEXT_FILES", Validate: positiveInt},
+	{Name: "CHATCLI_MAX_CONTEXT_BYTES", Validate: validSize},
+	{Name: "CHATCLI_MEMORY_FILE", DefaultValue: "~/.chatcli/memory.json", Validate: notEmpty},
+	{Name: "CHATCLI_SERVER_TOKEN", Secret: true, Validate: notEmpty},
+}
+
+// KnownKeys retorna as chaves de configuração suportadas
+func KnownKeys() []Key {
+	return knownKeys
+}
+
+// KeyNames retorna os nomes das chaves de configuração suportadas
+func KeyNames() []string {
+	names := make([]string, len(knownKeys))
+	for i, k := range knownKeys {
+		names[i] = k.Name
+	}
+	return names
+}
+
+// LookupKey retorna a definição de uma chave conhecida (sem diferenciar maiúsculas)
+func LookupKey(name string) (Key, bool) {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	for _, k := range knownKeys {
+		if k.Name == name {
+			return k, true
+		}
+	}
+	return Key{}, false
+}
+
+// DotenvPath retorna o caminho do arquivo .env, respeitando CHATCLI_DOTENV
+func DotenvPath() string {
+	envFilePath := os.Getenv("CHATCLI_DOTENV")
+	if envFilePath == "" {
+		return ".env"
+	}
+	expanded, err := utils.ExpandPath(envFilePath)
+	if err != nil {
+		fmt.Printf("Aviso: não foi possível expandir o caminho '%s': %v\n", envFilePath, err)
+		return envFilePath
+	}
+	return expanded
+}
+
+// ReadFile lê os valores definidos no arquivo .env. Um arquivo inexistente resulta em um mapa vazio.
+func ReadFile(path string) (map[string]string, error) {
+	values, err := godotenv.Read(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]string{}, nil
+		}
+		return nil, fmt.Errorf("erro ao ler o arquivo %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// Resolve determina o valor efetivo de uma chave. Variáveis de ambiente têm precedência
+// sobre o arquivo, pois o godotenv não sobrescreve variáveis já definidas.
+func Resolve(key Key, fileValues map[string]string) Entry {
+	if value, ok := os.LookupEnv(key.Name); ok && value != "" {
+		if fileValue, inFile := fileValues[key.Name]; !inFile || fileValue != value {
+			return Entry{Key: key, Value: value, Source: SourceEnv}
+		}
+	}
+	if value, ok := fileValues[key.Name]; ok {
+		return Entry{Key: key, Value: value, Source: SourceFile}
+	}
+	if key.DefaultValue != "" {
+		return Entry{Key: key, Value: key.DefaultValue, Source: SourceDefault}
+	}
+	return Entry{Key: key, Source: SourceUnset}
+}
+
+// List retorna os valores efetivos de todas as chaves conhecidas
+func List(path string) ([]Entry, error) {
+	fileValues, err := ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]Entry, 0, len(knownKeys))
+	for _, k := range knownKeys {
+		entries = append(entries, Resolve(k, fileValues))
+	}
+	return entries, nil
+}
+
+// DisplayValue retorna o valor pronto para exibição, mascarando segredos
+func (e Entry) DisplayValue() string {
+	if e.Value == "" {
+		return "-"
+	}
+	if !e.Key.Secret {
+		return e.Value
+	}
+	if len(e.Value) <= 8 {
+		return "********"
+	}
+	return e.Value[:4] + "..." + e.Value[len(e.Value)-4:]
+}
+
+// ValidateValue valida o valor de uma chave conhecida
+func ValidateValue(key Key, value string) error {
+	if key.Validate == nil {
+		return nil
+	}
+	if err := key.Validate(value); err != nil {
+		return fmt.Errorf("valor inválido para %s: %w", key.Name, err)
+	}
+	return nil
+}
+
+// Set grava (ou atualiza) uma chave no arquivo .env sem alterar as demais linhas
+func Set(path, name, value string) error {
+	key, ok := LookupKey(name)
+	if !ok {
+		return fmt.Errorf("chave desconhecida: %s (use 'config list' para ver as chaves suportadas)", name)
+	}
+	if err := ValidateValue(key, value); err != nil {
+		return err
+	}
+
+	lines, err := readLines(path)
+	if err != nil {
+		return err
+	}
+
+	newLine := fmt.Sprintf("%s=%s", key.Name, quoteValue(value))
+	var result []string
+	replaced := false
+	for _, line := range lines {
+		if lineDefinesKey(line, key.Name) {
+			// Substitui a primeira definição e descarta duplicatas para evitar ambiguidade
+			if !replaced {
+				result = append(result, newLine)
+				replaced = true
+			}
+			continue
+		}
+		result = append(result, line)
+	}
+	if !replaced {
+		result = append(result, newLine)
+	}
+
+	return writeLines(path, result)
+}
+
+// Unset remove uma chave do arquivo .env sem alterar as demais linhas
+func Unset(path, name string) (bool, error) {
+	key, ok := LookupKey(name)
+	if !ok {
+		return false, fmt.Errorf("chave desconhecida: %s (use 'config list' para ver as chaves suportadas)", name)
+	}
+
+	lines, err := readLines(path)
+	if err != nil {
+		return false, err
+	}
+
+	var kept []string
+	removed := false
+	for _, line := range lines {
+		if lineDefinesKey(line, key.Name) {
+			removed = true
+			continue
+		}
+		kept = append(kept, line)
+	}
+	if !removed {
+		return false, nil
+	}
+	return true, writeLines(path, kept)
+}
+
+// readLines lê o arquivo linha a linha; um arquivo inexistente resulta em nenhuma linha
+func readLines(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao ler o arquivo %s: %w", path, err)
+	}
+	content := strings.TrimSuffix(string(data), "\n")
+	if content == "" {
+		return nil, nil
+	}
+	return strings.Split(content, "\n"), nil
+}
+
+// writeLines grava as linhas no arquivo, preservando as permissões quando ele já existe
+func writeLines(path string, lines []string) error {
+	perm := os.FileMode(0600)
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+	content := strings.Join(lines, "\n")
+	if len(lines) > 0 {
+		content += "\n"
+	}
+	if err := os.WriteFile(path, []byte(content), perm); err != nil {
+		return fmt.Errorf("erro ao gravar o arquivo %s: %w", path, err)
+	}
+	return nil
+}
+
+// lineDefinesKey verifica se a linha do .env define a chave informada (com ou sem 'export')
+func lineDefinesKey(line, name string) bool {
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, "#") {
+		return false
+	}
+	trimmed = strings.TrimSpace(strings.TrimPrefix(trimmed, "export "))
+	idx := strings.IndexAny(trimmed, "=:")
+	if idx == -1 {
+		return false
+	}
+	return strings.TrimSpace(trimmed[:idx]) == name
+}
+
+// quoteValue adiciona aspas quando o valor contém espaços ou caracteres especiais do .env
+func quoteValue(value string) string {
+	if value == "" || strings.ContainsAny(value, " \t#\"'$") {
+		return fmt.Sprintf("%q", value)
+	}
+	return value
+}
+
+func oneOf(options ...string) func(string) error {
+	return func(value string) error {
+		for _, opt := range options {
+			if value == opt {
+				return nil
+			}
+		}
+		sorted := append([]string(nil), options...)
+		sort.Strings(sorted)
+		return fmt.Errorf("esperado um de: %s", strings.Join(sorted, ", "))
+	}
+}
+
+func notEmpty(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return fmt.Errorf("o valor não pode ser vazio")
+	}
+	return nil
+}
+
+func validBool(value string) error {
+	if _, err := strconv.ParseBool(value); err != nil {
+		return fmt.Errorf("esperado um valor booleano (true, false, 1 ou 0)")
+	}
+	return nil
+}
+
+func floatInRange(min, max float64) func(string) error {
+	return func(value string) error {
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("esperado um número")
+		}
+		if f < min || f > max {
+			return fmt.Errorf("esperado um valor entre %g e %g", min, max)
+		}
+		return nil
+	}
+}
+
+func positiveInt(value string) error {
+	if n, err := strconv.Atoi(value); err != nil || n < 1 {
+		return fmt.Errorf("esperado um número inteiro maior que zero")
+	}
+	return nil
+}
+
+func validModelID(value string) error {
+	if !modelIDPattern.MatchString(value) {
+		return fmt.Errorf("identificador de modelo inválido: %q", value)
+	}
+	return nil
+}
+
+func validSize(value string) error {
+	size, err := utils.ParseSize(value)
+	if err != nil {
+		return err
+	}
+	if size <= 0 {
+		return fmt.Errorf("o tamanho deve ser maior que zero")
+	}
+	return nil
+}
+
+func validAutoSummarize(value string) error {
+	if value == "true" || value == "false" {
+		return nil
+	}
+	if n, err := strconv.Atoi(value); err == nil && n >= 0 {
+		return nil
+	}
+	return fmt.Errorf("esperado true, false ou um limite de tokens (ex: 8000)")
+}
+
+// validOllamaHost aceita também o formato sem esquema usado pelo próprio Ollama, como 127.0.0.1:11434
+func validOllamaHost(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return fmt.Errorf("o valor não pode ser vazio")
+	}
+	_, err := ollama.NormalizeHost(value)
+	return err
+}
+
+func validBaseURL(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return fmt.Errorf("o valor não pode ser vazio")
+	}
+	_, err := utils.ValidateBaseURL(value)
+	return err
+}
+
+func validDuration(value string) error {
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return fmt.Errorf("duração inválida: %q (use, por exemplo, 30s, 2m ou um número de segundos)", value)
+	}
+	return nil
+}
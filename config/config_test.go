@@ -0,0 +1,77 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSetPreservesUnrelatedLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	original := "# Configurações Gerais\nLOG_LEVEL=info\n\nOPENAI_API_KEY=sk-test\nCUSTOM_VAR=1\n"
+	if err := os.WriteFile(path, []byte(original), 0600); err != nil {
+		t.Fatalf("Erro ao criar arquivo: %v", err)
+	}
+
+	if err := Set(path, "log_level", "debug"); err != nil {
+		t.Fatalf("Erro ao definir chave: %v", err)
+	}
+	if err := Set(path, "OPENAI_MODEL", "gpt-4o"); err != nil {
+		t.Fatalf("Erro ao definir chave: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	expected := "# Configurações Gerais\nLOG_LEVEL=debug\n\nOPENAI_API_KEY=sk-test\nCUSTOM_VAR=1\nOPENAI_MODEL=gpt-4o\n"
+	if string(data) != expected {
+		t.Errorf("Conteúdo inesperado:\n%s", string(data))
+	}
+}
+
+func TestSetRejectsInvalidValues(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+
+	if err := Set(path, "LLM_PROVIDER", "GEMINI"); err == nil {
+		t.Error("Esperado erro para provedor inválido")
+	}
+	if err := Set(path, "HISTORY_MAX_SIZE", "muito"); err == nil {
+		t.Error("Esperado erro para tamanho inválido")
+	}
+	if err := Set(path, "CHAVE_INEXISTENTE", "x"); err == nil {
+		t.Error("Esperado erro para chave desconhecida")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("O arquivo não deveria ter sido criado")
+	}
+}
+
+func TestUnset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	os.WriteFile(path, []byte("export OPENAI_MODEL=gpt-4o\nCUSTOM_VAR=1\n"), 0600)
+
+	removed, err := Unset(path, "OPENAI_MODEL")
+	if err != nil || !removed {
+		t.Fatalf("Esperado remover a chave, removed=%v err=%v", removed, err)
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "OPENAI_MODEL") || !strings.Contains(string(data), "CUSTOM_VAR=1") {
+		t.Errorf("Conteúdo inesperado: %s", string(data))
+	}
+}
+
+func TestResolveSource(t *testing.T) {
+	key, _ := LookupKey("CLAUDEAI_MODEL")
+	os.Unsetenv("CLAUDEAI_MODEL")
+
+	if e := Resolve(key, map[string]string{}); e.Source != SourceDefault {
+		t.Errorf("Esperado origem padrão, obtido %s", e.Source)
+	}
+	if e := Resolve(key, map[string]string{"CLAUDEAI_MODEL": "claude-3-opus"}); e.Source != SourceFile {
+		t.Errorf("Esperado origem arquivo, obtido %s", e.Source)
+	}
+
+	t.Setenv("CLAUDEAI_MODEL", "claude-3-haiku")
+	if e := Resolve(key, map[string]string{"CLAUDEAI_MODEL": "claude-3-opus"}); e.Source != SourceEnv || e.Value != "claude-3-haiku" {
+		t.Errorf("Esperado origem env, obtido %s (%s)", e.Source, e.Value)
+	}
+}
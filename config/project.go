@@ -0,0 +1,280 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ProjectConfigFileNames lista, em ordem de preferência, os nomes de arquivo de configuração de projeto
+var ProjectConfigFileNames = []string{".chatcli.yaml", ".chatcli.yml", ".chatcli.toml"}
+
+// ProjectConfig representa as configurações de um projeto, lidas de .chatcli.yaml ou .chatcli.toml.
+// Os valores sobrescrevem os padrões vindos das variáveis de ambiente.
+type ProjectConfig struct {
+	Path         string
+	Provider     string
+	Model        string
+	SystemPrompt string
+	// SystemPromptFile é um arquivo com o prompt de sistema, relativo ao arquivo de configuração
+	SystemPromptFile string
+	Context          []string
+}
+
+// FindProjectConfig procura um arquivo de configuração de projeto no diretório informado
+// e em seus ancestrais, retornando o caminho do primeiro encontrado ou "" se não houver.
+func FindProjectConfig(startDir string) (string, error) {
+	dir, err := filepath.Abs(startDir)
+	if err != nil {
+		return "", fmt.Errorf("não foi possível determinar o caminho absoluto: %w", err)
+	}
+
+	for {
+		for _, name := range ProjectConfigFileNames {
+			candidate := filepath.Join(dir, name)
+			if info, err := os.Stat(candidate); err == nil && info.Mode().IsRegular() {
+				return candidate, nil
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// LoadProjectConfig localiza e carrega a configuração de projeto a partir do diretório informado.
+// Retorna nil, nil quando nenhum arquivo é encontrado.
+func LoadProjectConfig(startDir string) (*ProjectConfig, error) {
+	path, err := FindProjectConfig(startDir)
+	if err != nil || path == "" {
+		return nil, err
+	}
+	return ParseProjectConfigFile(path)
+}
+
+// ParseProjectConfigFile lê e interpreta um arquivo de configuração de projeto (YAML ou TOML)
+func ParseProjectConfigFile(path string) (*ProjectConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler o arquivo %s: %w", path, err)
+	}
+
+	var values map[string]interface{}
+	if strings.HasSuffix(path, ".toml") {
+		values, err = parseFlatTOML(string(data))
+	} else {
+		values, err = parseFlatYAML(string(data))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao interpretar %s: %w", path, err)
+	}
+
+	pc := &ProjectConfig{Path: path}
+	for key, value := range values {
+		switch key {
+		case "provider":
+			pc.Provider = strings.ToUpper(asString(value))
+		case "model":
+			pc.Model = asString(value)
+		case "system_prompt", "system":
+			pc.SystemPrompt = asString(value)
+		case "system_prompt_file":
+			pc.SystemPromptFile = asString(value)
+		case "context", "default_context":
+			pc.Context = asList(value)
+		default:
+			return nil, fmt.Errorf("chave desconhecida em %s: %s", path, key)
+		}
+	}
+
+	if pc.Provider != "" {
+		providerKey, _ := LookupKey("LLM_PROVIDER")
+		if err := ValidateValue(providerKey, pc.Provider); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	if pc.Model != "" {
+		if err := validModelID(pc.Model); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	return pc, nil
+}
+
+// parseFlatYAML interpreta o subconjunto de YAML usado pelo arquivo de projeto:
+// pares "chave: valor", listas com "- item" e blocos de texto com "|".
+func parseFlatYAML(content string) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if line != strings.TrimLeft(line, " \t") {
+			return nil, fmt.Errorf("linha %d: indentação inesperada", i+1)
+		}
+
+		idx := strings.Index(trimmed, ":")
+		if idx == -1 {
+			return nil, fmt.Errorf("linha %d: esperado 'chave: valor'", i+1)
+		}
+		key := strings.TrimSpace(trimmed[:idx])
+		raw := strings.TrimSpace(trimmed[idx+1:])
+
+		switch {
+		case raw == "|" || raw == "|-" || raw == ">":
+			var block []string
+			for i+1 < len(lines) && (strings.TrimSpace(lines[i+1]) == "" || strings.HasPrefix(lines[i+1], " ") || strings.HasPrefix(lines[i+1], "\t")) {
+				i++
+				block = append(block, strings.TrimSpace(lines[i]))
+			}
+			sep := "\n"
+			if raw == ">" {
+				sep = " "
+			}
+			values[key] = strings.TrimSpace(strings.Join(block, sep))
+		case raw == "":
+			var items []string
+			for i+1 < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i+1]), "- ") {
+				i++
+				items = append(items, unquote(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(lines[i]), "- "))))
+			}
+			values[key] = items
+		case strings.HasPrefix(raw, "["):
+			items, err := parseInlineList(raw)
+			if err != nil {
+				return nil, fmt.Errorf("linha %d: %w", i+1, err)
+			}
+			values[key] = items
+		default:
+			values[key] = unquote(stripComment(raw))
+		}
+	}
+
+	return values, nil
+}
+
+// parseFlatTOML interpreta o subconjunto de TOML usado pelo arquivo de projeto:
+// pares "chave = valor", listas inline e strings multilinha com aspas triplas.
+func parseFlatTOML(content string) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		idx := strings.Index(trimmed, "=")
+		if idx == -1 {
+			return nil, fmt.Errorf("linha %d: esperado 'chave = valor'", i+1)
+		}
+		key := strings.TrimSpace(trimmed[:idx])
+		raw := strings.TrimSpace(trimmed[idx+1:])
+
+		switch {
+		case strings.HasPrefix(raw, `"""`):
+			rest := strings.TrimPrefix(raw, `"""`)
+			var block []string
+			for !strings.Contains(rest, `"""`) {
+				block = append(block, rest)
+				i++
+				if i >= len(lines) {
+					return nil, fmt.Errorf("string multilinha não fechada para '%s'", key)
+				}
+				rest = lines[i]
+			}
+			block = append(block, rest[:strings.Index(rest, `"""`)])
+			values[key] = strings.TrimSpace(strings.Join(block, "\n"))
+		case strings.HasPrefix(raw, "["):
+			items, err := parseInlineList(stripComment(raw))
+			if err != nil {
+				return nil, fmt.Errorf("linha %d: %w", i+1, err)
+			}
+			values[key] = items
+		default:
+			values[key] = unquote(stripComment(raw))
+		}
+	}
+
+	return values, nil
+}
+
+// parseInlineList interpreta listas no formato ["a", "b"]
+func parseInlineList(raw string) ([]string, error) {
+	if !strings.HasPrefix(raw, "[") || !strings.HasSuffix(raw, "]") {
+		return nil, fmt.Errorf("lista mal formada: %s", raw)
+	}
+	inner := strings.TrimSpace(raw[1 : len(raw)-1])
+	if inner == "" {
+		return nil, nil
+	}
+	var items []string
+	for _, part := range strings.Split(inner, ",") {
+		if item := unquote(strings.TrimSpace(part)); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+// stripComment remove comentários ao final de valores que não estão entre aspas
+func stripComment(raw string) string {
+	if strings.HasPrefix(raw, `"`) || strings.HasPrefix(raw, `'`) {
+		return raw
+	}
+	if idx := strings.Index(raw, " #"); idx != -1 {
+		return strings.TrimSpace(raw[:idx])
+	}
+	return raw
+}
+
+// unquote remove aspas simples ou duplas ao redor de um valor
+func unquote(raw string) string {
+	if len(raw) >= 2 {
+		if raw[0] == '"' && raw[len(raw)-1] == '"' {
+			if s, err := strconv.Unquote(raw); err == nil {
+				return s
+			}
+			return raw[1 : len(raw)-1]
+		}
+		if raw[0] == '\'' && raw[len(raw)-1] == '\'' {
+			return raw[1 : len(raw)-1]
+		}
+	}
+	return raw
+}
+
+func asString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []string:
+		return strings.Join(v, "\n")
+	default:
+		return ""
+	}
+}
+
+func asList(value interface{}) []string {
+	switch v := value.(type) {
+	case []string:
+		return v
+	case string:
+		if v == "" {
+			return nil
+		}
+		return []string{v}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadProjectConfigYAMLFromAncestor(t *testing.T) {
+	root := t.TempDir()
+	content := `# Configuração do projeto
+provider: openai
+model: gpt-4o
+system_prompt: |
+  Você é um revisor de código Go.
+  Responda em português.
+context:
+  - go.mod
+  - "README.md"
+`
+	if err := os.WriteFile(filepath.Join(root, ".chatcli.yaml"), []byte(content), 0644); err != nil {
+		t.Fatalf("Erro ao criar arquivo: %v", err)
+	}
+	subDir := filepath.Join(root, "cmd", "app")
+	os.MkdirAll(subDir, 0755)
+
+	pc, err := LoadProjectConfig(subDir)
+	if err != nil {
+		t.Fatalf("Erro ao carregar configuração: %v", err)
+	}
+	if pc == nil {
+		t.Fatal("Esperado encontrar a configuração no diretório ancestral")
+	}
+	if pc.Provider != "OPENAI" || pc.Model != "gpt-4o" {
+		t.Errorf("Provedor/modelo inesperados: %s/%s", pc.Provider, pc.Model)
+	}
+	if pc.SystemPrompt != "Você é um revisor de código Go.\nResponda em português." {
+		t.Errorf("Prompt de sistema inesperado: %q", pc.SystemPrompt)
+	}
+	if !reflect.DeepEqual(pc.Context, []string{"go.mod", "README.md"}) {
+		t.Errorf("Contexto inesperado: %v", pc.Context)
+	}
+}
+
+func TestParseProjectConfigTOML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".chatcli.toml")
+	content := `provider = "CLAUDEAI"
+system_prompt = """
+Seja conciso.
+"""
+system_prompt_file = "prompts/revisor.md"
+context = ["go.mod", "main.go"]
+`
+	os.WriteFile(path, []byte(content), 0644)
+
+	pc, err := ParseProjectConfigFile(path)
+	if err != nil {
+		t.Fatalf("Erro ao interpretar TOML: %v", err)
+	}
+	if pc.Provider != "CLAUDEAI" || pc.SystemPrompt != "Seja conciso." || pc.SystemPromptFile != "prompts/revisor.md" || len(pc.Context) != 2 {
+		t.Errorf("Configuração inesperada: %+v", pc)
+	}
+}
+
+func TestParseProjectConfigRejectsInvalidProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".chatcli.yaml")
+	os.WriteFile(path, []byte("provider: inexistente\n"), 0644)
+
+	if _, err := ParseProjectConfigFile(path); err == nil {
+		t.Error("Esperado erro para provedor inválido")
+	}
+}
+
+func TestLoadProjectConfigNotFound(t *testing.T) {
+	pc, err := LoadProjectConfig(t.TempDir())
+	if err != nil || pc != nil {
+		t.Errorf("Esperado nenhuma configuração, obtido %+v (%v)", pc, err)
+	}
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/diillson/chatcli/utils"
+)
+
+// DefaultProvidersFile é o arquivo de provedores compatíveis com a OpenAI usado quando
+// CHATCLI_PROVIDERS_FILE não está definido
+const DefaultProvidersFile = "~/.chatcli/providers.json"
+
+// builtinProviderNames não podem ser usados por provedores registrados com /providers add
+var builtinProviderNames = []string{"OPENAI", "STACKSPOT", "CLAUDEAI", "OLLAMA", "AUTO"}
+
+var (
+	// providerNamePattern segue o formato aceito por @provider
+	providerNamePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_-]*$`)
+	envNamePattern      = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// CustomProvider é um endpoint compatível com a API da OpenAI registrado com um nome, como GROQ ou
+// um vLLM local. A chave é lida da variável APIKeyEnv, nunca gravada no arquivo.
+type CustomProvider struct {
+	Name      string `json:"name"`
+	BaseURL   string `json:"base_url"`
+	APIKeyEnv string `json:"api_key_env,omitempty"`
+	Model     string `json:"model"`
+}
+
+// Validate confere o nome, a URL base, a variável da chave e o modelo padrão, normalizando o nome
+// para maiúsculas e a URL sem a barra final
+func (p *CustomProvider) Validate() error {
+	p.Name = strings.ToUpper(strings.TrimSpace(p.Name))
+	if !providerNamePattern.MatchString(p.Name) {
+		return fmt.Errorf("nome de provedor inválido %q: use letras, números, _ e -, começando com uma letra", p.Name)
+	}
+	for _, builtin := range builtinProviderNames {
+		if p.Name == builtin {
+			return fmt.Errorf("%s é um provedor embutido e não pode ser redefinido", p.Name)
+		}
+	}
+	if strings.TrimSpace(p.BaseURL) == "" {
+		return fmt.Errorf("informe a URL base do provedor %s", p.Name)
+	}
+	baseURL, err := utils.ValidateBaseURL(p.BaseURL)
+	if err != nil {
+		return err
+	}
+	p.BaseURL = baseURL
+	if p.APIKeyEnv != "" && !envNamePattern.MatchString(p.APIKeyEnv) {
+		return fmt.Errorf("nome de variável inválido para a chave: %s", p.APIKeyEnv)
+	}
+	if strings.TrimSpace(p.Model) == "" {
+		return fmt.Errorf("informe o modelo padrão do provedor %s", p.Name)
+	}
+	return nil
+}
+
+// ProvidersPath retorna o arquivo de provedores configurado, já expandido
+func ProvidersPath() (string, error) {
+	return utils.ExpandPath(utils.GetEnvOrDefault("CHATCLI_PROVIDERS_FILE", DefaultProvidersFile))
+}
+
+// LoadCustomProviders lê os provedores registrados. Um arquivo inexistente resulta em nenhum
+// provedor; entradas inválidas interrompem a leitura com o erro da primeira delas.
+func LoadCustomProviders(path string) ([]CustomProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao ler os provedores em %s: %w", path, err)
+	}
+	var providers []CustomProvider
+	if err := json.Unmarshal(data, &providers); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar os provedores em %s: %w", path, err)
+	}
+	for i := range providers {
+		if err := providers[i].Validate(); err != nil {
+			return nil, fmt.Errorf("provedor %d em %s: %w", i+1, path, err)
+		}
+	}
+	return providers, nil
+}
+
+// SaveCustomProviders grava os provedores, criando o diretório se necessário
+func SaveCustomProviders(path string, providers []CustomProvider) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("erro ao criar o diretório dos provedores: %w", err)
+	}
+	data, err := json.MarshalIndent(providers, "", "  ")
+	if err != nil {
+		return fmt.Errorf("erro ao codificar os provedores: %w", err)
+	}
+	if err := utils.WriteFileAtomic(path, data, 0600); err != nil {
+		return fmt.Errorf("erro ao gravar os provedores em %s: %w", path, err)
+	}
+	return nil
+}
+
+// validProvider aceita os provedores embutidos e os registrados em CHATCLI_PROVIDERS_FILE
+func validProvider(value string) error {
+	err := oneOf(builtinProviderNames...)(value)
+	if err == nil {
+		return nil
+	}
+	if path, pathErr := ProvidersPath(); pathErr == nil {
+		providers, _ := LoadCustomProviders(path)
+		for _, p := range providers {
+			if p.Name == value {
+				return nil
+			}
+		}
+	}
+	return err
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestCustomProviderValidate(t *testing.T) {
+	p := CustomProvider{Name: "groq", BaseURL: "https://api.groq.com/openai/v1/", APIKeyEnv: "GROQ_API_KEY", Model: "llama-3.1-8b-instant"}
+	if err := p.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "GROQ" || p.BaseURL != "https://api.groq.com/openai/v1" {
+		t.Errorf("Provedor não normalizado: %+v", p)
+	}
+
+	for _, invalid := range []CustomProvider{
+		{Name: "openai", BaseURL: "https://x", Model: "m"},
+		{Name: "1abc", BaseURL: "https://x", Model: "m"},
+		{Name: "vllm", BaseURL: "localhost:8000", Model: "m"},
+		{Name: "vllm", BaseURL: "http://localhost:8000/v1", APIKeyEnv: "MINHA-CHAVE", Model: "m"},
+		{Name: "vllm", BaseURL: "http://localhost:8000/v1"},
+	} {
+		if err := invalid.Validate(); err == nil {
+			t.Errorf("Esperava erro para %+v", invalid)
+		}
+	}
+}
+
+func TestCustomProvidersRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "providers.json")
+	t.Setenv("CHATCLI_PROVIDERS_FILE", path)
+
+	if providers, err := LoadCustomProviders(path); err != nil || len(providers) != 0 {
+		t.Fatalf("Esperava nenhum provedor, obtive %v (%v)", providers, err)
+	}
+	if err := validProvider("GROQ"); err == nil {
+		t.Error("GROQ ainda não está registrado")
+	}
+
+	if err := SaveCustomProviders(path, []CustomProvider{{Name: "GROQ", BaseURL: "https://api.groq.com/openai/v1", Model: "m"}}); err != nil {
+		t.Fatal(err)
+	}
+	providers, err := LoadCustomProviders(path)
+	if err != nil || len(providers) != 1 || providers[0].Name != "GROQ" {
+		t.Fatalf("Provedores inesperados: %v (%v)", providers, err)
+	}
+	// Um provedor registrado passa a ser aceito em LLM_PROVIDER
+	key, _ := LookupKey("LLM_PROVIDER")
+	if err := ValidateValue(key, "GROQ"); err != nil {
+		t.Errorf("GROQ deveria ser aceito em LLM_PROVIDER: %v", err)
+	}
+}
@@ -2,10 +2,15 @@ package claudeai
 
 import (
 	"context"
+	"errors"
 	"github.com/diillson/chatcli/llm/client"
+	"io"
+	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/diillson/chatcli/models"
+	"go.uber.org/zap"
 )
 
 func TestClaudeClient_SendPrompt(t *testing.T) {
@@ -26,3 +31,97 @@ func TestClaudeClient_SendPrompt(t *testing.T) {
 		t.Errorf("Resposta inesperada: %s", response)
 	}
 }
+
+func TestClaudeClient_applyGenerationParams(t *testing.T) {
+	temperature, penalty := 1.5, 0.5
+	c := NewClaudeClient("key", "model", "", zap.NewNop())
+	c.SetGenerationParams(models.GenerationParams{Temperature: &temperature, PresencePenalty: &penalty})
+
+	reqBody := map[string]interface{}{}
+	c.applyGenerationParams(reqBody)
+
+	if reqBody["temperature"] != 1.0 {
+		t.Errorf("Esperado temperature limitada a 1, obteve: %v", reqBody["temperature"])
+	}
+	if _, ok := reqBody["presence_penalty"]; ok {
+		t.Error("presence_penalty não deveria ser enviada para a ClaudeAI")
+	}
+	if reqBody["max_tokens"] != models.DefaultClaudeMaxTokens {
+		t.Errorf("Esperado max_tokens padrão, obteve: %v", reqBody["max_tokens"])
+	}
+}
+
+func TestClaudeClient_maxTokensClamped(t *testing.T) {
+	maxTokens := 100000
+	c := NewClaudeClient("key", "claude-3-opus-20240229", "", zap.NewNop())
+	reqBody := map[string]interface{}{}
+	c.applyGenerationParams(reqBody)
+	if reqBody["max_tokens"] != 4096 {
+		t.Errorf("Esperado o padrão limitado a 4096, obteve: %v", reqBody["max_tokens"])
+	}
+
+	c.SetGenerationParams(models.GenerationParams{MaxTokens: &maxTokens})
+	c.applyGenerationParams(reqBody)
+	if reqBody["max_tokens"] != 4096 {
+		t.Errorf("Esperado max_tokens limitado a 4096, obteve: %v", reqBody["max_tokens"])
+	}
+}
+
+func TestClaudeClient_buildMessagesWithImages(t *testing.T) {
+	c := NewClaudeClient("key", "claude-3-5-sonnet-20241022", "", zap.NewNop())
+	if !c.SupportsImages() || NewClaudeClient("key", "claude-2.1", "", zap.NewNop()).SupportsImages() {
+		t.Error("Suporte a imagens inesperado")
+	}
+
+	messages := c.buildMessages("descreva", []models.Image{{MediaType: "image/jpeg", Data: []byte("jpg")}}, nil)
+	blocks, ok := messages[0]["content"].([]map[string]interface{})
+	if !ok || len(blocks) != 2 || blocks[0]["type"] != "image" || blocks[1]["text"] != "descreva" {
+		t.Fatalf("Blocos inesperados: %v", messages[0]["content"])
+	}
+	if source := blocks[0]["source"].(map[string]interface{}); source["media_type"] != "image/jpeg" || source["data"] != "anBn" {
+		t.Errorf("Origem da imagem inesperada: %v", source)
+	}
+}
+
+func TestClaudeClient_parseResponseRefusal(t *testing.T) {
+	c := NewClaudeClient("key", "claude-3-5-sonnet-20241022", "", zap.NewNop())
+	respond := func(body string) (string, error) {
+		return c.parseResponse(&http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))})
+	}
+
+	if _, err := respond(`{"content":[],"stop_reason":"refusal"}`); !errors.Is(err, client.ErrContentFiltered) {
+		t.Errorf("Esperado ErrContentFiltered, obtido %v", err)
+	}
+	response, err := respond(`{"content":[{"type":"text","text":"Começo"}],"stop_reason":"refusal"}`)
+	if err != nil || !strings.HasPrefix(response, "Começo") || !strings.Contains(response, "filtro de conteúdo") {
+		t.Errorf("Resposta parcial: esperado o texto com um aviso, obtido %q (%v)", response, err)
+	}
+	if response, err := respond(`{"content":[{"type":"text","text":"Olá"}],"stop_reason":"end_turn"}`); err != nil || response != "Olá" {
+		t.Errorf("Resposta inesperada: %q (%v)", response, err)
+	}
+}
+
+func TestExtractSystemPrompt(t *testing.T) {
+	history := []models.Message{
+		{Role: "system", Content: "prompt do projeto", SystemPrompt: true},
+		{Role: "system", Content: "Saída do comando: ls"},
+		{Role: "user", Content: "olá"},
+	}
+
+	system, rest := extractSystemPrompt(history)
+	if system != "prompt do projeto" {
+		t.Errorf("Esperado apenas o primeiro prompt de sistema, obtido %q", system)
+	}
+	if len(rest) != 2 || rest[0].Content != "Saída do comando: ls" {
+		t.Errorf("As demais mensagens de sistema deveriam continuar no histórico: %+v", rest)
+	}
+
+	if system, rest := extractSystemPrompt(history[2:]); system != "" || len(rest) != 1 {
+		t.Errorf("Histórico sem sistema não deveria mudar: %q %+v", system, rest)
+	}
+
+	// Sem contexto do CLI, a saída de um @command abre o histórico e não pode virar instrução de sistema
+	if system, rest := extractSystemPrompt(history[1:]); system != "" || len(rest) != 2 {
+		t.Errorf("A saída do comando não deveria ser promovida: %q %+v", system, rest)
+	}
+}
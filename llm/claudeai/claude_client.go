@@ -2,37 +2,51 @@ package claudeai
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"github.com/diillson/chatcli/llm/client"
 	"github.com/diillson/chatcli/models"
 	"github.com/diillson/chatcli/utils"
 	"go.uber.org/zap"
 	"io"
 	"net/http"
 	"strings"
-	"time"
 )
 
+// DefaultBaseURL é a URL da API da ClaudeAI, usada quando CLAUDEAI_BASE_URL não está definida
+const DefaultBaseURL = "https://api.anthropic.com/v1"
+
 const (
-	claudeAIAPIURL = "https://api.anthropic.com/v1/messages"
+	claudeAIMessagesPath = "/messages"
+	claudeAIModelsPath   = "/models"
+	// claudeAIMaxImageSize é o tamanho máximo de cada imagem aceito pela API
+	claudeAIMaxImageSize = 5 * 1024 * 1024
 )
 
 // ClaudeClient é uma estrutura que contém o cliente de ClaudeAI com suas configurações
 type ClaudeClient struct {
 	apiKey string
 	model  string
+	apiURL string
 	logger *zap.Logger
 	client *http.Client
+	params models.GenerationParams
 }
 
-// NewClaudeClient cria um novo cliente ClaudeAI com configurações personalizáveis
-func NewClaudeClient(apiKey string, model string, logger *zap.Logger) *ClaudeClient {
+// NewClaudeClient cria um novo cliente ClaudeAI com configurações personalizáveis.
+// O baseURL permite apontar para um endpoint compatível; vazio usa o endpoint oficial.
+func NewClaudeClient(apiKey string, model string, baseURL string, logger *zap.Logger) *ClaudeClient {
 	// Usar o transporte HTTP com logging
-	httpClient := utils.NewHTTPClient(logger, 300*time.Second)
+	httpClient := utils.NewProviderHTTPClient(logger)
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
 
 	return &ClaudeClient{
 		apiKey: apiKey,
 		model:  model,
+		apiURL: strings.TrimSuffix(baseURL, "/") + claudeAIMessagesPath,
 		logger: logger,
 		client: httpClient,
 	}
@@ -46,18 +60,46 @@ func (c *ClaudeClient) GetModelName() string {
 	return c.model
 }
 
+// SetGenerationParams define os parâmetros de amostragem enviados em cada requisição
+func (c *ClaudeClient) SetGenerationParams(params models.GenerationParams) {
+	c.params = params
+}
+
 // SendPrompt monta a requisição com o histórico e a envia para a ClaudeAI, retornando a resposta formatada
 func (c *ClaudeClient) SendPrompt(ctx context.Context, prompt string, history []models.Message) (string, error) {
-	messages := c.buildMessages(prompt, history)
+	return c.SendPromptWithImages(ctx, prompt, nil, history)
+}
+
+// SupportsImages indica se o modelo configurado aceita imagens. Todos os modelos a partir do
+// Claude 3 aceitam; as gerações anteriores são apenas de texto.
+func (c *ClaudeClient) SupportsImages() bool {
+	model := strings.ToLower(c.model)
+	return !strings.HasPrefix(model, "claude-2") && !strings.HasPrefix(model, "claude-instant")
+}
+
+// SendPromptWithImages envia o prompt com as imagens anexadas à mensagem do usuário. O nível de
+// detalhe é específico da OpenAI e é ignorado.
+func (c *ClaudeClient) SendPromptWithImages(ctx context.Context, prompt string, images []models.Image, history []models.Message) (string, error) {
+	for _, image := range images {
+		if len(image.Data) > claudeAIMaxImageSize {
+			return "", fmt.Errorf("a imagem %s excede o limite de %d MB da ClaudeAI", image.Path, claudeAIMaxImageSize/(1024*1024))
+		}
+	}
+
+	systemPrompt, history := extractSystemPrompt(history)
+	messages := c.buildMessages(prompt, images, history)
 
 	reqBody := map[string]interface{}{
-		"model":      c.model,
-		"max_tokens": 8192,
-		"messages":   messages,
+		"model":    c.model,
+		"messages": messages,
 	}
+	if systemPrompt != "" {
+		reqBody["system"] = systemPrompt
+	}
+	c.applyGenerationParams(reqBody)
 	reqJSON, _ := json.Marshal(reqBody)
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, claudeAIAPIURL, strings.NewReader(string(reqJSON)))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL, strings.NewReader(string(reqJSON)))
 	if err != nil {
 		c.logger.Error("Erro ao criar a requisição de prompt", zap.Error(err))
 		return "", fmt.Errorf("erro ao criar a requisição: %w", err)
@@ -70,22 +112,82 @@ func (c *ClaudeClient) SendPrompt(ctx context.Context, prompt string, history []
 	resp, err := c.client.Do(req)
 	if err != nil {
 		c.logger.Error("Erro ao fazer a requisição de prompt", zap.Error(err))
-		return "", fmt.Errorf("erro ao fazer a requisição: %w", err)
+		return "", client.WrapTransportError("ClaudeAI", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		c.logger.Error("Erro ao obter resposta da ClaudeAI", zap.Int("status", resp.StatusCode), zap.String("body", string(body)))
-		return "", fmt.Errorf("erro ao obter resposta da ClaudeAI: status %d, body %s", resp.StatusCode, string(body))
+		return "", client.NewHTTPError("ClaudeAI", resp.StatusCode, resp.Header, body)
 	}
 
 	return c.parseResponse(resp)
 }
 
+// CheckKey valida a chave listando os modelos, uma chamada autenticada que não consome tokens
+func (c *ClaudeClient) CheckKey(ctx context.Context) error {
+	modelsURL := strings.TrimSuffix(c.apiURL, claudeAIMessagesPath) + claudeAIModelsPath
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, modelsURL, nil)
+	if err != nil {
+		return fmt.Errorf("erro ao criar a requisição: %w", err)
+	}
+	req.Header.Add("x-api-key", c.apiKey)
+	req.Header.Add("anthropic-version", "2023-06-01")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return client.WrapTransportError("ClaudeAI", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return client.NewHTTPError("ClaudeAI", resp.StatusCode, resp.Header, body)
+	}
+	return nil
+}
+
+// extractSystemPrompt separa o contexto de sistema montado pelo CLI (marcado com SystemPrompt), que a
+// ClaudeAI espera no campo "system" da requisição. As demais mensagens de sistema, como resumos e saídas
+// de comandos, continuam na lista de mensagens para não serem promovidas a instruções, mesmo quando
+// abrem o histórico.
+func extractSystemPrompt(history []models.Message) (string, []models.Message) {
+	if len(history) == 0 || history[0].Role != "system" || !history[0].SystemPrompt {
+		return "", history
+	}
+	return history[0].Content, history[1:]
+}
+
+// applyGenerationParams adiciona max_tokens, temperature e top_p à requisição. A ClaudeAI exige
+// max_tokens, aceita temperature apenas entre 0 e 1 e não possui penalidades de presença/frequência,
+// que são ignoradas.
+func (c *ClaudeClient) applyGenerationParams(reqBody map[string]interface{}) {
+	maxTokens, clamped := models.ResolveMaxTokens(c.model, c.params.MaxTokens, models.DefaultClaudeMaxTokens)
+	if clamped {
+		c.logger.Warn("max_tokens acima do limite do modelo, usando o máximo documentado",
+			zap.String("model", c.model), zap.Int("solicitado", *c.params.MaxTokens), zap.Int("max_tokens", maxTokens))
+	}
+	reqBody["max_tokens"] = maxTokens
+	if c.params.Temperature != nil {
+		temperature := *c.params.Temperature
+		if temperature > 1 {
+			c.logger.Debug("Temperature acima do máximo da ClaudeAI, usando 1", zap.Float64("temperature", temperature))
+			temperature = 1
+		}
+		reqBody["temperature"] = temperature
+	}
+	if c.params.TopP != nil {
+		reqBody["top_p"] = *c.params.TopP
+	}
+	if c.params.PresencePenalty != nil || c.params.FrequencyPenalty != nil {
+		c.logger.Debug("Penalidades de presença/frequência não são suportadas pela ClaudeAI e serão ignoradas")
+	}
+}
+
 // buildMessages monta o histórico de mensagens para incluir na requisição
-func (c *ClaudeClient) buildMessages(prompt string, history []models.Message) []map[string]string {
-	messages := make([]map[string]string, len(history))
+func (c *ClaudeClient) buildMessages(prompt string, images []models.Image, history []models.Message) []map[string]interface{} {
+	messages := make([]map[string]interface{}, len(history))
 
 	// Processa o histórico, garantindo que role e content estejam bem definidos
 	for i, msg := range history {
@@ -93,11 +195,26 @@ func (c *ClaudeClient) buildMessages(prompt string, history []models.Message) []
 		if msg.Role == "assistant" {
 			role = "assistant"
 		}
-		messages[i] = map[string]string{"role": role, "content": msg.Content}
+		messages[i] = map[string]interface{}{"role": role, "content": msg.Content}
 	}
 
-	// Adiciona a mensagem atual do usuário ao final
-	messages = append(messages, map[string]string{"role": "user", "content": prompt})
+	// Adiciona a mensagem atual do usuário ao final; com imagens, o conteúdo é uma lista de blocos
+	var content interface{} = prompt
+	if len(images) > 0 {
+		blocks := make([]map[string]interface{}, 0, len(images)+1)
+		for _, image := range images {
+			blocks = append(blocks, map[string]interface{}{
+				"type": "image",
+				"source": map[string]interface{}{
+					"type":       "base64",
+					"media_type": image.MediaType,
+					"data":       base64.StdEncoding.EncodeToString(image.Data),
+				},
+			})
+		}
+		content = append(blocks, map[string]interface{}{"type": "text", "text": prompt})
+	}
+	messages = append(messages, map[string]interface{}{"role": "user", "content": content})
 
 	return messages
 }
@@ -109,10 +226,11 @@ func (c *ClaudeClient) parseResponse(resp *http.Response) (string, error) {
 			Type string `json:"type"`
 			Text string `json:"text"`
 		} `json:"content"`
+		StopReason string `json:"stop_reason"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		c.logger.Error("Erro ao decodificar a resposta da ClaudeAI", zap.Error(err))
-		return "", fmt.Errorf("erro ao decodificar a resposta: %w", err)
+		return "", client.WrapTransportError("ClaudeAI", fmt.Errorf("erro ao decodificar a resposta: %w", err))
 	}
 
 	var responseText string
@@ -122,6 +240,15 @@ func (c *ClaudeClient) parseResponse(resp *http.Response) (string, error) {
 		}
 	}
 
+	// stop_reason refusal indica que os classificadores de segurança interromperam a resposta
+	if result.StopReason == "refusal" {
+		c.logger.Warn("Resposta recusada pela ClaudeAI", zap.Int("tamanho_parcial", len(responseText)))
+		if responseText == "" {
+			return "", client.NewContentFilterError("ClaudeAI", "stop_reason refusal", "", nil)
+		}
+		return responseText + "\n\n[Resposta interrompida pelo filtro de conteúdo do provedor]", nil
+	}
+
 	if responseText == "" {
 		c.logger.Error("Nenhum conteúdo de texto encontrado na resposta da ClaudeAI")
 		return "", fmt.Errorf("erro ao obter a resposta da ClaudeAI")
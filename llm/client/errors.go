@@ -0,0 +1,220 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Erros sentinela que classificam as falhas dos provedores. Use errors.Is para identificá-los
+// e errors.As com *ProviderError para obter o status HTTP e o RetryAfter.
+var (
+	ErrAuth           = errors.New("falha de autenticação com o provedor")
+	ErrRateLimited    = errors.New("limite de requisições excedido")
+	ErrContextTooLong = errors.New("o contexto excede o limite do modelo")
+	ErrTimeout        = errors.New("tempo limite excedido")
+	// ErrContentFiltered indica que o provedor recusou o pedido ou a resposta por suas políticas de conteúdo
+	ErrContentFiltered = errors.New("bloqueado pelas políticas de conteúdo do provedor")
+)
+
+// minRateLimitDelay é a espera mínima antes de repetir uma requisição limitada sem Retry-After
+const minRateLimitDelay = time.Second
+
+// contextTooLongMarkers são trechos usados pelos provedores para indicar que o prompt é grande demais
+var contextTooLongMarkers = []string{
+	"context_length_exceeded",
+	"maximum context length",
+	"prompt is too long",
+	"too many tokens",
+}
+
+// contentFilterMarkers são trechos usados pelos provedores (inclusive a Azure OpenAI) em erros de política de conteúdo
+var contentFilterMarkers = []string{
+	"content_policy_violation",
+	"content_filter",
+	"responsibleaipolicyviolation",
+}
+
+// ProviderError representa uma falha ao chamar um provedor. Kind contém o erro sentinela
+// correspondente (ou nil, quando a falha não se encaixa em nenhuma categoria).
+type ProviderError struct {
+	Provider   string
+	StatusCode int
+	Kind       error
+	RetryAfter time.Duration
+	Message    string
+	Err        error
+	// Categories lista as categorias apontadas pelo filtro de conteúdo, quando o provedor as informa
+	Categories []string
+}
+
+// Error implementa a interface de erro para ProviderError
+func (e *ProviderError) Error() string {
+	if e.StatusCode == 0 {
+		return fmt.Sprintf("erro na requisição à %s: %v", e.Provider, e.Err)
+	}
+	return fmt.Sprintf("erro na requisição à %s: status %d, resposta: %s", e.Provider, e.StatusCode, e.Message)
+}
+
+// Unwrap permite que errors.Is/As encontrem tanto a categoria quanto o erro de transporte original
+func (e *ProviderError) Unwrap() []error {
+	var errs []error
+	if e.Kind != nil {
+		errs = append(errs, e.Kind)
+	}
+	if e.Err != nil {
+		errs = append(errs, e.Err)
+	}
+	return errs
+}
+
+// NewHTTPError cria um ProviderError a partir de uma resposta HTTP sem sucesso, classificando-a pelo status e pelo corpo
+func NewHTTPError(provider string, statusCode int, header http.Header, body []byte) *ProviderError {
+	e := &ProviderError{
+		Provider:   provider,
+		StatusCode: statusCode,
+		Message:    string(body),
+	}
+
+	lowerBody := strings.ToLower(e.Message)
+	switch {
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		e.Kind = ErrAuth
+	case statusCode == http.StatusTooManyRequests || strings.Contains(e.Message, "RATE_LIMIT_EXCEEDED"):
+		e.Kind = ErrRateLimited
+		e.RetryAfter = parseRetryAfter(header)
+	case statusCode == http.StatusBadRequest && containsAny(lowerBody, contentFilterMarkers):
+		e.Kind = ErrContentFiltered
+		e.Categories = FilteredCategories(body)
+	case statusCode == http.StatusRequestEntityTooLarge || containsAny(lowerBody, contextTooLongMarkers):
+		e.Kind = ErrContextTooLong
+	case statusCode == http.StatusRequestTimeout || statusCode == http.StatusGatewayTimeout:
+		e.Kind = ErrTimeout
+	}
+	return e
+}
+
+// NewContentFilterError cria o erro de uma resposta recusada pelo provedor apesar do status de sucesso,
+// como finish_reason content_filter da OpenAI ou stop_reason refusal da ClaudeAI. A explicação do
+// modelo, quando houver, fica em Message.
+func NewContentFilterError(provider, reason, explanation string, categories []string) *ProviderError {
+	return &ProviderError{Provider: provider, Kind: ErrContentFiltered, Err: errors.New(reason), Message: explanation, Categories: categories}
+}
+
+// FilteredCategories extrai as categorias marcadas como filtradas de um corpo de resposta ou de erro.
+// A Azure OpenAI informa {"hate": {"filtered": true, "severity": "high"}, ...} em content_filter_result(s),
+// dentro de error.innererror ou de cada choice.
+func FilteredCategories(body []byte) []string {
+	var payload struct {
+		Error struct {
+			InnerError struct {
+				Result map[string]filterResult `json:"content_filter_result"`
+			} `json:"innererror"`
+		} `json:"error"`
+		Choices []struct {
+			Results map[string]filterResult `json:"content_filter_results"`
+		} `json:"choices"`
+	}
+	if json.Unmarshal(body, &payload) != nil {
+		return nil
+	}
+	results := []map[string]filterResult{payload.Error.InnerError.Result}
+	for _, choice := range payload.Choices {
+		results = append(results, choice.Results)
+	}
+	return filteredNames(results...)
+}
+
+// filterResult é o resultado do filtro de conteúdo para uma categoria
+type filterResult struct {
+	Filtered bool   `json:"filtered"`
+	Severity string `json:"severity"`
+}
+
+// filteredNames retorna, em ordem alfabética, as categorias filtradas com a severidade, como "violence (high)"
+func filteredNames(results ...map[string]filterResult) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, result := range results {
+		for name, r := range result {
+			if !r.Filtered || seen[name] {
+				continue
+			}
+			seen[name] = true
+			if r.Severity != "" && r.Severity != "safe" {
+				name += " (" + r.Severity + ")"
+			}
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WrapTransportError envolve um erro de rede em um ProviderError, classificando timeouts como ErrTimeout
+func WrapTransportError(provider string, err error) error {
+	if err == nil {
+		return nil
+	}
+	e := &ProviderError{Provider: provider, Err: err}
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		e.Kind = ErrTimeout
+	}
+	return e
+}
+
+// IsRetryable indica se a falha é transitória (limite de requisições, timeout ou erro temporário de rede)
+func IsRetryable(err error) bool {
+	if errors.Is(err, ErrRateLimited) || errors.Is(err, ErrTimeout) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// RetryDelay retorna quanto esperar antes de uma nova tentativa: o Retry-After informado pelo provedor,
+// se houver, ou o backoff atual (com um mínimo para respostas de limite de requisições)
+func RetryDelay(err error, backoff time.Duration) time.Duration {
+	var providerErr *ProviderError
+	if errors.As(err, &providerErr) && providerErr.RetryAfter > 0 {
+		return providerErr.RetryAfter
+	}
+	if errors.Is(err, ErrRateLimited) && backoff < minRateLimitDelay {
+		return minRateLimitDelay
+	}
+	return backoff
+}
+
+// parseRetryAfter interpreta o cabeçalho Retry-After, em segundos ou como data HTTP
+func parseRetryAfter(header http.Header) time.Duration {
+	value := strings.TrimSpace(header.Get("Retry-After"))
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := time.Until(date); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+func containsAny(s string, substrs []string) bool {
+	for _, sub := range substrs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
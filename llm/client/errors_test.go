@@ -0,0 +1,125 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/diillson/chatcli/utils"
+)
+
+func TestNewHTTPError(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		status   int
+		header   http.Header
+		body     string
+		expected error
+	}{
+		{"OpenAI 401", "OpenAI", 401, nil, `{"error":{"message":"Incorrect API key"}}`, ErrAuth},
+		{"OpenAI 429", "OpenAI", 429, http.Header{"Retry-After": {"7"}}, `{"error":{"type":"rate_limit"}}`, ErrRateLimited},
+		{"OpenAI contexto", "OpenAI", 400, nil, `{"error":{"code":"context_length_exceeded"}}`, ErrContextTooLong},
+		{"ClaudeAI 403", "ClaudeAI", 403, nil, `{"type":"error"}`, ErrAuth},
+		{"ClaudeAI contexto", "ClaudeAI", 400, nil, `{"error":{"message":"prompt is too long: 210000 tokens"}}`, ErrContextTooLong},
+		{"ClaudeAI 413", "ClaudeAI", 413, nil, `{"type":"request_too_large"}`, ErrContextTooLong},
+		{"StackSpot 401", "StackSpotAI", 401, nil, `unauthorized`, ErrAuth},
+		{"StackSpot limite", "StackSpotAI", 400, nil, `{"code":"RATE_LIMIT_EXCEEDED"}`, ErrRateLimited},
+		{"StackSpot 504", "StackSpotAI", 504, nil, `gateway timeout`, ErrTimeout},
+		{"OpenAI política", "OpenAI", 400, nil, `{"error":{"code":"content_policy_violation"}}`, ErrContentFiltered},
+		{"Azure filtro", "OpenAI", 400, nil, `{"error":{"code":"content_filter","innererror":{"code":"ResponsibleAIPolicyViolation"}}}`, ErrContentFiltered},
+		{"Erro genérico", "OpenAI", 500, nil, `internal error`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewHTTPError(tt.provider, tt.status, tt.header, []byte(tt.body))
+			if err.Kind != tt.expected {
+				t.Errorf("Esperado %v, obtido %v", tt.expected, err.Kind)
+			}
+			if tt.expected != nil && !errors.Is(fmt.Errorf("contexto: %w", err), tt.expected) {
+				t.Errorf("errors.Is deveria identificar %v através de wrapping", tt.expected)
+			}
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	err := fmt.Errorf("falha: %w", NewHTTPError("OpenAI", 429, http.Header{"Retry-After": {"7"}}, nil))
+	var providerErr *ProviderError
+	if !errors.As(err, &providerErr) || providerErr.RetryAfter != 7*time.Second {
+		t.Fatalf("Esperado RetryAfter de 7s")
+	}
+	if d := RetryDelay(err, time.Millisecond); d != 7*time.Second {
+		t.Errorf("Esperado 7s, obtido %v", d)
+	}
+
+	withoutHeader := NewHTTPError("OpenAI", 429, nil, nil)
+	if d := RetryDelay(withoutHeader, time.Millisecond); d != minRateLimitDelay {
+		t.Errorf("Esperado espera mínima de %v, obtido %v", minRateLimitDelay, d)
+	}
+	if !IsRetryable(withoutHeader) || IsRetryable(NewHTTPError("OpenAI", 401, nil, nil)) {
+		t.Error("Classificação de retry inesperada")
+	}
+}
+
+func TestWrapTransportError(t *testing.T) {
+	err := WrapTransportError("ClaudeAI", context.DeadlineExceeded)
+	if !errors.Is(err, ErrTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Esperado ErrTimeout preservando o erro original, obtido %v", err)
+	}
+	if errors.Is(WrapTransportError("ClaudeAI", errors.New("conexão recusada")), ErrTimeout) {
+		t.Error("Erro de conexão não deveria ser classificado como timeout")
+	}
+}
+
+func TestWrapTransportErrorIdleTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/corpo" {
+			w.Write([]byte("início"))
+			w.(http.Flusher).Flush()
+		}
+		time.Sleep(300 * time.Millisecond)
+	}))
+	defer server.Close()
+	httpClient := &http.Client{Transport: utils.NewIdleTimeoutTransport(http.DefaultTransport, 100*time.Millisecond)}
+
+	// Sem resposta: a inatividade aborta a requisição antes dos cabeçalhos
+	_, err := httpClient.Get(server.URL + "/cabecalhos")
+	if err = WrapTransportError("OpenAI", err); !errors.Is(err, ErrTimeout) || !IsRetryable(err) {
+		t.Errorf("Esperado ErrTimeout repetível ao esperar a resposta, obtido %v", err)
+	}
+
+	// Corpo interrompido: a inatividade aborta a leitura
+	resp, err := httpClient.Get(server.URL + "/corpo")
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	defer resp.Body.Close()
+	_, err = io.ReadAll(resp.Body)
+	if err = WrapTransportError("OpenAI", fmt.Errorf("erro ao ler a resposta: %w", err)); !errors.Is(err, ErrTimeout) || !IsRetryable(err) {
+		t.Errorf("Esperado ErrTimeout repetível ao ler o corpo, obtido %v", err)
+	}
+}
+
+func TestFilteredCategories(t *testing.T) {
+	errorBody := `{"error":{"code":"content_filter","innererror":{"content_filter_result":{
+		"hate":{"filtered":false,"severity":"safe"},"violence":{"filtered":true,"severity":"high"}}}}}`
+	if got := FilteredCategories([]byte(errorBody)); len(got) != 1 || got[0] != "violence (high)" {
+		t.Errorf("Categorias inesperadas no erro: %q", got)
+	}
+
+	responseBody := `{"choices":[{"finish_reason":"content_filter","content_filter_results":{
+		"self_harm":{"filtered":true,"severity":"medium"},"jailbreak":{"filtered":true,"detected":true}}}]}`
+	if got := FilteredCategories([]byte(responseBody)); len(got) != 2 || got[0] != "jailbreak" || got[1] != "self_harm (medium)" {
+		t.Errorf("Categorias inesperadas na resposta: %q", got)
+	}
+	if got := FilteredCategories([]byte(`texto`)); got != nil {
+		t.Errorf("Esperado nenhuma categoria, obtido %q", got)
+	}
+}
@@ -0,0 +1,105 @@
+package client
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// KeyPool distribui as requisições entre várias chaves de API de um mesmo provedor, em rodízio.
+// Uma chave que recebe um limite de requisições fica em espera pelo tempo informado e é pulada
+// até que a espera termine. Com uma única chave, Next sempre a retorna.
+type KeyPool struct {
+	mu        sync.Mutex
+	keys      []string
+	next      int
+	cooldowns map[int]time.Time
+	now       func() time.Time
+}
+
+// NewKeyPool cria o rodízio com as chaves informadas, ignorando as vazias e as repetidas
+func NewKeyPool(keys []string) *KeyPool {
+	pool := &KeyPool{cooldowns: make(map[int]time.Time), now: time.Now}
+	seen := make(map[string]bool)
+	for _, key := range keys {
+		if key = strings.TrimSpace(key); key != "" && !seen[key] {
+			seen[key] = true
+			pool.keys = append(pool.keys, key)
+		}
+	}
+	return pool
+}
+
+// ParseKeys separa uma lista de chaves separadas por vírgula
+func ParseKeys(value string) []string {
+	var keys []string
+	for _, key := range strings.Split(value, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// Len retorna a quantidade de chaves do rodízio
+func (p *KeyPool) Len() int {
+	return len(p.keys)
+}
+
+// Key retorna a chave de índice i, sem afetar o rodízio
+func (p *KeyPool) Key(i int) string {
+	return p.keys[i]
+}
+
+// Next retorna a próxima chave fora de espera, com seu índice (usado nos logs no lugar da chave).
+// Se todas estiverem em espera, retorna a que ficará disponível primeiro.
+func (p *KeyPool) Next() (int, string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.keys) == 0 {
+		return 0, ""
+	}
+
+	now := p.now()
+	earliest := -1
+	for i := 0; i < len(p.keys); i++ {
+		idx := (p.next + i) % len(p.keys)
+		until, cooling := p.cooldowns[idx]
+		if !cooling || !now.Before(until) {
+			delete(p.cooldowns, idx)
+			p.next = idx + 1
+			return idx, p.keys[idx]
+		}
+		if earliest == -1 || until.Before(p.cooldowns[earliest]) {
+			earliest = idx
+		}
+	}
+	p.next = earliest + 1
+	return earliest, p.keys[earliest]
+}
+
+// Cooldown coloca a chave de índice idx em espera pela duração informada
+func (p *KeyPool) Cooldown(idx int, d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cooldowns[idx] = p.now().Add(d)
+}
+
+// Wait retorna quanto falta para alguma chave sair da espera, ou zero se alguma já estiver disponível
+func (p *KeyPool) Wait() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	var wait time.Duration
+	for i := range p.keys {
+		until, cooling := p.cooldowns[i]
+		if !cooling || !now.Before(until) {
+			return 0
+		}
+		if remaining := until.Sub(now); wait == 0 || remaining < wait {
+			wait = remaining
+		}
+	}
+	return wait
+}
@@ -0,0 +1,58 @@
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+func TestKeyPool(t *testing.T) {
+	now := time.Unix(0, 0)
+	pool := NewKeyPool([]string{"a", " b ", "", "a", "c"})
+	pool.now = func() time.Time { return now }
+
+	if pool.Len() != 3 {
+		t.Fatalf("Esperadas 3 chaves, obtidas %d", pool.Len())
+	}
+	var order []string
+	for i := 0; i < 4; i++ {
+		_, key := pool.Next()
+		order = append(order, key)
+	}
+	if got := order[0] + order[1] + order[2] + order[3]; got != "abca" {
+		t.Errorf("Rodízio inesperado: %s", got)
+	}
+
+	// "b" em espera é pulada até a espera terminar
+	pool.Cooldown(1, 10*time.Second)
+	if _, key := pool.Next(); key != "c" {
+		t.Errorf("Esperada c, obtida %s", key)
+	}
+	if _, key := pool.Next(); key != "a" {
+		t.Errorf("Esperada a, obtida %s", key)
+	}
+	if pool.Wait() != 0 {
+		t.Errorf("Esperado Wait zero com chaves disponíveis, obtido %v", pool.Wait())
+	}
+
+	// Todas em espera: Wait retorna a menor espera e Next a chave que sai primeiro
+	pool.Cooldown(0, 5*time.Second)
+	pool.Cooldown(2, 20*time.Second)
+	if pool.Wait() != 5*time.Second {
+		t.Errorf("Esperado Wait de 5s, obtido %v", pool.Wait())
+	}
+	if _, key := pool.Next(); key != "a" {
+		t.Errorf("Esperada a, obtida %s", key)
+	}
+
+	now = now.Add(11 * time.Second)
+	if _, key := pool.Next(); key != "b" {
+		t.Errorf("Esperada b após a espera, obtida %s", key)
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	keys := ParseKeys(" sk-1, ,sk-2,")
+	if len(keys) != 2 || keys[0] != "sk-1" || keys[1] != "sk-2" {
+		t.Errorf("Chaves inesperadas: %v", keys)
+	}
+}
@@ -33,3 +33,31 @@ type LLMClient interface {
 	// Caso o cliente precise de configuração ou autenticação, esse método pode ser implementado.
 	// Initialize(config Config) error
 }
+
+// GenerationConfigurable é implementado pelos clientes que aceitam parâmetros de amostragem
+// (temperature, top_p, penalidades). Parâmetros sem equivalente no provedor são ignorados.
+type GenerationConfigurable interface {
+	SetGenerationParams(params models.GenerationParams)
+}
+
+// ImageCapable é implementado pelos clientes capazes de enviar imagens junto ao prompt.
+// SupportsImages indica se o modelo configurado aceita imagens (visão).
+type ImageCapable interface {
+	SupportsImages() bool
+	SendPromptWithImages(ctx context.Context, prompt string, images []models.Image, history []models.Message) (string, error)
+}
+
+// KeyChecker é implementado pelos clientes capazes de validar as credenciais com uma chamada mínima
+// autenticada, sem enviar um prompt. Falhas de autenticação são retornadas como ErrAuth.
+type KeyChecker interface {
+	CheckKey(ctx context.Context) error
+}
+
+// ResponseFormatJSON pede ao provedor que a resposta seja um objeto JSON
+const ResponseFormatJSON = "json"
+
+// ResponseFormatConfigurable é implementado pelos clientes cujo provedor tem um modo nativo de saída
+// estruturada. Um formato vazio volta à resposta em texto livre.
+type ResponseFormatConfigurable interface {
+	SetResponseFormat(format string)
+}
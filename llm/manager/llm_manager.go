@@ -2,18 +2,23 @@ package manager
 
 import (
 	"fmt"
+	"github.com/diillson/chatcli/config"
 	"github.com/diillson/chatcli/llm/claudeai"
 	"github.com/diillson/chatcli/llm/client"
+	"github.com/diillson/chatcli/llm/ollama"
 	"github.com/diillson/chatcli/llm/openai"
 	"github.com/diillson/chatcli/llm/stackspotai"
 	"github.com/diillson/chatcli/llm/token"
+	"github.com/diillson/chatcli/utils"
 	"go.uber.org/zap"
 	"os"
+	"strings"
 )
 
 const (
 	defaultOpenAIModel   = "gpt-4o-mini"
 	defaultClaudeAIModel = "claude-3-5-sonnet-20241022"
+	defaultOllamaModel   = "llama3.1"
 )
 
 // ConfigError representa um erro de configuração, como variáveis de ambiente ausentes
@@ -51,22 +56,34 @@ func NewLLMManager(logger *zap.Logger, slugName, tenantName string) (LLMManager,
 	manager.configurarOpenAIClient()
 	manager.configurarStackSpotClient(slugName, tenantName)
 	manager.configurarClaudeAIClient()
+	manager.configurarOllamaClient()
+	manager.configurarCustomProviders()
 
 	return manager, nil
 }
 
-// configurarOpenAIClient configura o cliente OpenAI se a variável de ambiente OPENAI_API_KEY estiver definida.
+// configurarOpenAIClient configura o cliente OpenAI se a variável de ambiente OPENAI_API_KEYS ou
+// OPENAI_API_KEY estiver definida. OPENAI_API_KEYS, com chaves separadas por vírgula, tem precedência
+// e faz o cliente usá-las em rodízio.
 func (m *LLMManagerImpl) configurarOpenAIClient() {
-	apiKey := os.Getenv("OPENAI_API_KEY")
-	if apiKey != "" {
+	apiKeys := client.ParseKeys(os.Getenv("OPENAI_API_KEYS"))
+	if len(apiKeys) == 0 && os.Getenv("OPENAI_API_KEY") != "" {
+		apiKeys = []string{os.Getenv("OPENAI_API_KEY")}
+	}
+	if len(apiKeys) > 0 {
+		baseURL, err := utils.ValidateBaseURL(os.Getenv("OPENAI_BASE_URL"))
+		if err != nil {
+			m.logger.Error("OPENAI_BASE_URL inválida, o provedor OPENAI não estará disponível", zap.Error(err))
+			return
+		}
 		m.clients["OPENAI"] = func(model string) (client.LLMClient, error) {
 			if model == "" {
 				model = defaultOpenAIModel
 			}
-			return openai.NewOpenAIClient(apiKey, model, m.logger, 50, 300), nil
+			return openai.NewOpenAIClientWithKeys(apiKeys, model, baseURL, m.logger, 50, 300), nil
 		}
 	} else {
-		m.logger.Warn("OPENAI_API_KEY não definida, o provedor OPENAI não estará disponível")
+		m.logger.Warn("OPENAI_API_KEY e OPENAI_API_KEYS não definidas, o provedor OPENAI não estará disponível")
 	}
 }
 
@@ -93,17 +110,102 @@ func (m *LLMManagerImpl) configurarStackSpotClient(slugName, tenantName string)
 func (m *LLMManagerImpl) configurarClaudeAIClient() {
 	apiKey := os.Getenv("CLAUDEAI_API_KEY")
 	if apiKey != "" {
+		baseURL, err := utils.ValidateBaseURL(os.Getenv("CLAUDEAI_BASE_URL"))
+		if err != nil {
+			m.logger.Error("CLAUDEAI_BASE_URL inválida, o provedor ClaudeAI não estará disponível", zap.Error(err))
+			return
+		}
 		m.clients["CLAUDEAI"] = func(model string) (client.LLMClient, error) {
 			if model == "" {
 				model = defaultClaudeAIModel
 			}
-			return claudeai.NewClaudeClient(apiKey, model, m.logger), nil
+			return claudeai.NewClaudeClient(apiKey, model, baseURL, m.logger), nil
 		}
 	} else {
 		m.logger.Warn("CLAUDEAI_API_KEY não definida, o provedor ClaudeAI não estará disponível")
 	}
 }
 
+// configurarOllamaClient configura o cliente Ollama quando OLLAMA_ENABLED=true ou quando há um servidor
+// respondendo em OLLAMA_HOST. OLLAMA_ENABLED=false desativa o provedor sem verificar o host.
+func (m *LLMManagerImpl) configurarOllamaClient() {
+	host, err := ollama.NormalizeHost(utils.GetEnvOrDefault("OLLAMA_HOST", ollama.DefaultHost))
+	if err != nil {
+		m.logger.Error("OLLAMA_HOST inválido, o provedor OLLAMA não estará disponível", zap.Error(err))
+		return
+	}
+
+	switch strings.ToLower(os.Getenv("OLLAMA_ENABLED")) {
+	case "true":
+	case "false":
+		return
+	default:
+		if !ollama.IsReachable(host) {
+			m.logger.Debug("Servidor Ollama não encontrado, o provedor OLLAMA não estará disponível", zap.String("host", host))
+			return
+		}
+	}
+
+	m.clients["OLLAMA"] = func(model string) (client.LLMClient, error) {
+		if model == "" {
+			model = defaultOllamaModel
+		}
+		return ollama.NewOllamaClient(host, model, m.logger), nil
+	}
+}
+
+// CustomProviderRegistry é implementado pelos managers que aceitam provedores compatíveis com a OpenAI
+// registrados em tempo de execução, como os de /providers add
+type CustomProviderRegistry interface {
+	RegisterCustomProvider(p config.CustomProvider) error
+	UnregisterCustomProvider(name string)
+}
+
+// configurarCustomProviders registra os provedores compatíveis com a OpenAI de CHATCLI_PROVIDERS_FILE
+func (m *LLMManagerImpl) configurarCustomProviders() {
+	path, err := config.ProvidersPath()
+	if err != nil {
+		m.logger.Error("Caminho inválido em CHATCLI_PROVIDERS_FILE", zap.Error(err))
+		return
+	}
+	providers, err := config.LoadCustomProviders(path)
+	if err != nil {
+		m.logger.Error("Os provedores registrados não estarão disponíveis", zap.Error(err))
+		return
+	}
+	for _, p := range providers {
+		if err := m.RegisterCustomProvider(p); err != nil {
+			m.logger.Warn("Provedor registrado indisponível", zap.String("provider", p.Name), zap.Error(err))
+		}
+	}
+}
+
+// RegisterCustomProvider disponibiliza o provedor usando o cliente da OpenAI com a URL base dele. A
+// variável da chave, quando informada, precisa estar definida.
+func (m *LLMManagerImpl) RegisterCustomProvider(p config.CustomProvider) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	var apiKey string
+	if p.APIKeyEnv != "" {
+		if apiKey = os.Getenv(p.APIKeyEnv); apiKey == "" {
+			return fmt.Errorf("%s não definida, o provedor %s não estará disponível", p.APIKeyEnv, p.Name)
+		}
+	}
+	m.clients[p.Name] = func(model string) (client.LLMClient, error) {
+		if model == "" {
+			model = p.Model
+		}
+		return openai.NewOpenAIClient(apiKey, model, p.BaseURL, m.logger, 50, 300), nil
+	}
+	return nil
+}
+
+// UnregisterCustomProvider remove um provedor registrado com RegisterCustomProvider
+func (m *LLMManagerImpl) UnregisterCustomProvider(name string) {
+	delete(m.clients, strings.ToUpper(name))
+}
+
 // GetAvailableProviders retorna uma lista de provedores disponíveis configurados
 func (m *LLMManagerImpl) GetAvailableProviders() []string {
 	var providers []string
@@ -2,8 +2,10 @@ package manager
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/diillson/chatcli/config"
 	"go.uber.org/zap"
 )
 
@@ -15,6 +17,8 @@ func TestNewLLMManager(t *testing.T) {
 	os.Setenv("CLIENT_ID", "test-client-id")
 	os.Setenv("CLIENT_SECRET", "test-client-secret")
 	os.Setenv("CLAUDEAI_API_KEY", "test-claudeai-key")
+	t.Setenv("OLLAMA_ENABLED", "false")
+	t.Setenv("CHATCLI_PROVIDERS_FILE", filepath.Join(t.TempDir(), "providers.json"))
 
 	manager, err := NewLLMManager(logger, "slug", "tenant")
 	if err != nil {
@@ -26,3 +30,47 @@ func TestNewLLMManager(t *testing.T) {
 		t.Errorf("Esperado 3 provedores, obtido %d", len(providers))
 	}
 }
+
+func TestCustomProviders(t *testing.T) {
+	logger := zap.NewNop()
+	path := filepath.Join(t.TempDir(), "providers.json")
+	t.Setenv("CHATCLI_PROVIDERS_FILE", path)
+	t.Setenv("OLLAMA_ENABLED", "false")
+	t.Setenv("GROQ_API_KEY", "chave")
+	t.Setenv("TOGETHER_API_KEY", "")
+	providers := []config.CustomProvider{
+		{Name: "groq", BaseURL: "https://api.groq.com/openai/v1", APIKeyEnv: "GROQ_API_KEY", Model: "llama-3.1-8b-instant"},
+		{Name: "TOGETHER", BaseURL: "https://api.together.xyz/v1", APIKeyEnv: "TOGETHER_API_KEY", Model: "x"},
+	}
+	if err := config.SaveCustomProviders(path, providers); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := NewLLMManager(logger, "slug", "tenant")
+	if err != nil {
+		t.Fatal(err)
+	}
+	llmClient, err := m.GetClient("GROQ", "")
+	if err != nil {
+		t.Fatalf("GROQ deveria estar disponível: %v", err)
+	}
+	if llmClient.GetModelName() != "llama-3.1-8b-instant" {
+		t.Errorf("Modelo padrão inesperado: %s", llmClient.GetModelName())
+	}
+	// Sem a chave definida, o provedor não é registrado
+	if _, err := m.GetClient("TOGETHER", ""); err == nil {
+		t.Error("TOGETHER não deveria estar disponível sem a chave")
+	}
+
+	registry := m.(CustomProviderRegistry)
+	if err := registry.RegisterCustomProvider(config.CustomProvider{Name: "vllm", BaseURL: "http://localhost:8000/v1", Model: "qwen"}); err != nil {
+		t.Fatalf("Provedor sem chave deveria ser aceito: %v", err)
+	}
+	if _, err := m.GetClient("VLLM", ""); err != nil {
+		t.Errorf("VLLM deveria estar disponível: %v", err)
+	}
+	registry.UnregisterCustomProvider("vllm")
+	if _, err := m.GetClient("VLLM", ""); err == nil {
+		t.Error("VLLM deveria ter sido removido")
+	}
+}
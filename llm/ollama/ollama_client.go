@@ -0,0 +1,262 @@
+package ollama
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/diillson/chatcli/llm/client"
+	"github.com/diillson/chatcli/models"
+	"github.com/diillson/chatcli/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultHost é o endereço padrão do servidor Ollama local
+	DefaultHost     = "http://localhost:11434"
+	defaultPort     = "11434"
+	ollamaChatPath  = "/api/chat"
+	ollamaTagsPath  = "/api/tags"
+	ollamaProbeWait = 500 * time.Millisecond
+)
+
+// modelsWithoutSystemRole lista prefixos de modelos cujo template não aceita mensagens de sistema.
+// Para eles, as instruções de sistema são enviadas junto da primeira mensagem do usuário.
+var modelsWithoutSystemRole = []string{"gemma"}
+
+// OllamaClient implementa o cliente para um servidor Ollama local
+type OllamaClient struct {
+	host   string
+	model  string
+	logger *zap.Logger
+	client *http.Client
+	params models.GenerationParams
+	format string
+}
+
+// chatMessage é o formato de mensagem esperado pela API /api/chat
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// chatChunk é cada linha da resposta em streaming de /api/chat
+type chatChunk struct {
+	Message chatMessage `json:"message"`
+	Done    bool        `json:"done"`
+	Error   string      `json:"error"`
+}
+
+// NewOllamaClient cria uma nova instância de OllamaClient. Host vazio usa DefaultHost.
+func NewOllamaClient(host, model string, logger *zap.Logger) *OllamaClient {
+	if host == "" {
+		host = DefaultHost
+	}
+	return &OllamaClient{
+		host:   strings.TrimSuffix(host, "/"),
+		model:  model,
+		logger: logger,
+		client: utils.NewProviderHTTPClient(logger),
+	}
+}
+
+// NormalizeHost aceita OLLAMA_HOST também no formato do próprio Ollama, sem esquema (como 127.0.0.1:11434,
+// 0.0.0.0:11434 ou localhost), completando http:// e, se faltar, a porta padrão, e valida a URL resultante
+func NormalizeHost(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw != "" && !strings.Contains(raw, "://") {
+		hostport, path, _ := strings.Cut(raw, "/")
+		if _, _, err := net.SplitHostPort(hostport); err != nil {
+			hostport = net.JoinHostPort(strings.Trim(hostport, "[]"), defaultPort)
+		}
+		raw = "http://" + hostport
+		if path != "" {
+			raw += "/" + path
+		}
+	}
+	return utils.ValidateBaseURL(raw)
+}
+
+// IsReachable verifica rapidamente se há um servidor Ollama respondendo no host informado
+func IsReachable(host string) bool {
+	if host == "" {
+		host = DefaultHost
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), ollamaProbeWait)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(host, "/")+ollamaTagsPath, nil)
+	if err != nil {
+		return false
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
+}
+
+// GetModelName retorna o nome do modelo utilizado pelo cliente
+func (c *OllamaClient) GetModelName() string {
+	return c.model
+}
+
+// SetGenerationParams define os parâmetros de amostragem enviados em cada requisição.
+// O Ollama aceita todos os parâmetros de models.GenerationParams no campo "options" (max_tokens
+// como num_predict, sem limite conhecido por modelo).
+func (c *OllamaClient) SetGenerationParams(params models.GenerationParams) {
+	c.params = params
+}
+
+// SetResponseFormat ativa o modo JSON do Ollama (campo "format")
+func (c *OllamaClient) SetResponseFormat(format string) {
+	c.format = format
+}
+
+// SendPrompt envia o prompt com o histórico para /api/chat e acumula a resposta recebida em streaming
+func (c *OllamaClient) SendPrompt(ctx context.Context, prompt string, history []models.Message) (string, error) {
+	payload := map[string]interface{}{
+		"model":    c.model,
+		"messages": c.buildMessages(prompt, history),
+		"stream":   true,
+	}
+	if options := c.options(); len(options) > 0 {
+		payload["options"] = options
+	}
+	if c.format == client.ResponseFormatJSON {
+		payload["format"] = "json"
+	}
+
+	jsonValue, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("erro ao preparar a requisição: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.host+ollamaChatPath, utils.NewJSONReader(jsonValue))
+	if err != nil {
+		c.logger.Error("Erro ao criar a requisição", zap.Error(err))
+		return "", fmt.Errorf("erro ao criar a requisição: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		c.logger.Error("Erro ao fazer a requisição para o Ollama", zap.Error(err))
+		return "", client.WrapTransportError("Ollama", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		c.logger.Error("Resposta de erro do Ollama", zap.Int("status", resp.StatusCode), zap.String("body", string(body)))
+		return "", client.NewHTTPError("Ollama", resp.StatusCode, resp.Header, body)
+	}
+
+	return c.readStream(resp.Body)
+}
+
+// readStream lê as linhas JSON enviadas pelo Ollama até receber done=true
+func (c *OllamaClient) readStream(body io.Reader) (string, error) {
+	var response strings.Builder
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var chunk chatChunk
+		if err := json.Unmarshal([]byte(line), &chunk); err != nil {
+			c.logger.Error("Erro ao decodificar a resposta do Ollama", zap.Error(err))
+			return "", fmt.Errorf("erro ao decodificar a resposta do Ollama: %w", err)
+		}
+		if chunk.Error != "" {
+			return "", fmt.Errorf("erro retornado pelo Ollama: %s", chunk.Error)
+		}
+		response.WriteString(chunk.Message.Content)
+		if chunk.Done {
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", client.WrapTransportError("Ollama", err)
+	}
+
+	if response.Len() == 0 {
+		return "", fmt.Errorf("nenhuma resposta recebida do Ollama")
+	}
+	return response.String(), nil
+}
+
+// buildMessages converte o histórico para o formato do Ollama. Para modelos sem papel de sistema,
+// as mensagens de sistema são agrupadas e prefixadas à primeira mensagem do usuário.
+func (c *OllamaClient) buildMessages(prompt string, history []models.Message) []chatMessage {
+	all := append(append([]models.Message{}, history...), models.Message{Role: "user", Content: prompt})
+
+	if c.supportsSystemRole() {
+		messages := make([]chatMessage, len(all))
+		for i, msg := range all {
+			messages[i] = chatMessage{Role: msg.Role, Content: msg.Content}
+		}
+		return messages
+	}
+
+	var system []string
+	messages := make([]chatMessage, 0, len(all))
+	for _, msg := range all {
+		if msg.Role == "system" {
+			system = append(system, msg.Content)
+			continue
+		}
+		messages = append(messages, chatMessage{Role: msg.Role, Content: msg.Content})
+	}
+	if len(system) > 0 {
+		for i := range messages {
+			if messages[i].Role == "user" {
+				messages[i].Content = strings.Join(system, "\n\n") + "\n\n" + messages[i].Content
+				break
+			}
+		}
+	}
+	return messages
+}
+
+// supportsSystemRole indica se o modelo configurado aceita mensagens com papel de sistema
+func (c *OllamaClient) supportsSystemRole() bool {
+	model := strings.ToLower(c.model)
+	for _, prefix := range modelsWithoutSystemRole {
+		if strings.HasPrefix(model, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// options converte os parâmetros de geração para o campo "options" do Ollama
+func (c *OllamaClient) options() map[string]interface{} {
+	options := map[string]interface{}{}
+	if c.params.Temperature != nil {
+		options["temperature"] = *c.params.Temperature
+	}
+	if c.params.TopP != nil {
+		options["top_p"] = *c.params.TopP
+	}
+	if c.params.PresencePenalty != nil {
+		options["presence_penalty"] = *c.params.PresencePenalty
+	}
+	if c.params.FrequencyPenalty != nil {
+		options["frequency_penalty"] = *c.params.FrequencyPenalty
+	}
+	if c.params.MaxTokens != nil {
+		options["num_predict"] = *c.params.MaxTokens
+	}
+	return options
+}
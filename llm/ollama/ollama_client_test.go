@@ -0,0 +1,95 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/diillson/chatcli/models"
+	"go.uber.org/zap"
+)
+
+func TestOllamaClient_SendPrompt(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			t.Errorf("Caminho inesperado: %s", r.URL.Path)
+		}
+		var payload struct {
+			Model    string        `json:"model"`
+			Messages []chatMessage `json:"messages"`
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+		if payload.Model != "llama3.1" || len(payload.Messages) != 2 {
+			t.Errorf("Payload inesperado: %+v", payload)
+		}
+		w.Write([]byte(`{"message":{"role":"assistant","content":"Olá"},"done":false}` + "\n"))
+		w.Write([]byte(`{"message":{"role":"assistant","content":", mundo"},"done":false}` + "\n"))
+		w.Write([]byte(`{"message":{"role":"assistant","content":""},"done":true}` + "\n"))
+	}))
+	defer server.Close()
+
+	c := NewOllamaClient(server.URL, "llama3.1", zap.NewNop())
+	history := []models.Message{{Role: "system", Content: "Seja breve"}}
+	response, err := c.SendPrompt(context.Background(), "oi", history)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if response != "Olá, mundo" {
+		t.Errorf("Resposta inesperada: %q", response)
+	}
+}
+
+func TestOllamaClient_buildMessagesWithoutSystemRole(t *testing.T) {
+	c := NewOllamaClient("", "gemma2:9b", zap.NewNop())
+	history := []models.Message{
+		{Role: "system", Content: "Responda em português"},
+		{Role: "user", Content: "primeira"},
+		{Role: "assistant", Content: "ok"},
+	}
+
+	messages := c.buildMessages("segunda", history)
+	if len(messages) != 3 {
+		t.Fatalf("Esperado 3 mensagens sem a de sistema, obtido %d", len(messages))
+	}
+	if messages[0].Role != "user" || !strings.HasPrefix(messages[0].Content, "Responda em português") {
+		t.Errorf("Instruções de sistema deveriam prefixar a primeira mensagem do usuário: %+v", messages[0])
+	}
+}
+
+func TestIsReachable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"models":[]}`))
+	}))
+	if !IsReachable(server.URL) {
+		t.Error("Esperado servidor acessível")
+	}
+	server.Close()
+	if IsReachable(server.URL) {
+		t.Error("Esperado servidor inacessível após fechar")
+	}
+}
+
+func TestNormalizeHost(t *testing.T) {
+	cases := map[string]string{
+		"0.0.0.0:11434":             "http://0.0.0.0:11434",
+		"127.0.0.1:11434":           "http://127.0.0.1:11434",
+		"localhost:11434":           "http://localhost:11434",
+		"localhost":                 "http://localhost:11434",
+		"[::1]:8080":                "http://[::1]:8080",
+		"ollama.interno:80/prefixo": "http://ollama.interno:80/prefixo",
+		"http://localhost:11434/":   "http://localhost:11434",
+		"https://ollama.exemplo":    "https://ollama.exemplo",
+	}
+	for raw, want := range cases {
+		got, err := NormalizeHost(raw)
+		if err != nil || got != want {
+			t.Errorf("NormalizeHost(%q) = %q, %v; esperado %q", raw, got, err, want)
+		}
+	}
+	if _, err := NormalizeHost("ftp://localhost:11434"); err == nil {
+		t.Error("Esperado erro para esquema inválido")
+	}
+}
@@ -2,46 +2,71 @@ package openai
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/diillson/chatcli/llm/client"
 	"github.com/diillson/chatcli/models"
 	"github.com/diillson/chatcli/utils"
 	"go.uber.org/zap"
 )
 
+// DefaultBaseURL é a URL da API da OpenAI, usada quando OPENAI_BASE_URL não está definida
+const DefaultBaseURL = "https://api.openai.com/v1"
+
 const (
-	openAIAPIURL             = "https://api.openai.com/v1/chat/completions"
+	openAIChatCompletionPath = "/chat/completions"
+	openAIModelsPath         = "/models"
 	openAIDefaultMaxAttempts = 3
 	openAIDefaultBackoff     = time.Second
 )
 
+// openAIVisionModelPrefixes lista os prefixos dos modelos da OpenAI que aceitam imagens
+var openAIVisionModelPrefixes = []string{"gpt-4o", "gpt-4.1", "gpt-4-turbo", "gpt-4-vision", "gpt-5", "chatgpt-4o", "o1", "o3", "o4"}
+
 // OpenAIClient implementa o cliente para interagir com a API da OpenAI
 type OpenAIClient struct {
-	apiKey      string
+	keys        *client.KeyPool
 	model       string
+	apiURL      string
 	logger      *zap.Logger
 	client      *http.Client
 	maxAttempts int
 	backoff     time.Duration
+	params      models.GenerationParams
+	format      string
+}
+
+// NewOpenAIClient cria uma nova instância de OpenAIClient. O baseURL permite apontar para
+// gateways compatíveis com a API da OpenAI; vazio usa o endpoint oficial.
+func NewOpenAIClient(apiKey, model, baseURL string, logger *zap.Logger, maxAttempts int, backoff time.Duration) *OpenAIClient {
+	return NewOpenAIClientWithKeys([]string{apiKey}, model, baseURL, logger, maxAttempts, backoff)
 }
 
-// NewOpenAIClient cria uma nova instância de OpenAIClient.
-func NewOpenAIClient(apiKey, model string, logger *zap.Logger, maxAttempts int, backoff time.Duration) *OpenAIClient {
-	httpClient := utils.NewHTTPClient(logger, 300*time.Second)
+// NewOpenAIClientWithKeys cria um OpenAIClient que usa as chaves em rodízio. Quando uma chave
+// recebe 429, ela fica em espera pelo Retry-After e a próxima é tentada antes do backoff.
+func NewOpenAIClientWithKeys(apiKeys []string, model, baseURL string, logger *zap.Logger, maxAttempts int, backoff time.Duration) *OpenAIClient {
+	httpClient := utils.NewProviderHTTPClient(logger)
 	if maxAttempts <= 0 {
 		maxAttempts = openAIDefaultMaxAttempts
 	}
 	if backoff <= 0 {
 		backoff = openAIDefaultBackoff
 	}
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
 
 	return &OpenAIClient{
-		apiKey:      apiKey,
+		keys:        client.NewKeyPool(apiKeys),
 		model:       model,
+		apiURL:      strings.TrimSuffix(baseURL, "/") + openAIChatCompletionPath,
 		logger:      logger,
 		client:      httpClient,
 		maxAttempts: maxAttempts,
@@ -54,29 +79,66 @@ func (c *OpenAIClient) GetModelName() string {
 	return c.model
 }
 
+// SetGenerationParams define os parâmetros de amostragem enviados em cada requisição.
+// A OpenAI aceita todos os parâmetros de models.GenerationParams.
+func (c *OpenAIClient) SetGenerationParams(params models.GenerationParams) {
+	c.params = params
+}
+
+// SetResponseFormat ativa o modo JSON da OpenAI (response_format json_object). A API exige que as
+// mensagens mencionem JSON, o que fica a cargo de quem envia o prompt.
+func (c *OpenAIClient) SetResponseFormat(format string) {
+	c.format = format
+}
+
 // SendPrompt envia um prompt para o modelo de linguagem e retorna a resposta.
 func (c *OpenAIClient) SendPrompt(ctx context.Context, prompt string, history []models.Message) (string, error) {
+	return c.SendPromptWithImages(ctx, prompt, nil, history)
+}
+
+// SupportsImages indica se o modelo configurado aceita imagens
+func (c *OpenAIClient) SupportsImages() bool {
+	model := strings.ToLower(c.model)
+	// o1-mini e o3-mini são modelos apenas de texto
+	if strings.HasPrefix(model, "o1-mini") || strings.HasPrefix(model, "o3-mini") {
+		return false
+	}
+	for _, prefix := range openAIVisionModelPrefixes {
+		if strings.HasPrefix(model, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// SendPromptWithImages envia o prompt com as imagens anexadas à mensagem do usuário. Sem imagens,
+// a requisição é idêntica à de SendPrompt.
+func (c *OpenAIClient) SendPromptWithImages(ctx context.Context, prompt string, images []models.Image, history []models.Message) (string, error) {
 	// Construir o array de mensagens
-	messages := []map[string]string{}
+	messages := []map[string]interface{}{}
 
 	// Adicionar o histórico
 	for _, msg := range history {
-		messages = append(messages, map[string]string{
+		messages = append(messages, map[string]interface{}{
 			"role":    msg.Role,
 			"content": msg.Content,
 		})
 	}
 
 	// Adicionar a nova mensagem do usuário
-	messages = append(messages, map[string]string{
+	messages = append(messages, map[string]interface{}{
 		"role":    "user",
-		"content": prompt,
+		"content": buildUserContent(prompt, images),
 	})
 
 	payload := map[string]interface{}{
 		"model":    c.model,
 		"messages": messages,
 	}
+	c.applyGenerationParams(payload)
+	if c.format == client.ResponseFormatJSON {
+		payload["response_format"] = map[string]string{"type": "json_object"}
+	}
 
 	jsonValue, err := json.Marshal(payload)
 	if err != nil {
@@ -87,49 +149,160 @@ func (c *OpenAIClient) SendPrompt(ctx context.Context, prompt string, history []
 	var backoff = c.backoff
 
 	for attempt := 1; attempt <= c.maxAttempts; attempt++ {
-		resp, err := c.sendRequest(ctx, jsonValue)
-		if err != nil {
-			if utils.IsTemporaryError(err) {
-				c.logger.Warn("Erro temporário ao chamar OpenAI",
-					zap.Int("attempt", attempt),
-					zap.Error(err),
-					zap.Duration("backoff", backoff),
+		keyIndex, apiKey := c.keys.Next()
+		resp, err := c.sendRequest(ctx, jsonValue, apiKey)
+		if err == nil {
+			var response string
+			response, err = c.processResponse(resp)
+			if err == nil {
+				return response, nil
+			}
+		}
+
+		// Com várias chaves, a que atingiu o limite fica em espera e outra disponível é tentada
+		// imediatamente, sem consumir uma tentativa
+		if errors.Is(err, client.ErrRateLimited) && c.keys.Len() > 1 {
+			c.keys.Cooldown(keyIndex, client.RetryDelay(err, backoff))
+			if c.keys.Wait() == 0 {
+				c.logger.Warn("Limite de requisições atingido; tentando a próxima chave da OpenAI",
+					zap.Int("chave", keyIndex+1),
+					zap.Int("chaves", c.keys.Len()),
 				)
-				if attempt < c.maxAttempts {
-					time.Sleep(backoff)
-					backoff *= 2 // Backoff exponencial
-					continue
-				}
+				attempt--
+				continue
 			}
-			c.logger.Error("Erro ao fazer a requisição para OpenAI", zap.Error(err))
-			return "", fmt.Errorf("erro ao fazer a requisição para OpenAI: %w", err)
 		}
 
-		response, err := c.processResponse(resp)
-		if err != nil {
-			c.logger.Error("Erro ao processar a resposta da OpenAI", zap.Error(err))
-			return "", err
+		if (client.IsRetryable(err) || utils.IsTemporaryError(err)) && attempt < c.maxAttempts {
+			delay := client.RetryDelay(err, backoff)
+			if errors.Is(err, client.ErrRateLimited) && c.keys.Len() > 1 {
+				delay = c.keys.Wait()
+			}
+			c.logger.Warn("Erro temporário ao chamar OpenAI",
+				zap.Int("attempt", attempt),
+				zap.Error(err),
+				zap.Duration("backoff", delay),
+			)
+			select {
+			case <-ctx.Done():
+				return "", client.WrapTransportError("OpenAI", ctx.Err())
+			case <-time.After(delay):
+			}
+			backoff *= 2 // Backoff exponencial
+			continue
 		}
 
-		return response, nil
+		c.logger.Error("Erro ao fazer a requisição para OpenAI", zap.Error(err))
+		return "", fmt.Errorf("erro ao fazer a requisição para OpenAI: %w", err)
 	}
 
 	return "", fmt.Errorf("falha ao obter resposta da OpenAI após %d tentativas", c.maxAttempts)
 }
 
+// CheckKey valida as chaves listando os modelos, uma chamada autenticada que não consome tokens.
+// Com várias chaves, todas são verificadas e o erro indica qual delas falhou.
+func (c *OpenAIClient) CheckKey(ctx context.Context) error {
+	for i := 0; i < c.keys.Len(); i++ {
+		if err := c.checkKey(ctx, c.keys.Key(i)); err != nil {
+			if c.keys.Len() > 1 {
+				return fmt.Errorf("chave %d de %d: %w", i+1, c.keys.Len(), err)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// checkKey valida uma única chave
+func (c *OpenAIClient) checkKey(ctx context.Context, apiKey string) error {
+	modelsURL := strings.TrimSuffix(c.apiURL, openAIChatCompletionPath) + openAIModelsPath
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, modelsURL, nil)
+	if err != nil {
+		return fmt.Errorf("erro ao criar a requisição: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return client.WrapTransportError("OpenAI", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return client.NewHTTPError("OpenAI", resp.StatusCode, resp.Header, body)
+	}
+	return nil
+}
+
+// buildUserContent retorna o texto do prompt ou, com imagens, a lista de partes (texto e image_url)
+// com as imagens codificadas como data URLs
+func buildUserContent(prompt string, images []models.Image) interface{} {
+	if len(images) == 0 {
+		return prompt
+	}
+	parts := []map[string]interface{}{{"type": "text", "text": prompt}}
+	for _, image := range images {
+		imageURL := map[string]interface{}{
+			"url": "data:" + image.MediaType + ";base64," + base64.StdEncoding.EncodeToString(image.Data),
+		}
+		if image.Detail != "" {
+			imageURL["detail"] = image.Detail
+		}
+		parts = append(parts, map[string]interface{}{"type": "image_url", "image_url": imageURL})
+	}
+	return parts
+}
+
+// applyGenerationParams adiciona ao payload os parâmetros de amostragem definidos. O max_tokens só é
+// enviado quando definido, limitado ao máximo documentado do modelo; os modelos de raciocínio (o1, o3...)
+// o recebem como max_completion_tokens.
+func (c *OpenAIClient) applyGenerationParams(payload map[string]interface{}) {
+	if c.params.MaxTokens != nil {
+		maxTokens, clamped := models.ResolveMaxTokens(c.model, c.params.MaxTokens, 0)
+		if clamped {
+			c.logger.Warn("max_tokens acima do limite do modelo, usando o máximo documentado",
+				zap.String("model", c.model), zap.Int("solicitado", *c.params.MaxTokens), zap.Int("max_tokens", maxTokens))
+		}
+		if isReasoningModel(c.model) {
+			payload["max_completion_tokens"] = maxTokens
+		} else {
+			payload["max_tokens"] = maxTokens
+		}
+	}
+	if c.params.Temperature != nil {
+		payload["temperature"] = *c.params.Temperature
+	}
+	if c.params.TopP != nil {
+		payload["top_p"] = *c.params.TopP
+	}
+	if c.params.PresencePenalty != nil {
+		payload["presence_penalty"] = *c.params.PresencePenalty
+	}
+	if c.params.FrequencyPenalty != nil {
+		payload["frequency_penalty"] = *c.params.FrequencyPenalty
+	}
+}
+
+// isReasoningModel indica os modelos da série o, que não aceitam max_tokens
+func isReasoningModel(model string) bool {
+	model = strings.ToLower(model)
+	return len(model) > 1 && model[0] == 'o' && model[1] >= '0' && model[1] <= '9'
+}
+
 // sendRequest envia a requisição para a API da OpenAI
-func (c *OpenAIClient) sendRequest(ctx context.Context, jsonValue []byte) (*http.Response, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, openAIAPIURL, utils.NewJSONReader(jsonValue))
+func (c *OpenAIClient) sendRequest(ctx context.Context, jsonValue []byte, apiKey string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL, utils.NewJSONReader(jsonValue))
 	if err != nil {
 		c.logger.Error("Erro ao criar a requisição", zap.Error(err))
 		return nil, fmt.Errorf("erro ao criar a requisição: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	req.Header.Set("Authorization", "Bearer "+apiKey)
 
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, client.WrapTransportError("OpenAI", err)
 	}
 
 	return resp, nil
@@ -142,16 +315,15 @@ func (c *OpenAIClient) processResponse(resp *http.Response) (string, error) {
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
 		c.logger.Error("Erro ao ler a resposta da OpenAI", zap.Error(err))
-		return "", fmt.Errorf("erro ao ler a resposta da OpenAI: %w", err)
+		return "", client.WrapTransportError("OpenAI", fmt.Errorf("erro ao ler a resposta: %w", err))
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		errMsg := fmt.Sprintf("Erro na requisição à OpenAI: status %d, resposta: %s", resp.StatusCode, string(bodyBytes))
 		c.logger.Error("Resposta de erro da OpenAI",
 			zap.Int("status", resp.StatusCode),
 			zap.String("resposta", string(bodyBytes)),
 		)
-		return "", fmt.Errorf(errMsg)
+		return "", client.NewHTTPError("OpenAI", resp.StatusCode, resp.Header, bodyBytes)
 	}
 
 	var result map[string]interface{}
@@ -178,7 +350,22 @@ func (c *OpenAIClient) processResponse(resp *http.Response) (string, error) {
 		return "", fmt.Errorf("campo 'message' ausente na resposta da OpenAI")
 	}
 
-	content, ok := message["content"].(string)
+	// Recusas chegam com status 200: como message.refusal ou com finish_reason content_filter
+	content, _ := message["content"].(string)
+	if refusal, _ := message["refusal"].(string); refusal != "" && content == "" {
+		c.logger.Warn("Pedido recusado pela OpenAI", zap.String("recusa", refusal))
+		return "", client.NewContentFilterError("OpenAI", "recusa do modelo", refusal, nil)
+	}
+	if firstChoice["finish_reason"] == "content_filter" {
+		categories := client.FilteredCategories(bodyBytes)
+		c.logger.Warn("Resposta bloqueada pelo filtro de conteúdo da OpenAI", zap.Strings("categorias", categories))
+		if content == "" {
+			return "", client.NewContentFilterError("OpenAI", "finish_reason content_filter", "", categories)
+		}
+		return content + "\n\n[Resposta interrompida pelo filtro de conteúdo do provedor]", nil
+	}
+
+	content, ok = message["content"].(string)
 	if !ok {
 		c.logger.Error("Conteúdo da mensagem não é uma string", zap.Any("content", message["content"]))
 		return "", fmt.Errorf("conteúdo da mensagem não é válido")
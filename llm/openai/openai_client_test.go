@@ -2,10 +2,18 @@ package openai
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"github.com/diillson/chatcli/llm/client"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/diillson/chatcli/models"
+	"go.uber.org/zap"
 )
 
 func TestOpenAIClient_SendPrompt(t *testing.T) {
@@ -26,3 +34,180 @@ func TestOpenAIClient_SendPrompt(t *testing.T) {
 		t.Errorf("Resposta inesperada: %s", response)
 	}
 }
+
+func TestOpenAIClient_processResponseTypedErrors(t *testing.T) {
+	c := NewOpenAIClient("key", "gpt-4o-mini", "", zap.NewNop(), 1, time.Millisecond)
+
+	tests := []struct {
+		status   int
+		body     string
+		expected error
+	}{
+		{http.StatusUnauthorized, `{"error":{"message":"Incorrect API key"}}`, client.ErrAuth},
+		{http.StatusTooManyRequests, `{"error":{"type":"requests"}}`, client.ErrRateLimited},
+		{http.StatusBadRequest, `{"error":{"code":"context_length_exceeded"}}`, client.ErrContextTooLong},
+	}
+	for _, tt := range tests {
+		resp := &http.Response{
+			StatusCode: tt.status,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(tt.body)),
+		}
+		_, err := c.processResponse(resp)
+		if !errors.Is(err, tt.expected) {
+			t.Errorf("Status %d: esperado %v, obtido %v", tt.status, tt.expected, err)
+		}
+	}
+}
+
+func TestOpenAIClient_processResponseRefusals(t *testing.T) {
+	c := NewOpenAIClient("key", "gpt-4o-mini", "", zap.NewNop(), 1, time.Millisecond)
+	respond := func(status int, body string) (string, error) {
+		return c.processResponse(&http.Response{StatusCode: status, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(body))})
+	}
+
+	_, err := respond(http.StatusOK, `{"choices":[{"finish_reason":"stop","message":{"content":null,"refusal":"Não posso ajudar com isso."}}]}`)
+	var providerErr *client.ProviderError
+	if !errors.Is(err, client.ErrContentFiltered) || !errors.As(err, &providerErr) || providerErr.Message != "Não posso ajudar com isso." {
+		t.Errorf("Recusa do modelo: esperado ErrContentFiltered com a explicação, obtido %v", err)
+	}
+
+	_, err = respond(http.StatusOK, `{"choices":[{"finish_reason":"content_filter","message":{"content":""},
+		"content_filter_results":{"violence":{"filtered":true,"severity":"high"}}}]}`)
+	if !errors.As(err, &providerErr) || len(providerErr.Categories) != 1 || providerErr.Categories[0] != "violence (high)" {
+		t.Errorf("finish_reason content_filter: esperado a categoria violence, obtido %v", err)
+	}
+
+	response, err := respond(http.StatusOK, `{"choices":[{"finish_reason":"content_filter","message":{"content":"Parte da resposta"}}]}`)
+	if err != nil || !strings.HasPrefix(response, "Parte da resposta") || !strings.Contains(response, "filtro de conteúdo") {
+		t.Errorf("Resposta parcial: esperado o texto com um aviso, obtido %q (%v)", response, err)
+	}
+
+	_, err = respond(http.StatusBadRequest, `{"error":{"code":"content_filter","innererror":{"content_filter_result":{"sexual":{"filtered":true,"severity":"medium"}}}}}`)
+	if !errors.As(err, &providerErr) || !errors.Is(err, client.ErrContentFiltered) || len(providerErr.Categories) != 1 {
+		t.Errorf("Erro 400 do filtro: esperado ErrContentFiltered com categorias, obtido %v", err)
+	}
+}
+
+func TestOpenAIClient_baseURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("Caminho inesperado: %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"choices":[{"message":{"content":"resposta do gateway"}}]}`))
+	}))
+	defer server.Close()
+
+	c := NewOpenAIClient("key", "modelo-local", server.URL+"/v1/", zap.NewNop(), 1, time.Millisecond)
+	response, err := c.SendPrompt(context.Background(), "olá", nil)
+	if err != nil || response != "resposta do gateway" {
+		t.Errorf("Esperado resposta do gateway, obtido %q (erro: %v)", response, err)
+	}
+}
+
+func TestOpenAIClient_maxTokens(t *testing.T) {
+	maxTokens := 200000
+	for model, field := range map[string]string{"gpt-4o-mini": "max_tokens", "o1-mini": "max_completion_tokens"} {
+		c := NewOpenAIClient("key", model, "", zap.NewNop(), 1, time.Millisecond)
+		payload := map[string]interface{}{}
+		c.applyGenerationParams(payload)
+		if len(payload) != 0 {
+			t.Errorf("%s: max_tokens não deveria ser enviado quando não definido: %v", model, payload)
+		}
+
+		c.SetGenerationParams(models.GenerationParams{MaxTokens: &maxTokens})
+		c.applyGenerationParams(payload)
+		if payload[field] != models.MaxOutputTokens(model) {
+			t.Errorf("%s: esperado %s limitado a %d, obteve: %v", model, field, models.MaxOutputTokens(model), payload)
+		}
+	}
+}
+
+func TestOpenAIClient_responseFormat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&payload)
+		format, _ := payload["response_format"].(map[string]interface{})
+		if format["type"] != "json_object" {
+			t.Errorf("Esperado response_format json_object, obteve %v", payload["response_format"])
+		}
+		w.Write([]byte(`{"choices":[{"message":{"content":"{}"}}]}`))
+	}))
+	defer server.Close()
+
+	c := NewOpenAIClient("key", "gpt-4o-mini", server.URL, zap.NewNop(), 1, time.Millisecond)
+	c.SetResponseFormat(client.ResponseFormatJSON)
+	if _, err := c.SendPrompt(context.Background(), "responda em JSON", nil); err != nil {
+		t.Errorf("Erro inesperado: %v", err)
+	}
+}
+
+func TestOpenAIClient_images(t *testing.T) {
+	for model, expected := range map[string]bool{"gpt-4o": true, "gpt-4o-mini": true, "o1-mini": false, "gpt-3.5-turbo": false} {
+		c := NewOpenAIClient("key", model, "", zap.NewNop(), 1, time.Millisecond)
+		if c.SupportsImages() != expected {
+			t.Errorf("SupportsImages(%s): esperado %v", model, expected)
+		}
+	}
+
+	if content := buildUserContent("olá", nil); content != "olá" {
+		t.Errorf("Sem imagens, o conteúdo deveria ser o texto, obtido: %v", content)
+	}
+	parts, ok := buildUserContent("descreva", []models.Image{{MediaType: "image/png", Data: []byte("png"), Detail: "low"}}).([]map[string]interface{})
+	if !ok || len(parts) != 2 {
+		t.Fatalf("Esperado texto e imagem, obtido: %v", parts)
+	}
+	imageURL := parts[1]["image_url"].(map[string]interface{})
+	if imageURL["url"] != "data:image/png;base64,cG5n" || imageURL["detail"] != "low" {
+		t.Errorf("image_url inesperado: %v", imageURL)
+	}
+}
+
+func TestOpenAIClient_CheckKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/models" {
+			t.Errorf("Caminho inesperado: %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer sk-valida" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"data":[]}`))
+	}))
+	defer server.Close()
+
+	if err := NewOpenAIClient("sk-valida", "gpt-4o", server.URL+"/v1", zap.NewNop(), 1, time.Millisecond).CheckKey(context.Background()); err != nil {
+		t.Errorf("Erro inesperado: %v", err)
+	}
+	err := NewOpenAIClient("sk-invalida", "gpt-4o", server.URL+"/v1", zap.NewNop(), 1, time.Millisecond).CheckKey(context.Background())
+	if !errors.Is(err, client.ErrAuth) {
+		t.Errorf("Esperado ErrAuth, obtido: %v", err)
+	}
+}
+
+func TestOpenAIClient_keyRotation(t *testing.T) {
+	var used []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		used = append(used, key)
+		if key == "sk-limitada" {
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"choices":[{"message":{"content":"ok"}}]}`))
+	}))
+	defer server.Close()
+
+	// A chave limitada fica em espera e a próxima é usada sem aguardar o Retry-After nem consumir tentativa
+	c := NewOpenAIClientWithKeys([]string{"sk-limitada", "sk-livre"}, "gpt-4o", server.URL, zap.NewNop(), 1, time.Millisecond)
+	for i := 0; i < 2; i++ {
+		response, err := c.SendPrompt(context.Background(), "oi", nil)
+		if err != nil || response != "ok" {
+			t.Fatalf("Resposta inesperada: %q, %v", response, err)
+		}
+	}
+	if strings.Join(used, ",") != "sk-limitada,sk-livre,sk-livre" {
+		t.Errorf("Sequência de chaves inesperada: %v", used)
+	}
+}
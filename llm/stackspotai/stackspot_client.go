@@ -3,7 +3,9 @@ package stackspotai
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/diillson/chatcli/llm/client"
 	"github.com/diillson/chatcli/llm/token"
 	"io"
 	"net/http"
@@ -15,8 +17,10 @@ import (
 	"go.uber.org/zap"
 )
 
+// BaseURL é a URL dos quick commands da StackSpot
+const BaseURL = "https://genai-code-buddy-api.stackspot.com/v1/quick-commands"
+
 const (
-	stackSpotBaseURL         = "https://genai-code-buddy-api.stackspot.com/v1/quick-commands"
 	defaultMaxAttempts       = 50
 	defaultBackoff           = 300 * time.Second
 	stackSpotDefaultModel    = "StackSpotAI"
@@ -35,7 +39,7 @@ type StackSpotClient struct {
 
 // NewStackSpotClient cria uma nova instância de StackSpotClient.
 func NewStackSpotClient(tokenManager *token.TokenManager, slug string, logger *zap.Logger, maxAttempts int, backoff time.Duration) *StackSpotClient {
-	httpClient := utils.NewHTTPClient(logger, 300*time.Second)
+	httpClient := utils.NewProviderHTTPClient(logger)
 	if maxAttempts <= 0 {
 		maxAttempts = defaultMaxAttempts
 	}
@@ -58,6 +62,21 @@ func (c *StackSpotClient) GetModelName() string {
 	return stackSpotDefaultModel
 }
 
+// SetGenerationParams é aceito para manter a interface comum, mas a API da StackSpot
+// não expõe parâmetros de amostragem e eles são ignorados.
+func (c *StackSpotClient) SetGenerationParams(params models.GenerationParams) {
+	if !params.IsEmpty() {
+		c.logger.Debug("Parâmetros de geração não são suportados pela StackSpot e serão ignorados",
+			zap.String("parametros", params.String()))
+	}
+}
+
+// CheckKey valida CLIENT_ID e CLIENT_SECRET solicitando um novo token de acesso
+func (c *StackSpotClient) CheckKey(ctx context.Context) error {
+	_, err := c.tokenManager.RefreshToken(ctx)
+	return err
+}
+
 // SendPrompt envia um prompt para o modelo de linguagem e retorna a resposta.
 func (c *StackSpotClient) SendPrompt(ctx context.Context, prompt string, history []models.Message) (string, error) {
 	// Formatar o histórico da conversa
@@ -90,6 +109,8 @@ func formatConversationHistory(history []models.Message) string {
 		role := "Usuário"
 		if msg.Role == "assistant" {
 			role = "Assistente"
+		} else if msg.Role == "system" {
+			role = "Sistema"
 		}
 		conversationBuilder.WriteString(fmt.Sprintf("%s: %s\n", role, msg.Content))
 	}
@@ -106,14 +127,15 @@ func (c *StackSpotClient) sendRequestToLLMWithRetry(ctx context.Context, prompt
 		})
 
 		if err != nil {
-			if utils.IsTemporaryError(err) {
+			if client.IsRetryable(err) || utils.IsTemporaryError(err) {
+				delay := client.RetryDelay(err, backoff)
 				c.logger.Warn("Erro temporário ao enviar requisição para StackSpotAI",
 					zap.Int("attempt", attempt),
 					zap.Error(err),
-					zap.Duration("backoff", backoff),
+					zap.Duration("backoff", delay),
 				)
 				if attempt < c.maxAttempts {
-					time.Sleep(backoff)
+					time.Sleep(delay)
 					backoff *= 2 // Backoff exponencial
 					continue
 				}
@@ -131,7 +153,7 @@ func (c *StackSpotClient) sendRequestToLLMWithRetry(ctx context.Context, prompt
 func (c *StackSpotClient) sendRequestToLLM(ctx context.Context, prompt, accessToken string) (string, error) {
 	conversationID := utils.GenerateUUID()
 
-	url := fmt.Sprintf("%s/create-execution/%s?conversation_id=%s", stackSpotBaseURL, c.tokenManager.SlugName, conversationID)
+	url := fmt.Sprintf("%s/create-execution/%s?conversation_id=%s", BaseURL, c.tokenManager.SlugName, conversationID)
 	c.logger.Info("Enviando requisição para URL", zap.String("url", url))
 
 	requestBody := map[string]string{
@@ -154,14 +176,14 @@ func (c *StackSpotClient) sendRequestToLLM(ctx context.Context, prompt, accessTo
 	resp, err := c.client.Do(req)
 	if err != nil {
 		c.logger.Error("Erro ao fazer a requisição POST", zap.Error(err))
-		return "", fmt.Errorf("erro ao fazer a requisição: %w", err)
+		return "", client.WrapTransportError("StackSpotAI", err)
 	}
 	defer resp.Body.Close()
 
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
 		c.logger.Error("Erro ao ler a resposta POST", zap.Error(err))
-		return "", fmt.Errorf("erro ao ler a resposta: %w", err)
+		return "", client.WrapTransportError("StackSpotAI", fmt.Errorf("erro ao ler a resposta: %w", err))
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -169,7 +191,7 @@ func (c *StackSpotClient) sendRequestToLLM(ctx context.Context, prompt, accessTo
 			zap.Int("status_code", resp.StatusCode),
 			zap.String("response", string(bodyBytes)),
 		)
-		return "", fmt.Errorf("erro na requisição à LLM: status %d, resposta: %s", resp.StatusCode, string(bodyBytes))
+		return "", client.NewHTTPError("StackSpotAI", resp.StatusCode, resp.Header, bodyBytes)
 	}
 
 	var responseID string
@@ -195,8 +217,8 @@ func (c *StackSpotClient) executeWithTokenRetry(ctx context.Context, requestFunc
 	// Tenta executar a função de requisição com o token atual
 	response, err := requestFunc(token)
 	if err != nil {
-		// Se o erro for 403, tenta renovar o token e refazer a requisição
-		if strings.Contains(err.Error(), "403") || strings.Contains(err.Error(), "401") {
+		// Se o erro for de autenticação (401/403), tenta renovar o token e refazer a requisição
+		if errors.Is(err, client.ErrAuth) {
 			c.logger.Warn("Token expirado ou inválido, tentando renovar...")
 
 			// Tenta renovar o token
@@ -249,7 +271,7 @@ func (c *StackSpotClient) pollLLMResponse(ctx context.Context, responseID string
 
 // getLLMResponse obtém a resposta da LLM usando o responseID
 func (c *StackSpotClient) getLLMResponse(ctx context.Context, responseID, accessToken string) (string, error) {
-	url := fmt.Sprintf("%s/callback/%s", stackSpotBaseURL, responseID)
+	url := fmt.Sprintf("%s/callback/%s", BaseURL, responseID)
 	c.logger.Info("Fazendo GET para URL", zap.String("url", url))
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
@@ -263,20 +285,20 @@ func (c *StackSpotClient) getLLMResponse(ctx context.Context, responseID, access
 	resp, err := c.client.Do(req)
 	if err != nil {
 		c.logger.Error("Erro na requisição GET para a LLM", zap.Error(err))
-		return "", fmt.Errorf("erro na requisição GET para a LLM: %w", err)
+		return "", client.WrapTransportError("StackSpotAI", err)
 	}
 	defer resp.Body.Close()
 
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
 		c.logger.Error("Erro ao ler o corpo da resposta da LLM", zap.Error(err))
-		return "", fmt.Errorf("erro ao ler o corpo da resposta da LLM: %w", err)
+		return "", client.WrapTransportError("StackSpotAI", fmt.Errorf("erro ao ler o corpo da resposta da LLM: %w", err))
 	}
 
 	c.logger.Info("Resposta recebida", zap.Int("status_code", resp.StatusCode), zap.String("response", string(bodyBytes)))
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("erro na requisição de callback: status %d, resposta: %s", resp.StatusCode, string(bodyBytes))
+		return "", client.NewHTTPError("StackSpotAI", resp.StatusCode, resp.Header, bodyBytes)
 	}
 
 	var callbackResponse CallbackResponse
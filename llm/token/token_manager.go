@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/diillson/chatcli/llm/client"
 	"github.com/diillson/chatcli/utils"
 	"go.uber.org/zap"
 	"io"
@@ -140,9 +141,9 @@ func (tm *TokenManager) requestToken(ctx context.Context) (string, error) {
 
 	if resp.StatusCode != http.StatusOK {
 		bodyBytes, _ := io.ReadAll(resp.Body)
-		errMsg := fmt.Sprintf("falha ao obter o token: status %d, resposta: %s", resp.StatusCode, string(bodyBytes))
-		tm.logger.Error("Falha na requisição de token", zap.String("response", errMsg))
-		return "", fmt.Errorf(errMsg)
+		tm.logger.Error("Falha na requisição de token", zap.Int("status", resp.StatusCode), zap.String("response", string(bodyBytes)))
+		// O erro tipado permite identificar credenciais inválidas com errors.Is(err, client.ErrAuth)
+		return "", client.NewHTTPError("StackSpot", resp.StatusCode, resp.Header, bodyBytes)
 	}
 
 	var result map[string]interface{}
@@ -5,10 +5,13 @@ import (
 	"fmt"
 	"github.com/diillson/chatcli/llm/manager"
 	"os"
-	"os/signal"
-	"syscall"
+	"strings"
 
+	"github.com/diillson/chatcli/batch"
 	"github.com/diillson/chatcli/cli"
+	"github.com/diillson/chatcli/completion"
+	"github.com/diillson/chatcli/config"
+	"github.com/diillson/chatcli/server"
 	"github.com/diillson/chatcli/utils"
 	"github.com/joho/godotenv"
 	"go.uber.org/zap"
@@ -20,19 +23,20 @@ const (
 )
 
 func main() {
-	// Carregar variáveis de ambiente do arquivo .env
-	envFilePath := os.Getenv("CHATCLI_DOTENV")
-	if envFilePath == "" {
-		envFilePath = ".env"
-	} else {
-		expanded, err := utils.ExpandPath(envFilePath)
-		if err == nil {
-			envFilePath = expanded
-		} else {
-			fmt.Printf("Aviso: não foi possível expandir o caminho '%s': %v\n", envFilePath, err)
+	// Subcomandos são tratados antes de carregar o .env para que a origem dos valores seja preservada
+	if len(os.Args) > 1 {
+		if handled, err := runSubcommand(os.Args[1], os.Args[2:]); handled {
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Erro:", err)
+				os.Exit(1)
+			}
+			return
 		}
 	}
 
+	// Carregar variáveis de ambiente do arquivo .env
+	envFilePath := config.DotenvPath()
+
 	if err := godotenv.Load(envFilePath); err != nil && !os.IsNotExist(err) {
 		fmt.Printf("Não foi encontrado o arquivo .env em %s\n", envFilePath)
 	}
@@ -48,7 +52,23 @@ func main() {
 	// Configurar o contexto para o shutdown gracioso
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	handleGracefulShutdown(cancel, logger)
+	cli.HandleSignals(cancel, logger)
+
+	// Os modos batch, serve e doctor não usam o REPL; os avisos de configuração são omitidos para não misturar com a saída
+	if len(os.Args) > 1 && (os.Args[1] == "batch" || os.Args[1] == "serve" || os.Args[1] == "doctor") {
+		if err := runHeadless(ctx, os.Args[1], os.Args[2:], logger); err != nil {
+			fmt.Fprintln(os.Stderr, "Erro:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// No modo interativo, os argumentos são apenas os limites globais de contexto
+	contextLimits, err := cli.ParseContextLimitFlags(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Erro:", err)
+		os.Exit(1)
+	}
 
 	// Verificar variáveis de ambiente e informar o usuário
 	utils.CheckEnvVariables(logger, defaultSlugName, defaultTenantName)
@@ -73,18 +93,74 @@ func main() {
 	if err != nil {
 		logger.Fatal("Erro ao inicializar o ChatCLI", zap.Error(err))
 	}
+	chatCLI.SetContextLimits(contextLimits)
 
 	chatCLI.Start(ctx)
 }
 
-// handleGracefulShutdown configura o tratamento de sinais para um shutdown gracioso
-func handleGracefulShutdown(cancelFunc context.CancelFunc, logger *zap.Logger) {
-	signals := make(chan os.Signal, 1)
-	// Capturar sinais de interrupção e término
-	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
-	go func() {
-		sig := <-signals
-		logger.Info("Recebido sinal para finalizar a aplicação", zap.String("sinal", sig.String()))
-		cancelFunc()
-	}()
+// runSubcommand executa os subcomandos de uso único (config, completion).
+// Retorna false quando o argumento não corresponde a um subcomando conhecido.
+func runSubcommand(name string, args []string) (bool, error) {
+	switch name {
+	case "config":
+		return true, config.RunCommand(args, os.Stdout)
+	case "completion":
+		if len(args) != 1 {
+			return true, fmt.Errorf("uso: chatcli completion <%s>", strings.Join(completion.SupportedShells, "|"))
+		}
+		return true, completion.Generate(args[0], completionSpec(), os.Stdout)
+	default:
+		return false, nil
+	}
+}
+
+// runHeadless inicializa o LLMManager e executa o subcomando batch, serve ou doctor
+func runHeadless(ctx context.Context, name string, args []string, logger *zap.Logger) error {
+	slugName := utils.GetEnvOrDefault("SLUG_NAME", defaultSlugName)
+	tenantName := utils.GetEnvOrDefault("TENANT_NAME", defaultTenantName)
+	manager, err := manager.NewLLMManager(logger, slugName, tenantName)
+	if err != nil {
+		return fmt.Errorf("erro ao inicializar o LLMManager: %w", err)
+	}
+	if name == "doctor" {
+		if failures := cli.RunDoctor(ctx, manager, logger, os.Stdout); failures > 0 {
+			return fmt.Errorf("o diagnóstico encontrou %d falha(s)", failures)
+		}
+		return nil
+	}
+	// O roteamento automático é do modo interativo; fora dele, AUTO usa o primeiro provedor disponível
+	provider := utils.GetEnvOrDefault("LLM_PROVIDER", "STACKSPOT")
+	if strings.EqualFold(provider, "AUTO") {
+		if available := manager.GetAvailableProviders(); len(available) > 0 {
+			provider = available[0]
+		}
+	}
+	if name == "serve" {
+		return server.RunCommand(ctx, args, manager, provider, logger)
+	}
+	return batch.RunCommand(ctx, args, manager, provider, logger)
+}
+
+// completionSpec descreve os subcomandos aceitos por runSubcommand para a geração dos scripts de completion
+func completionSpec() completion.Spec {
+	keyArgs := config.KeyNames()
+	return completion.Spec{
+		Program: "chatcli",
+		Flags:   cli.ContextLimitFlags,
+		Commands: []completion.Command{
+			{
+				Name:        "config",
+				Subcommands: config.Subcommands,
+				SubcommandArgs: map[string][]string{
+					"get":   keyArgs,
+					"set":   keyArgs,
+					"unset": keyArgs,
+				},
+			},
+			{Name: "completion", Subcommands: completion.SupportedShells},
+			{Name: "batch"},
+			{Name: "serve"},
+			{Name: "doctor"},
+		},
+	}
 }
@@ -0,0 +1,70 @@
+package models
+
+import "fmt"
+
+// GenerationParams reúne os parâmetros de amostragem aplicados às requisições das LLMs.
+// Campos nil indicam que o padrão do provedor deve ser usado.
+type GenerationParams struct {
+	Temperature      *float64
+	TopP             *float64
+	PresencePenalty  *float64
+	FrequencyPenalty *float64
+	MaxTokens        *int
+}
+
+// IsEmpty indica se nenhum parâmetro foi definido
+func (p GenerationParams) IsEmpty() bool {
+	return p.Temperature == nil && p.TopP == nil && p.PresencePenalty == nil && p.FrequencyPenalty == nil &&
+		p.MaxTokens == nil
+}
+
+// Validate verifica se os parâmetros definidos estão dentro dos intervalos aceitos
+func (p GenerationParams) Validate() error {
+	checks := []struct {
+		name     string
+		value    *float64
+		min, max float64
+	}{
+		{"temperature", p.Temperature, 0, 2},
+		{"top_p", p.TopP, 0, 1},
+		{"presence_penalty", p.PresencePenalty, -2, 2},
+		{"frequency_penalty", p.FrequencyPenalty, -2, 2},
+	}
+	for _, c := range checks {
+		if c.value != nil && (*c.value < c.min || *c.value > c.max) {
+			return fmt.Errorf("%s deve estar entre %g e %g", c.name, c.min, c.max)
+		}
+	}
+	if p.MaxTokens != nil && *p.MaxTokens < 1 {
+		return fmt.Errorf("max_tokens deve ser maior que zero")
+	}
+	return nil
+}
+
+// String retorna os parâmetros definidos em formato legível
+func (p GenerationParams) String() string {
+	if p.IsEmpty() {
+		return "padrão do provedor"
+	}
+	var s string
+	add := func(name string, value *float64) {
+		if value == nil {
+			return
+		}
+		if s != "" {
+			s += ", "
+		}
+		s += fmt.Sprintf("%s=%g", name, *value)
+	}
+	add("temperature", p.Temperature)
+	add("top_p", p.TopP)
+	add("presence_penalty", p.PresencePenalty)
+	add("frequency_penalty", p.FrequencyPenalty)
+	if p.MaxTokens != nil {
+		if s != "" {
+			s += ", "
+		}
+		s += fmt.Sprintf("max_tokens=%d", *p.MaxTokens)
+	}
+	return s
+}
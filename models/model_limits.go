@@ -0,0 +1,61 @@
+package models
+
+import "strings"
+
+// DefaultClaudeMaxTokens é o max_tokens usado na ClaudeAI quando nenhum é definido, já que a API o exige
+const DefaultClaudeMaxTokens = 8192
+
+// maxOutputTokens traz o limite documentado de tokens de saída por prefixo do nome do modelo.
+// O prefixo mais longo que corresponder ao modelo vence, então "gpt-4o-mini" prevalece sobre "gpt-4".
+var maxOutputTokens = map[string]int{
+	// OpenAI
+	"gpt-3.5-turbo":     4096,
+	"gpt-4":             8192,
+	"gpt-4-turbo":       4096,
+	"gpt-4o":            16384,
+	"gpt-4o-2024-05-13": 4096,
+	"gpt-4o-mini":       16384,
+	"chatgpt-4o":        16384,
+	"gpt-4.1":           32768,
+	"gpt-5":             128000,
+	"o1":                100000,
+	"o1-mini":           65536,
+	"o3":                100000,
+	"o4-mini":           100000,
+	// ClaudeAI
+	"claude-3-haiku":    4096,
+	"claude-3-sonnet":   4096,
+	"claude-3-opus":     4096,
+	"claude-3-5-haiku":  8192,
+	"claude-3-5-sonnet": 8192,
+	"claude-3-7-sonnet": 64000,
+	"claude-sonnet-4":   64000,
+	"claude-opus-4":     32000,
+}
+
+// MaxOutputTokens retorna o limite de tokens de saída documentado para o modelo, ou zero se ele
+// for desconhecido (como os modelos locais do Ollama)
+func MaxOutputTokens(model string) int {
+	model = strings.ToLower(model)
+	best, limit := "", 0
+	for prefix, tokens := range maxOutputTokens {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(best) {
+			best, limit = prefix, tokens
+		}
+	}
+	return limit
+}
+
+// ResolveMaxTokens decide o max_tokens de uma requisição: o valor pedido, ou fallback quando não há
+// um (zero significa não enviar), limitado ao máximo documentado do modelo. Retorna também se o
+// valor foi reduzido, para que o chamador possa avisar.
+func ResolveMaxTokens(model string, requested *int, fallback int) (int, bool) {
+	value := fallback
+	if requested != nil {
+		value = *requested
+	}
+	if limit := MaxOutputTokens(model); limit > 0 && value > limit {
+		return limit, requested != nil
+	}
+	return value, false
+}
@@ -11,6 +11,18 @@ const (
 type Message struct {
 	Role    string `json:"role"`    // O papel da mensagem, como "user" ou "assistant".
 	Content string `json:"content"` // O conteúdo da mensagem.
+	// SystemPrompt marca o contexto de sistema montado pelo CLI (memória, prompt do projeto e contexto
+	// padrão), a única mensagem que os provedores com campo próprio tratam como prompt de sistema. Não é
+	// gravado: as demais mensagens de sistema, como saídas de comandos e resumos, nunca o têm.
+	SystemPrompt bool `json:"-"`
+}
+
+// Image representa uma imagem enviada junto ao prompt para modelos com visão.
+type Image struct {
+	Path      string // Caminho de origem, usado apenas para exibição.
+	MediaType string // O tipo MIME, como "image/png".
+	Data      []byte // O conteúdo da imagem.
+	Detail    string // O nível de detalhe ("low" ou "high"); vazio usa o padrão do provedor.
 }
 
 // IsValid valida se a mensagem tem um papel e conteúdo válidos.
@@ -25,3 +25,52 @@ func TestResponseData_IsValid(t *testing.T) {
 		t.Error("Resposta inválida foi considerada válida")
 	}
 }
+
+func TestGenerationParams_Validate(t *testing.T) {
+	value := func(f float64) *float64 { return &f }
+
+	valid := GenerationParams{Temperature: value(0.2), TopP: value(0.9), PresencePenalty: value(-1)}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Esperado parâmetros válidos, obteve: %v", err)
+	}
+
+	invalid := []GenerationParams{
+		{Temperature: value(2.5)},
+		{TopP: value(1.1)},
+		{FrequencyPenalty: value(-3)},
+	}
+	for _, p := range invalid {
+		if err := p.Validate(); err == nil {
+			t.Errorf("Esperado erro de validação para %s", p)
+		}
+	}
+
+	if got := (GenerationParams{}).String(); got != "padrão do provedor" {
+		t.Errorf("String inesperada para parâmetros vazios: %s", got)
+	}
+}
+
+func TestResolveMaxTokens(t *testing.T) {
+	value := func(n int) *int { return &n }
+
+	cases := []struct {
+		model     string
+		requested *int
+		fallback  int
+		want      int
+		clamped   bool
+	}{
+		{"gpt-4o-mini", value(50000), 0, 16384, true},
+		{"gpt-4o-2024-05-13", value(8000), 0, 4096, true},
+		{"gpt-4", value(4000), 0, 4000, false},
+		{"claude-3-opus-20240229", nil, DefaultClaudeMaxTokens, 4096, false},
+		{"claude-3-5-sonnet-20241022", nil, DefaultClaudeMaxTokens, 8192, false},
+		{"llama3.1", value(50000), 0, 50000, false},
+	}
+	for _, c := range cases {
+		got, clamped := ResolveMaxTokens(c.model, c.requested, c.fallback)
+		if got != c.want || clamped != c.clamped {
+			t.Errorf("ResolveMaxTokens(%s) = %d, %v; esperado %d, %v", c.model, got, clamped, c.want, c.clamped)
+		}
+	}
+}
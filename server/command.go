@@ -0,0 +1,137 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/diillson/chatcli/llm/manager"
+	"go.uber.org/zap"
+)
+
+const commandUsage = `Uso: chatcli serve [--addr 127.0.0.1:8099] [--token <segredo>] [--timeout 2m]
+
+Expõe uma API REST com os provedores configurados:
+  POST /chat       {"provider"?, "model"?, "prompt", "history"?, "stream"?}
+  GET  /providers  provedores disponíveis e o padrão
+
+O segredo compartilhado (--token ou CHATCLI_SERVER_TOKEN) deve ser enviado no cabeçalho
+X-ChatCLI-Token ou em Authorization: Bearer. Sem ele, o servidor só aceita endereços locais.`
+
+// commandArgs são os argumentos de 'chatcli serve' já interpretados
+type commandArgs struct {
+	addr    string
+	token   string
+	timeout time.Duration
+}
+
+// parseArgs interpreta os argumentos de 'chatcli serve'. O token padrão vem de CHATCLI_SERVER_TOKEN.
+func parseArgs(args []string) (commandArgs, error) {
+	parsed := commandArgs{addr: DefaultAddr, token: os.Getenv("CHATCLI_SERVER_TOKEN"), timeout: DefaultTimeout}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case "--addr", "--token", "--timeout":
+			if i+1 >= len(args) {
+				return parsed, fmt.Errorf("valor ausente para %s", arg)
+			}
+			value := args[i+1]
+			i++
+			switch arg {
+			case "--addr":
+				if _, _, err := net.SplitHostPort(value); err != nil {
+					return parsed, fmt.Errorf("valor inválido para --addr: %s (use host:porta ou :porta)", value)
+				}
+				parsed.addr = value
+			case "--token":
+				parsed.token = value
+			case "--timeout":
+				d, err := time.ParseDuration(value)
+				if err != nil || d <= 0 {
+					return parsed, fmt.Errorf("valor inválido para --timeout: %s", value)
+				}
+				parsed.timeout = d
+			}
+		case "-h", "--help":
+			return parsed, fmt.Errorf("%s", commandUsage)
+		default:
+			return parsed, fmt.Errorf("argumento inesperado: %s\n\n%s", arg, commandUsage)
+		}
+	}
+	if parsed.token == "" && !isLoopback(parsed.addr) {
+		return parsed, fmt.Errorf("o endereço %s aceita conexões de outras máquinas; defina --token ou CHATCLI_SERVER_TOKEN", parsed.addr)
+	}
+	return parsed, nil
+}
+
+// isLoopback indica se o endereço só aceita conexões da própria máquina. Um host vazio (":8099")
+// escuta em todas as interfaces.
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil || host == "" {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// isLoopbackHost indica se o cabeçalho Host, com ou sem porta, é um nome da própria máquina
+func isLoopbackHost(host string) bool {
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(strings.Trim(host, "[]"), "0")
+	}
+	return isLoopback(host)
+}
+
+// RunCommand executa o subcomando 'serve' até que ctx seja cancelado, encerrando o servidor de forma
+// graciosa
+func RunCommand(ctx context.Context, args []string, mgr manager.LLMManager, defaultProvider string, logger *zap.Logger) error {
+	parsed, err := parseArgs(args)
+	if err != nil {
+		return err
+	}
+	if len(mgr.GetAvailableProviders()) == 0 {
+		return fmt.Errorf("nenhum provedor LLM está configurado; verifique suas variáveis de ambiente")
+	}
+
+	srv := &http.Server{
+		Addr:              parsed.addr,
+		Handler:           NewHandler(mgr, Options{Token: parsed.token, DefaultProvider: defaultProvider, Timeout: parsed.timeout}, logger),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	listener, err := net.Listen("tcp", parsed.addr)
+	if err != nil {
+		return fmt.Errorf("erro ao escutar em %s: %w", parsed.addr, err)
+	}
+
+	// Serve retorna assim que Shutdown começa; stopped garante que as requisições em andamento terminem
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	auth := "sem token (apenas conexões locais)"
+	if parsed.token != "" {
+		auth = "token exigido em " + TokenHeader
+	}
+	fmt.Fprintf(os.Stderr, "Servidor do ChatCLI em http://%s (provedor padrão: %s; %s). Ctrl+C encerra.\n", listener.Addr(), defaultProvider, auth)
+	logger.Info("Servidor REST iniciado", zap.String("addr", listener.Addr().String()))
+
+	if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("erro no servidor: %w", err)
+	}
+	<-stopped
+	return nil
+}
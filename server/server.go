@@ -0,0 +1,248 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/diillson/chatcli/batch"
+	"github.com/diillson/chatcli/llm/client"
+	"github.com/diillson/chatcli/llm/manager"
+	"github.com/diillson/chatcli/models"
+	"go.uber.org/zap"
+)
+
+const (
+	DefaultAddr    = "127.0.0.1:8099"
+	DefaultTimeout = 2 * time.Minute
+	// TokenHeader é o cabeçalho com o segredo compartilhado; Authorization: Bearer também é aceito
+	TokenHeader = "X-ChatCLI-Token"
+	// maxBodySize limita o corpo de POST /chat, que inclui o histórico
+	maxBodySize = 10 * 1024 * 1024
+	// keepAliveInterval é o intervalo dos comentários enviados no SSE enquanto o provedor responde,
+	// para que proxies não encerrem a conexão ociosa
+	keepAliveInterval = 15 * time.Second
+)
+
+// ChatRequest é o corpo de POST /chat
+type ChatRequest struct {
+	Provider string           `json:"provider,omitempty"`
+	Model    string           `json:"model,omitempty"`
+	Prompt   string           `json:"prompt"`
+	History  []models.Message `json:"history,omitempty"`
+	Stream   bool             `json:"stream,omitempty"`
+}
+
+// ChatResponse é a resposta de POST /chat
+type ChatResponse struct {
+	Provider   string `json:"provider"`
+	Model      string `json:"model,omitempty"`
+	Response   string `json:"response"`
+	DurationMS int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+	ErrorType  string `json:"error_type,omitempty"`
+}
+
+// Options controla o servidor
+type Options struct {
+	// Token é o segredo compartilhado exigido em cada requisição; vazio desativa a autenticação
+	Token           string
+	DefaultProvider string
+	Timeout         time.Duration
+}
+
+// handler atende a API REST usando os clientes do LLMManager
+type handler struct {
+	mgr    manager.LLMManager
+	opts   Options
+	logger *zap.Logger
+}
+
+// NewHandler cria o http.Handler com as rotas POST /chat e GET /providers
+func NewHandler(mgr manager.LLMManager, opts Options, logger *zap.Logger) http.Handler {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	h := &handler{mgr: mgr, opts: opts, logger: logger}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/chat", h.authorized(h.handleChat))
+	mux.HandleFunc("/providers", h.authorized(h.handleProviders))
+	return mux
+}
+
+// authorized exige o segredo compartilhado, quando configurado, antes de chamar next. Sem segredo, o
+// servidor escuta apenas na própria máquina, mas as páginas abertas no navegador também alcançam
+// 127.0.0.1: o cabeçalho Host precisa ser um nome local, o que barra o DNS rebinding.
+func (h *handler) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if h.opts.Token == "" && !isLoopbackHost(r.Host) {
+			writeError(w, http.StatusForbidden, fmt.Sprintf("host não permitido: %s", r.Host))
+			return
+		}
+		if h.opts.Token != "" {
+			token := r.Header.Get(TokenHeader)
+			if token == "" {
+				token = strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			}
+			if subtle.ConstantTimeCompare([]byte(token), []byte(h.opts.Token)) != 1 {
+				writeError(w, http.StatusUnauthorized, "token ausente ou inválido")
+				return
+			}
+		}
+		next(w, r)
+	}
+}
+
+// handleProviders trata GET /providers
+func (h *handler) handleProviders(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "use GET")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"providers": h.mgr.GetAvailableProviders(),
+		"default":   h.opts.DefaultProvider,
+	})
+}
+
+// handleChat trata POST /chat. Com "stream": true, a resposta é enviada como Server-Sent Events.
+func (h *handler) handleChat(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "use POST")
+		return
+	}
+	// Um navegador envia POST com text/plain a outro site sem consulta prévia (CORS); com
+	// application/json, a consulta é obrigatória e, sem resposta do servidor, a requisição não é feita
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+		writeError(w, http.StatusUnsupportedMediaType, "use Content-Type: application/json")
+		return
+	}
+	var req ChatRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "JSON inválido: "+err.Error())
+		return
+	}
+	if strings.TrimSpace(req.Prompt) == "" {
+		writeError(w, http.StatusBadRequest, "campo 'prompt' ausente")
+		return
+	}
+	for i, msg := range req.History {
+		if msg.Role != "user" && msg.Role != "assistant" && msg.Role != "system" {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("history[%d]: papel inválido %q (use user, assistant ou system)", i, msg.Role))
+			return
+		}
+	}
+
+	provider := strings.ToUpper(req.Provider)
+	if provider == "" {
+		provider = h.opts.DefaultProvider
+	}
+	model := req.Model
+	if model == "" {
+		model = batch.ModelFromEnv(provider)
+	}
+	llmClient, err := h.mgr.GetClient(provider, model)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.Stream {
+		h.streamChat(w, r, llmClient, provider, req)
+		return
+	}
+	result := h.send(r.Context(), llmClient, provider, req)
+	writeJSON(w, statusFor(result), result)
+}
+
+// send envia o prompt ao provedor com o tempo limite do servidor e monta a resposta
+func (h *handler) send(ctx context.Context, llmClient client.LLMClient, provider string, req ChatRequest) ChatResponse {
+	result := ChatResponse{Provider: provider, Model: llmClient.GetModelName()}
+	ctx, cancel := context.WithTimeout(ctx, h.opts.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	response, err := llmClient.SendPrompt(ctx, req.Prompt, req.History)
+	result.DurationMS = time.Since(start).Milliseconds()
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded && !errors.Is(err, client.ErrTimeout) {
+			err = fmt.Errorf("%w: %v", client.ErrTimeout, err)
+		}
+		h.logger.Warn("Falha ao atender /chat", zap.String("provider", provider), zap.Error(err))
+		result.Error = err.Error()
+		result.ErrorType = batch.ErrorType(err)
+		return result
+	}
+	result.Response = response
+	return result
+}
+
+// streamChat responde em SSE. Os clientes recebem a resposta completa do provedor, então ela chega em
+// um único evento "response" (ou "error"); até lá, comentários periódicos mantêm a conexão ativa.
+func (h *handler) streamChat(w http.ResponseWriter, r *http.Request, llmClient client.LLMClient, provider string, req ChatRequest) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming não suportado pela conexão")
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	done := make(chan ChatResponse, 1)
+	go func() { done <- h.send(r.Context(), llmClient, provider, req) }()
+
+	ticker := time.NewTicker(keepAliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			fmt.Fprint(w, ": aguardando o provedor\n\n")
+			flusher.Flush()
+		case result := <-done:
+			event := "response"
+			if result.Error != "" {
+				event = "error"
+			}
+			data, _ := json.Marshal(result)
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\nevent: done\ndata: {}\n\n", event, data)
+			flusher.Flush()
+			return
+		}
+	}
+}
+
+// statusFor escolhe o status HTTP de acordo com o tipo de erro do provedor
+func statusFor(result ChatResponse) int {
+	switch result.ErrorType {
+	case "":
+		return http.StatusOK
+	case "timeout":
+		return http.StatusGatewayTimeout
+	case "rate_limited":
+		return http.StatusTooManyRequests
+	case "content_filtered":
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusBadGateway
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
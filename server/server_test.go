@@ -0,0 +1,197 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/diillson/chatcli/llm/client"
+	"github.com/diillson/chatcli/llm/token"
+	"github.com/diillson/chatcli/models"
+	"go.uber.org/zap"
+)
+
+// echoClient responde com o prompt e o tamanho do histórico recebido
+type echoClient struct{ model string }
+
+func (c *echoClient) GetModelName() string { return c.model }
+
+func (c *echoClient) SendPrompt(ctx context.Context, prompt string, history []models.Message) (string, error) {
+	if prompt == "limite" {
+		return "", client.NewHTTPError("OpenAI", 429, nil, []byte("rate limit"))
+	}
+	return fmt.Sprintf("eco: %s (%d no histórico)", prompt, len(history)), nil
+}
+
+type fakeManager struct{}
+
+func (m *fakeManager) GetClient(provider, model string) (client.LLMClient, error) {
+	if provider != "OPENAI" {
+		return nil, fmt.Errorf("provedor %s não configurado", provider)
+	}
+	if model == "" {
+		model = "padrao"
+	}
+	return &echoClient{model: model}, nil
+}
+
+func (m *fakeManager) GetAvailableProviders() []string { return []string{"OPENAI"} }
+
+func (m *fakeManager) GetTokenManager() (*token.TokenManager, bool) { return nil, false }
+
+func newTestServer(t *testing.T, token string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(NewHandler(&fakeManager{}, Options{Token: token, DefaultProvider: "OPENAI"}, zap.NewNop()))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func postChat(t *testing.T, url, token, body string) *http.Response {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodPost, url+"/chat", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set(TokenHeader, token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("erro na requisição: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestChat(t *testing.T) {
+	srv := newTestServer(t, "")
+	resp := postChat(t, srv.URL, "", `{"model": "gpt-x", "prompt": "olá", "history": [{"role": "user", "content": "oi"}, {"role": "assistant", "content": "oi!"}]}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status inesperado: %d", resp.StatusCode)
+	}
+	var result ChatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Provider != "OPENAI" || result.Model != "gpt-x" || result.Response != "eco: olá (2 no histórico)" {
+		t.Errorf("resposta inesperada: %+v", result)
+	}
+}
+
+func TestChat_errors(t *testing.T) {
+	srv := newTestServer(t, "")
+	tests := []struct {
+		body   string
+		status int
+	}{
+		{`{"prompt": ""}`, http.StatusBadRequest},
+		{`não é json`, http.StatusBadRequest},
+		{`{"prompt": "oi", "provider": "claudeai"}`, http.StatusBadRequest},
+		{`{"prompt": "oi", "history": [{"role": "tool", "content": "x"}]}`, http.StatusBadRequest},
+		{`{"prompt": "limite"}`, http.StatusTooManyRequests},
+	}
+	for _, tt := range tests {
+		if resp := postChat(t, srv.URL, "", tt.body); resp.StatusCode != tt.status {
+			t.Errorf("%s: status %d, esperava %d", tt.body, resp.StatusCode, tt.status)
+		}
+	}
+}
+
+func TestChat_stream(t *testing.T) {
+	srv := newTestServer(t, "")
+	resp := postChat(t, srv.URL, "", `{"prompt": "olá", "stream": true}`)
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type inesperado: %s", ct)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(body), "event: response\ndata: {") || !strings.HasSuffix(string(body), "event: done\ndata: {}\n\n") {
+		t.Errorf("eventos inesperados:\n%s", body)
+	}
+}
+
+func TestAuth(t *testing.T) {
+	srv := newTestServer(t, "segredo")
+	if resp := postChat(t, srv.URL, "", `{"prompt": "olá"}`); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("sem token: status %d", resp.StatusCode)
+	}
+	if resp := postChat(t, srv.URL, "errado", `{"prompt": "olá"}`); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("token errado: status %d", resp.StatusCode)
+	}
+	if resp := postChat(t, srv.URL, "segredo", `{"prompt": "olá"}`); resp.StatusCode != http.StatusOK {
+		t.Errorf("token correto: status %d", resp.StatusCode)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/providers", nil)
+	req.Header.Set("Authorization", "Bearer segredo")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var providers struct {
+		Providers []string `json:"providers"`
+		Default   string   `json:"default"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&providers); err != nil {
+		t.Fatal(err)
+	}
+	if len(providers.Providers) != 1 || providers.Default != "OPENAI" {
+		t.Errorf("provedores inesperados: %+v", providers)
+	}
+}
+
+func TestCrossSiteRequests(t *testing.T) {
+	srv := newTestServer(t, "")
+
+	// Requisição simples de outro site: POST com text/plain não tem consulta prévia do navegador
+	resp, err := http.Post(srv.URL+"/chat", "text/plain", strings.NewReader(`{"prompt": "olá"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnsupportedMediaType {
+		t.Errorf("text/plain: status %d", resp.StatusCode)
+	}
+
+	// DNS rebinding: o navegador envia o nome do site atacante no Host
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/chat", strings.NewReader(`{"prompt": "olá"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Host = "atacante.example.com:8099"
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("host externo: status %d", resp.StatusCode)
+	}
+
+	for _, host := range []string{"localhost:8099", "127.0.0.1", "[::1]:8099"} {
+		if !isLoopbackHost(host) {
+			t.Errorf("%s deveria ser aceito", host)
+		}
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	t.Setenv("CHATCLI_SERVER_TOKEN", "")
+	parsed, err := parseArgs(nil)
+	if err != nil || parsed.addr != DefaultAddr {
+		t.Errorf("padrão inesperado: %+v, %v", parsed, err)
+	}
+	if _, err := parseArgs([]string{"--addr", ":8099"}); err == nil {
+		t.Error("esperava erro ao escutar em todas as interfaces sem token")
+	}
+	if _, err := parseArgs([]string{"--addr", ":8099", "--token", "s"}); err != nil {
+		t.Errorf("erro inesperado com token: %v", err)
+	}
+	if _, err := parseArgs([]string{"--addr", "localhost:9000"}); err != nil {
+		t.Errorf("erro inesperado para localhost: %v", err)
+	}
+	if _, err := parseArgs([]string{"--timeout", "x"}); err == nil {
+		t.Error("esperava erro para --timeout inválido")
+	}
+}
@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// clipboardTimeout limita o tempo de leitura da área de transferência
+const clipboardTimeout = 5 * time.Second
+
+// ErrNoClipboardTool indica que nenhuma ferramenta de leitura da área de transferência foi encontrada
+var ErrNoClipboardTool = errors.New("nenhuma ferramenta de área de transferência encontrada")
+
+// clipboardCommands lista, em ordem de preferência, os comandos que leem a área de transferência no sistema
+func clipboardCommands(goos string, wayland bool) [][]string {
+	switch goos {
+	case "darwin":
+		return [][]string{{"pbpaste"}}
+	case "windows":
+		return [][]string{{"powershell.exe", "-NoProfile", "-Command", "Get-Clipboard -Raw"}}
+	default:
+		commands := [][]string{{"xclip", "-selection", "clipboard", "-o"}, {"xsel", "--clipboard", "--output"}}
+		if wayland {
+			commands = append([][]string{{"wl-paste", "--no-newline"}}, commands...)
+		}
+		return commands
+	}
+}
+
+// ReadClipboard retorna o texto da área de transferência usando a primeira ferramenta instalada
+// (pbpaste, wl-paste, xclip, xsel ou PowerShell). Retorna ErrNoClipboardTool se nenhuma existir.
+func ReadClipboard() (string, error) {
+	for _, command := range clipboardCommands(runtime.GOOS, os.Getenv("WAYLAND_DISPLAY") != "") {
+		path, err := exec.LookPath(command[0])
+		if err != nil {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), clipboardTimeout)
+		output, err := exec.CommandContext(ctx, path, command[1:]...).Output()
+		cancel()
+		if err != nil {
+			return "", fmt.Errorf("erro ao ler a área de transferência com %s: %w", command[0], err)
+		}
+		return strings.ReplaceAll(string(output), "\r\n", "\n"), nil
+	}
+	return "", ErrNoClipboardTool
+}
@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// dockerInspectInfo são os campos de 'docker inspect' úteis para depuração. As variáveis de ambiente
+// do contêiner são omitidas de propósito, pois costumam conter segredos.
+type dockerInspectInfo struct {
+	Name         string
+	Created      string
+	RestartCount int
+	State        struct {
+		Status     string
+		Running    bool
+		Restarting bool
+		OOMKilled  bool
+		ExitCode   int
+		Error      string
+		StartedAt  string
+		FinishedAt string
+		Health     *struct {
+			Status        string
+			FailingStreak int
+		} `json:",omitempty"`
+	}
+	Config struct {
+		Image      string
+		Cmd        []string
+		Entrypoint []string
+		WorkingDir string
+		User       string
+	}
+	HostConfig struct {
+		RestartPolicy struct {
+			Name              string
+			MaximumRetryCount int
+		}
+		Memory       int64
+		NanoCpus     int64
+		PortBindings map[string][]struct {
+			HostIP   string `json:"HostIp"`
+			HostPort string
+		}
+	}
+	Mounts []struct {
+		Type        string
+		Source      string
+		Destination string
+		RW          bool
+	}
+}
+
+// runDocker executa o docker CLI e converte as falhas mais comuns em mensagens claras
+func runDocker(ctx context.Context, container string, args ...string) (string, error) {
+	output, err := exec.CommandContext(ctx, "docker", args...).CombinedOutput()
+	if err != nil {
+		return "", dockerError(container, string(output), err)
+	}
+	return string(output), nil
+}
+
+// dockerError identifica o docker ausente, o daemon inacessível e o contêiner inexistente
+func dockerError(container, output string, err error) error {
+	lower := strings.ToLower(output)
+	switch {
+	case errors.Is(err, exec.ErrNotFound):
+		return fmt.Errorf("docker não encontrado no PATH")
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("o docker não respondeu a tempo")
+	case strings.Contains(lower, "cannot connect to the docker daemon") || strings.Contains(lower, "error during connect") ||
+		strings.Contains(lower, "is the docker daemon running"):
+		return fmt.Errorf("o daemon do Docker não está acessível; verifique se ele está em execução")
+	case strings.Contains(lower, "no such container") || strings.Contains(lower, "no such object"):
+		return fmt.Errorf("contêiner %s não encontrado (use 'docker ps -a' para listar os contêineres)", container)
+	case strings.Contains(lower, "permission denied"):
+		return fmt.Errorf("sem permissão para acessar o daemon do Docker")
+	case strings.TrimSpace(output) != "":
+		return fmt.Errorf("erro do docker: %s", strings.TrimSpace(output))
+	default:
+		return fmt.Errorf("erro ao executar o docker: %w", err)
+	}
+}
+
+// DockerLogs retorna as últimas linhas do log do contêiner (stdout e stderr), opcionalmente apenas as
+// geradas no período de since (ex: 10m)
+func DockerLogs(ctx context.Context, container string, tail int, since string) (string, error) {
+	args := []string{"logs", "--tail", strconv.Itoa(tail)}
+	if since != "" {
+		args = append(args, "--since", since)
+	}
+	return runDocker(ctx, container, append(args, "--", container)...)
+}
+
+// DockerInspect retorna um resumo em JSON do estado e da configuração do contêiner
+func DockerInspect(ctx context.Context, container string) (string, error) {
+	output, err := runDocker(ctx, container, "inspect", "--type", "container", "--", container)
+	if err != nil {
+		return "", err
+	}
+	return summarizeDockerInspect(output)
+}
+
+// summarizeDockerInspect reduz a saída de 'docker inspect' aos campos de dockerInspectInfo
+func summarizeDockerInspect(output string) (string, error) {
+	var infos []dockerInspectInfo
+	if err := json.Unmarshal([]byte(output), &infos); err != nil || len(infos) == 0 {
+		return "", fmt.Errorf("saída inesperada do docker inspect")
+	}
+	summary, err := json.MarshalIndent(infos[0], "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("erro ao resumir o docker inspect: %w", err)
+	}
+	return string(summary), nil
+}
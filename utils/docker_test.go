@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestDockerError(t *testing.T) {
+	failed := errors.New("exit status 1")
+	tests := []struct {
+		output   string
+		err      error
+		expected string
+	}{
+		{"", &exec.Error{Name: "docker", Err: exec.ErrNotFound}, "não encontrado no PATH"},
+		{"Cannot connect to the Docker daemon at unix:///var/run/docker.sock. Is the docker daemon running?", failed, "daemon do Docker não está acessível"},
+		{"Error response from daemon: No such container: api", failed, "contêiner api não encontrado"},
+		{"Error: No such object: api", failed, "contêiner api não encontrado"},
+		{"algo inesperado", failed, "erro do docker: algo inesperado"},
+	}
+	for _, tt := range tests {
+		if err := dockerError("api", tt.output, tt.err); !strings.Contains(err.Error(), tt.expected) {
+			t.Errorf("Para %q, esperado %q, obtido %q", tt.output, tt.expected, err)
+		}
+	}
+}
+
+func TestSummarizeDockerInspect(t *testing.T) {
+	output := `[{"Name":"/api","RestartCount":3,"State":{"Status":"restarting","OOMKilled":true,"ExitCode":137},
+		"Config":{"Image":"api:1.2","Env":["DB_PASSWORD=segredo"],"Cmd":["./api"]}}]`
+	summary, err := summarizeDockerInspect(output)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if strings.Contains(summary, "segredo") {
+		t.Errorf("O resumo não deveria incluir as variáveis de ambiente:\n%s", summary)
+	}
+	for _, expected := range []string{`"OOMKilled": true`, `"ExitCode": 137`, `"RestartCount": 3`, `"Image": "api:1.2"`} {
+		if !strings.Contains(summary, expected) {
+			t.Errorf("Resumo sem %s:\n%s", expected, summary)
+		}
+	}
+	if _, err := summarizeDockerInspect("[]"); err == nil {
+		t.Error("Esperado erro para saída vazia")
+	}
+}
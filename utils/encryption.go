@@ -0,0 +1,146 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	encryptionSaltSize   = 16
+	encryptionIterations = 200000
+	encryptionKeySize    = 32
+)
+
+// encryptedFileMagic identifica os arquivos gravados por EncryptData
+var encryptedFileMagic = []byte("CHATCLI-ENC1\n")
+
+// ErrEncryptionKeyMissing indica que o arquivo está criptografado e CHATCLI_ENCRYPTION_KEY não foi definida
+var ErrEncryptionKeyMissing = errors.New("o arquivo está criptografado; defina CHATCLI_ENCRYPTION_KEY para lê-lo")
+
+// ErrEncryptionKeyWrong indica que a chave não corresponde à usada para criptografar o arquivo
+var ErrEncryptionKeyWrong = errors.New("não foi possível descriptografar o arquivo: CHATCLI_ENCRYPTION_KEY incorreta ou arquivo corrompido")
+
+// IsEncrypted indica se os dados foram gravados por EncryptData
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, encryptedFileMagic)
+}
+
+// EncryptData criptografa os dados com AES-256-GCM usando uma chave derivada da senha (PBKDF2-HMAC-SHA256
+// com sal aleatório). O resultado contém o cabeçalho, o sal, o nonce e o texto cifrado.
+func EncryptData(plaintext []byte, passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, ErrEncryptionKeyMissing
+	}
+	salt := make([]byte, encryptionSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("erro ao gerar o sal: %w", err)
+	}
+	gcm, err := newGCM(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("erro ao gerar o nonce: %w", err)
+	}
+
+	out := append(append(append([]byte{}, encryptedFileMagic...), salt...), nonce...)
+	return gcm.Seal(out, nonce, plaintext, encryptedFileMagic), nil
+}
+
+// DecryptData descriptografa dados gravados por EncryptData
+func DecryptData(data []byte, passphrase string) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return nil, fmt.Errorf("os dados não estão criptografados")
+	}
+	if passphrase == "" {
+		return nil, ErrEncryptionKeyMissing
+	}
+	body := data[len(encryptedFileMagic):]
+	if len(body) < encryptionSaltSize {
+		return nil, ErrEncryptionKeyWrong
+	}
+	salt, body := body[:encryptionSaltSize], body[encryptionSaltSize:]
+	gcm, err := newGCM(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(body) < gcm.NonceSize() {
+		return nil, ErrEncryptionKeyWrong
+	}
+	nonce, ciphertext := body[:gcm.NonceSize()], body[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, encryptedFileMagic)
+	if err != nil {
+		return nil, ErrEncryptionKeyWrong
+	}
+	return plaintext, nil
+}
+
+// WriteFileAtomic grava o arquivo por meio de um temporário no mesmo diretório seguido de rename,
+// de modo que nunca exista uma versão parcial (ou em texto puro) ao lado da definitiva
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func newGCM(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key := pbkdf2SHA256([]byte(passphrase), salt, encryptionIterations, encryptionKeySize)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao inicializar a criptografia: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// pbkdf2SHA256 implementa a derivação de chave PBKDF2 (RFC 8018) com HMAC-SHA256
+func pbkdf2SHA256(password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	hashLen := prf.Size()
+	blocks := (keyLen + hashLen - 1) / hashLen
+
+	var key []byte
+	buf := make([]byte, 4)
+	for block := 1; block <= blocks; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		binary.BigEndian.PutUint32(buf, uint32(block))
+		prf.Write(buf)
+		u := prf.Sum(nil)
+		t := append([]byte(nil), u...)
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		key = append(key, t...)
+	}
+	return key[:keyLen]
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestEncryptData(t *testing.T) {
+	plaintext := []byte(`[{"id":1,"text":"segredo"}]`)
+	data, err := EncryptData(plaintext, "senha")
+	if err != nil {
+		t.Fatalf("Erro ao criptografar: %v", err)
+	}
+	if !IsEncrypted(data) || bytes.Contains(data, []byte("segredo")) {
+		t.Fatal("Os dados deveriam estar criptografados")
+	}
+
+	decrypted, err := DecryptData(data, "senha")
+	if err != nil || !bytes.Equal(decrypted, plaintext) {
+		t.Fatalf("Esperado recuperar o texto original, obtido %q (erro: %v)", decrypted, err)
+	}
+	if _, err := DecryptData(data, "outra"); !errors.Is(err, ErrEncryptionKeyWrong) {
+		t.Errorf("Esperado ErrEncryptionKeyWrong com a chave incorreta, obtido %v", err)
+	}
+	if _, err := DecryptData(data, ""); !errors.Is(err, ErrEncryptionKeyMissing) {
+		t.Errorf("Esperado ErrEncryptionKeyMissing sem chave, obtido %v", err)
+	}
+	if _, err := EncryptData(plaintext, ""); !errors.Is(err, ErrEncryptionKeyMissing) {
+		t.Errorf("Esperado ErrEncryptionKeyMissing ao criptografar sem chave, obtido %v", err)
+	}
+}
+
+func TestPBKDF2SHA256(t *testing.T) {
+	// Vetor de teste da RFC 7914, seção 11
+	got := hex.EncodeToString(pbkdf2SHA256([]byte("passwd"), []byte("salt"), 1, 64))
+	want := "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783"
+	if got != want {
+		t.Errorf("PBKDF2 incorreto:\n obtido   %s\n esperado %s", got, want)
+	}
+}
@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// ReadFileHead lê no máximo maxSize bytes do início do arquivo, sem carregá-lo inteiro na memória.
+// Retorna o conteúdo, o tamanho total do arquivo e se o conteúdo foi truncado.
+func ReadFileHead(filePath string, maxSize int64) (string, int64, bool, error) {
+	absPath, info, err := statRegularFile(filePath)
+	if err != nil {
+		return "", 0, false, err
+	}
+
+	file, err := os.Open(absPath)
+	if err != nil {
+		return "", 0, false, fmt.Errorf("erro ao abrir o arquivo: %w", err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxSize))
+	if err != nil {
+		return "", 0, false, fmt.Errorf("erro ao ler o arquivo: %w", err)
+	}
+	truncated := info.Size() > int64(len(data))
+	if truncated {
+		data = trimPartialRune(data)
+	}
+	return strings.ReplaceAll(string(data), "\x00", ""), info.Size(), truncated, nil
+}
+
+// ReadFileChunks lê o arquivo em partes de até chunkSize bytes, quebrando preferencialmente em fins
+// de linha, e para após maxChunks partes. A memória usada fica limitada a chunkSize*maxChunks bytes,
+// qualquer que seja o tamanho do arquivo. Retorna as partes e se o arquivo foi lido apenas em parte.
+func ReadFileChunks(filePath string, chunkSize, maxChunks int) ([]string, bool, error) {
+	absPath, _, err := statRegularFile(filePath)
+	if err != nil {
+		return nil, false, err
+	}
+
+	file, err := os.Open(absPath)
+	if err != nil {
+		return nil, false, fmt.Errorf("erro ao abrir o arquivo: %w", err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	var chunks []string
+	var current []byte
+	flush := func() {
+		if len(current) > 0 {
+			chunks = append(chunks, strings.ReplaceAll(string(current), "\x00", ""))
+			current = nil
+		}
+	}
+
+	for {
+		line, err := reader.ReadSlice('\n')
+		// Linhas maiores que o buffer do leitor chegam em pedaços, tratados como linhas comuns
+		if err != nil && !errors.Is(err, bufio.ErrBufferFull) && !errors.Is(err, io.EOF) {
+			return nil, false, fmt.Errorf("erro ao ler o arquivo: %w", err)
+		}
+		for len(line) > 0 {
+			if len(current)+len(line) > chunkSize && len(current) > 0 {
+				flush()
+				if len(chunks) == maxChunks {
+					return chunks, true, nil
+				}
+			}
+			n := len(line)
+			if n > chunkSize {
+				n = len(trimPartialRune(line[:chunkSize]))
+				if n == 0 {
+					n = chunkSize
+				}
+			}
+			current = append(current, line[:n]...)
+			line = line[n:]
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+	}
+	flush()
+	return chunks, false, nil
+}
+
+// trimPartialRune remove do fim um caractere UTF-8 cortado ao meio
+func trimPartialRune(data []byte) []byte {
+	for i := 0; i < utf8.UTFMax-1 && len(data) > 0; i++ {
+		r, size := utf8.DecodeLastRune(data)
+		if r != utf8.RuneError || size != 1 {
+			break
+		}
+		data = data[:len(data)-1]
+	}
+	return data
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeLargeFile cria um arquivo sintético com o número de linhas informado
+func writeLargeFile(t *testing.T, lines int) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "grande.log")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := bufio.NewWriter(file)
+	for i := 0; i < lines; i++ {
+		w.WriteString("linha de log com conteúdo repetido: ação concluída\n")
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+	return path
+}
+
+func TestReadFileHead(t *testing.T) {
+	path := writeLargeFile(t, 200000) // cerca de 10 MB
+
+	content, size, truncated, err := ReadFileHead(path, 1000)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if !truncated || size < 10*1000*1000 || len(content) > 1000 {
+		t.Errorf("Esperado conteúdo truncado em 1000 bytes, obtido %d bytes de %d (truncado: %v)", len(content), size, truncated)
+	}
+	if !strings.HasPrefix(content, "linha de log") || strings.ContainsRune(content, '�') {
+		t.Errorf("Conteúdo inesperado: %q", content[:40])
+	}
+
+	if _, _, truncated, _ := ReadFileHead("path.go", 1<<20); truncated {
+		t.Error("Arquivo pequeno não deveria ser truncado")
+	}
+}
+
+func TestReadFileChunks(t *testing.T) {
+	path := writeLargeFile(t, 200000)
+
+	chunks, truncated, err := ReadFileChunks(path, 4096, 5)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if !truncated || len(chunks) != 5 {
+		t.Fatalf("Esperado 5 partes e truncamento, obtido %d (truncado: %v)", len(chunks), truncated)
+	}
+	for i, chunk := range chunks {
+		if len(chunk) > 4096 || !strings.HasSuffix(chunk, "\n") {
+			t.Errorf("Parte %d inválida: %d bytes", i, len(chunk))
+		}
+	}
+
+	// Uma linha maior que a parte é dividida sem cortar caracteres ao meio
+	long := filepath.Join(t.TempDir(), "longa.txt")
+	os.WriteFile(long, []byte(strings.Repeat("ção", 3000)), 0600)
+	chunks, truncated, err = ReadFileChunks(long, 1000, 100)
+	if err != nil || truncated || strings.Join(chunks, "") != strings.Repeat("ção", 3000) {
+		t.Errorf("Divisão inesperada da linha longa: %d partes (truncado: %v, erro: %v)", len(chunks), truncated, err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os/exec"
 	"strings"
+	"time"
 )
 
 // GetGitInfo retorna informações detalhadas sobre o repositório Git atual
@@ -125,12 +126,122 @@ func GetFileHistory(filepath string) (string, error) {
 	return string(output), nil
 }
 
-// Função auxiliar para obter blame de um arquivo
-func GetFileBlame(filepath string) (string, error) {
-	cmd := exec.Command("git", "blame", filepath)
+// MaxBlameLines é o número máximo de linhas de blame injetadas no contexto
+const MaxBlameLines = 200
+
+// uncommittedHash é o hash usado pelo git blame para linhas ainda não commitadas
+const uncommittedHash = "0000000000000000000000000000000000000000"
+
+// BlameLine é uma linha do git blame com o autor, o commit e a data da última alteração
+type BlameLine struct {
+	Number  int
+	Commit  string
+	Author  string
+	Date    string
+	Summary string
+	Content string
+}
+
+// GetFileBlame retorna o blame das linhas start a end do arquivo (end 0 indica até o fim),
+// limitado a MaxBlameLines linhas
+func GetFileBlame(filepath string, start, end int) (string, error) {
+	if err := exec.Command("git", "rev-parse", "--is-inside-work-tree").Run(); err != nil {
+		return "", fmt.Errorf("não é um repositório Git")
+	}
+	if start < 1 {
+		start = 1
+	}
+	if end == 0 || end-start+1 > MaxBlameLines {
+		end = start + MaxBlameLines - 1
+	}
+
+	cmd := exec.Command("git", "blame", "--line-porcelain", "-L", fmt.Sprintf("%d,%d", start, end), "--", filepath)
 	output, err := cmd.Output()
 	if err != nil {
+		// Intervalos além do fim do arquivo são limitados ao que existe
+		if exitErr, ok := err.(*exec.ExitError); ok && strings.Contains(string(exitErr.Stderr), "has only") {
+			cmd = exec.Command("git", "blame", "--line-porcelain", "-L", fmt.Sprintf("%d,", start), "--", filepath)
+			output, err = cmd.Output()
+		}
+	}
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("erro ao obter blame do arquivo %s: %s", filepath, strings.TrimSpace(string(exitErr.Stderr)))
+		}
 		return "", fmt.Errorf("erro ao obter blame do arquivo %s: %w", filepath, err)
 	}
-	return string(output), nil
+
+	lines := ParseBlamePorcelain(string(output))
+	if len(lines) > MaxBlameLines {
+		lines = lines[:MaxBlameLines]
+	}
+	return FormatBlame(lines), nil
+}
+
+// ParseBlamePorcelain interpreta a saída de git blame --line-porcelain
+func ParseBlamePorcelain(output string) []BlameLine {
+	var lines []BlameLine
+	var current BlameLine
+	expectHeader := true
+
+	for _, raw := range strings.Split(output, "\n") {
+		if expectHeader {
+			fields := strings.Fields(raw)
+			if len(fields) < 3 {
+				continue
+			}
+			current = BlameLine{Commit: fields[0]}
+			fmt.Sscanf(fields[2], "%d", &current.Number)
+			expectHeader = false
+			continue
+		}
+		switch {
+		case strings.HasPrefix(raw, "\t"):
+			current.Content = strings.TrimPrefix(raw, "\t")
+			lines = append(lines, current)
+			expectHeader = true
+		case strings.HasPrefix(raw, "author "):
+			current.Author = strings.TrimPrefix(raw, "author ")
+		case strings.HasPrefix(raw, "author-time "):
+			var ts int64
+			fmt.Sscanf(strings.TrimPrefix(raw, "author-time "), "%d", &ts)
+			current.Date = time.Unix(ts, 0).UTC().Format("2006-01-02")
+		case strings.HasPrefix(raw, "summary "):
+			current.Summary = strings.TrimPrefix(raw, "summary ")
+		}
+	}
+	return lines
+}
+
+// FormatBlame formata as linhas do blame para o contexto, seguidas da mensagem de cada commit citado
+func FormatBlame(lines []BlameLine) string {
+	var b strings.Builder
+	var commits []BlameLine
+	seen := make(map[string]bool)
+
+	for _, l := range lines {
+		if l.Commit == uncommittedHash {
+			fmt.Fprintf(&b, "%5d  %-8s %-10s %-20s | %s\n", l.Number, "-", "-", "(não commitado)", l.Content)
+			continue
+		}
+		fmt.Fprintf(&b, "%5d  %-8s %-10s %-20s | %s\n", l.Number, l.Commit[:minInt(8, len(l.Commit))], l.Date, l.Author, l.Content)
+		if !seen[l.Commit] {
+			seen[l.Commit] = true
+			commits = append(commits, l)
+		}
+	}
+	if len(commits) > 0 {
+		b.WriteString("\nCommits:\n")
+		for _, c := range commits {
+			fmt.Fprintf(&b, "%s %s (%s, %s)\n", c.Commit[:minInt(8, len(c.Commit))], c.Summary, c.Author, c.Date)
+		}
+	}
+	return b.String()
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
 }
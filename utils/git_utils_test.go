@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -10,3 +11,34 @@ func TestGetGitInfo(t *testing.T) {
 		t.Logf("Erro esperado se não estiver em um repositório Git: %v", err)
 	}
 }
+
+func TestParseBlamePorcelain(t *testing.T) {
+	output := "abcdef1234567890abcdef1234567890abcdef12 40 40 1\n" +
+		"author Fulana\n" +
+		"author-time 1714521600\n" +
+		"summary Corrige o parser\n" +
+		"filename main.go\n" +
+		"\tfunc main() {\n" +
+		"0000000000000000000000000000000000000000 41 41 1\n" +
+		"author Not Committed Yet\n" +
+		"author-time 1714521600\n" +
+		"summary Version of main.go from main.go\n" +
+		"filename main.go\n" +
+		"\t\tnovo()\n"
+
+	lines := ParseBlamePorcelain(output)
+	if len(lines) != 2 {
+		t.Fatalf("Esperado 2 linhas, obtido %d", len(lines))
+	}
+	if lines[0].Number != 40 || lines[0].Author != "Fulana" || lines[0].Date != "2024-05-01" || lines[0].Content != "func main() {" {
+		t.Errorf("Primeira linha inesperada: %+v", lines[0])
+	}
+
+	formatted := FormatBlame(lines)
+	if !strings.Contains(formatted, "(não commitado)") || !strings.Contains(formatted, "abcdef12 Corrige o parser (Fulana, 2024-05-01)") {
+		t.Errorf("Blame formatado inesperado:\n%s", formatted)
+	}
+	if strings.Contains(formatted, "Version of main.go") {
+		t.Error("Linhas não commitadas não deveriam aparecer na lista de commits")
+	}
+}
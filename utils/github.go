@@ -0,0 +1,234 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultGitHubAPIURL é o endereço da API REST do GitHub
+	DefaultGitHubAPIURL = "https://api.github.com"
+	// maxGitHubDiffSize limita o tamanho do diff lido de um pull request
+	maxGitHubDiffSize = 1024 * 1024
+)
+
+var gitHubRefPattern = regexp.MustCompile(`^([A-Za-z0-9_.-]+)/([A-Za-z0-9_.-]+)#([0-9]+)$`)
+
+// GitHubRef identifica uma issue ou pull request no formato owner/repo#123
+type GitHubRef struct {
+	Owner  string
+	Repo   string
+	Number int
+}
+
+// String retorna a referência no formato owner/repo#123
+func (r GitHubRef) String() string {
+	return fmt.Sprintf("%s/%s#%d", r.Owner, r.Repo, r.Number)
+}
+
+// ParseGitHubRef interpreta uma referência no formato owner/repo#123
+func ParseGitHubRef(s string) (GitHubRef, error) {
+	match := gitHubRefPattern.FindStringSubmatch(s)
+	if match == nil {
+		return GitHubRef{}, fmt.Errorf("referência inválida: %q (use owner/repo#123)", s)
+	}
+	number, err := strconv.Atoi(match[3])
+	if err != nil || number < 1 {
+		return GitHubRef{}, fmt.Errorf("número inválido em %q", s)
+	}
+	return GitHubRef{Owner: match[1], Repo: match[2], Number: number}, nil
+}
+
+// GitHubComment é um comentário de issue ou pull request
+type GitHubComment struct {
+	Author    string
+	Body      string
+	CreatedAt time.Time
+}
+
+// GitHubIssue reúne os dados de uma issue ou pull request usados como contexto
+type GitHubIssue struct {
+	Ref           GitHubRef
+	Title         string
+	Body          string
+	State         string
+	Author        string
+	URL           string
+	IsPullRequest bool
+	TotalComments int
+	Comments      []GitHubComment
+	Diff          string
+}
+
+// GitHubClient consulta issues e pull requests na API do GitHub. Sem token, apenas repositórios
+// públicos são acessíveis e o limite de requisições é bem menor.
+type GitHubClient struct {
+	httpClient *http.Client
+	baseURL    string
+	token      string
+}
+
+// NewGitHubClient cria o cliente da API do GitHub; um baseURL vazio usa DefaultGitHubAPIURL
+func NewGitHubClient(httpClient *http.Client, baseURL, token string) *GitHubClient {
+	if baseURL == "" {
+		baseURL = DefaultGitHubAPIURL
+	}
+	return &GitHubClient{httpClient: httpClient, baseURL: strings.TrimSuffix(baseURL, "/"), token: token}
+}
+
+type gitHubUser struct {
+	Login string `json:"login"`
+}
+
+type gitHubIssueResponse struct {
+	Title       string          `json:"title"`
+	Body        string          `json:"body"`
+	State       string          `json:"state"`
+	HTMLURL     string          `json:"html_url"`
+	User        gitHubUser      `json:"user"`
+	Comments    int             `json:"comments"`
+	PullRequest json.RawMessage `json:"pull_request"`
+}
+
+type gitHubCommentResponse struct {
+	Body      string     `json:"body"`
+	User      gitHubUser `json:"user"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// FetchIssue obtém a issue ou pull request com os últimos comentários (até comments) e, para pull
+// requests, o diff, se solicitado
+func (c *GitHubClient) FetchIssue(ctx context.Context, ref GitHubRef, comments int, withDiff bool) (*GitHubIssue, error) {
+	var resp gitHubIssueResponse
+	path := fmt.Sprintf("/repos/%s/%s/issues/%d", ref.Owner, ref.Repo, ref.Number)
+	if err := c.getJSON(ctx, path, &resp); err != nil {
+		return nil, err
+	}
+
+	issue := &GitHubIssue{
+		Ref:           ref,
+		Title:         resp.Title,
+		Body:          resp.Body,
+		State:         resp.State,
+		Author:        resp.User.Login,
+		URL:           resp.HTMLURL,
+		IsPullRequest: len(resp.PullRequest) > 0 && string(resp.PullRequest) != "null",
+		TotalComments: resp.Comments,
+	}
+
+	if comments > 0 && resp.Comments > 0 {
+		latest, err := c.latestComments(ctx, path, resp.Comments, comments)
+		if err != nil {
+			return nil, err
+		}
+		issue.Comments = latest
+	}
+
+	if withDiff && issue.IsPullRequest {
+		diff, err := c.get(ctx, fmt.Sprintf("/repos/%s/%s/pulls/%d", ref.Owner, ref.Repo, ref.Number), "application/vnd.github.diff")
+		if err != nil {
+			return nil, err
+		}
+		issue.Diff = string(diff)
+	}
+	return issue, nil
+}
+
+// latestComments busca os últimos n comentários. A API os retorna do mais antigo para o mais recente,
+// então são lidas a última página e, se ela não tiver n comentários, a anterior.
+func (c *GitHubClient) latestComments(ctx context.Context, issuePath string, total, n int) ([]GitHubComment, error) {
+	if n > 100 {
+		n = 100
+	}
+	lastPage := (total + n - 1) / n
+	var responses []gitHubCommentResponse
+	for page := lastPage - 1; page <= lastPage; page++ {
+		if page < 1 {
+			continue
+		}
+		var pageComments []gitHubCommentResponse
+		if err := c.getJSON(ctx, fmt.Sprintf("%s/comments?per_page=%d&page=%d", issuePath, n, page), &pageComments); err != nil {
+			return nil, err
+		}
+		responses = append(responses, pageComments...)
+	}
+	if len(responses) > n {
+		responses = responses[len(responses)-n:]
+	}
+
+	comments := make([]GitHubComment, len(responses))
+	for i, r := range responses {
+		comments[i] = GitHubComment{Author: r.User.Login, Body: r.Body, CreatedAt: r.CreatedAt}
+	}
+	return comments, nil
+}
+
+func (c *GitHubClient) getJSON(ctx context.Context, path string, v interface{}) error {
+	body, err := c.get(ctx, path, "application/vnd.github+json")
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("resposta inválida da API do GitHub: %w", err)
+	}
+	return nil
+}
+
+func (c *GitHubClient) get(ctx context.Context, path, accept string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao acessar a API do GitHub: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxGitHubDiffSize))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler a resposta da API do GitHub: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.statusError(resp)
+	}
+	return body, nil
+}
+
+// statusError traduz as respostas de erro da API, sugerindo GITHUB_TOKEN quando a falta de
+// autenticação é a causa provável
+func (c *GitHubClient) statusError(resp *http.Response) error {
+	switch {
+	case (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests) &&
+		resp.Header.Get("X-RateLimit-Remaining") == "0":
+		msg := "limite de requisições da API do GitHub atingido"
+		if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			msg += fmt.Sprintf("; tente novamente após %s", time.Unix(reset, 0).Format("15:04"))
+		}
+		if c.token == "" {
+			msg += " (defina GITHUB_TOKEN para um limite maior)"
+		}
+		return fmt.Errorf("%s", msg)
+	case resp.StatusCode == http.StatusUnauthorized:
+		return fmt.Errorf("GITHUB_TOKEN inválido ou expirado")
+	case resp.StatusCode == http.StatusNotFound && c.token == "":
+		return fmt.Errorf("não encontrado; se o repositório for privado, defina GITHUB_TOKEN")
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("não encontrado ou sem acesso com o GITHUB_TOKEN atual")
+	default:
+		return fmt.Errorf("a API do GitHub retornou o status %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestGitHubClient_FetchIssue(t *testing.T) {
+	var authHeader string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/octo/app/issues/7", func(w http.ResponseWriter, r *http.Request) {
+		authHeader = r.Header.Get("Authorization")
+		fmt.Fprint(w, `{"title": "Corrige o login", "body": "descrição", "state": "open", "html_url": "https://github.com/octo/app/pull/7",
+			"user": {"login": "ana"}, "comments": 7, "pull_request": {"url": "x"}}`)
+	})
+	mux.HandleFunc("/repos/octo/app/issues/7/comments", func(w http.ResponseWriter, r *http.Request) {
+		perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		var comments []map[string]interface{}
+		for i := (page-1)*perPage + 1; i <= page*perPage && i <= 7; i++ {
+			comments = append(comments, map[string]interface{}{"body": fmt.Sprintf("comentário %d", i), "user": map[string]string{"login": "bia"}})
+		}
+		json.NewEncoder(w).Encode(comments)
+	})
+	mux.HandleFunc("/repos/octo/app/pulls/7", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "application/vnd.github.diff" {
+			t.Errorf("Accept inesperado: %s", r.Header.Get("Accept"))
+		}
+		fmt.Fprint(w, "diff --git a/login.go b/login.go\n")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewGitHubClient(server.Client(), server.URL, "segredo")
+	issue, err := client.FetchIssue(context.Background(), GitHubRef{Owner: "octo", Repo: "app", Number: 7}, 3, true)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if authHeader != "Bearer segredo" {
+		t.Errorf("Cabeçalho Authorization inesperado: %q", authHeader)
+	}
+	if !issue.IsPullRequest || issue.Title != "Corrige o login" || issue.Author != "ana" {
+		t.Errorf("Issue inesperada: %+v", issue)
+	}
+	if len(issue.Comments) != 3 || issue.Comments[0].Body != "comentário 5" || issue.Comments[2].Body != "comentário 7" {
+		t.Errorf("Esperado os 3 últimos comentários, obtido %+v", issue.Comments)
+	}
+	if !strings.HasPrefix(issue.Diff, "diff --git") {
+		t.Errorf("Diff inesperado: %q", issue.Diff)
+	}
+}
+
+func TestGitHubClient_errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/1") {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewGitHubClient(server.Client(), server.URL, "")
+	_, err := client.FetchIssue(context.Background(), GitHubRef{Owner: "o", Repo: "r", Number: 1}, 0, false)
+	if err == nil || !strings.Contains(err.Error(), "limite de requisições") || !strings.Contains(err.Error(), "GITHUB_TOKEN") {
+		t.Errorf("Esperado erro de limite de requisições sugerindo GITHUB_TOKEN, obtido %v", err)
+	}
+	_, err = client.FetchIssue(context.Background(), GitHubRef{Owner: "o", Repo: "privado", Number: 2}, 0, false)
+	if err == nil || !strings.Contains(err.Error(), "privado") {
+		t.Errorf("Esperado erro sugerindo GITHUB_TOKEN para repositórios privados, obtido %v", err)
+	}
+}
+
+func TestParseGitHubRef(t *testing.T) {
+	ref, err := ParseGitHubRef("diillson/chatcli#42")
+	if err != nil || ref.Owner != "diillson" || ref.Repo != "chatcli" || ref.Number != 42 {
+		t.Errorf("Referência inesperada: %+v (erro: %v)", ref, err)
+	}
+	for _, s := range []string{"chatcli#42", "diillson/chatcli", "diillson/chatcli#0", "a/b#x"} {
+		if _, err := ParseGitHubRef(s); err == nil {
+			t.Errorf("Esperado erro para %q", s)
+		}
+	}
+}
@@ -2,19 +2,292 @@
 package utils
 
 import (
-	"go.uber.org/zap"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultConnectTimeout = 30 * time.Second
+	// defaultIdleTimeout é o prazo de inatividade dos clientes dos provedores sem CHATCLI_IDLE_TIMEOUT
+	defaultIdleTimeout = 5 * time.Minute
+	// maxIdleConnsPerHost é a quantidade de conexões ociosas mantidas por host para reuso
+	maxIdleConnsPerHost = 10
 )
 
-// NewHTTPClient cria um cliente HTTP com LoggingTransport e timeout configurado
+// sharedTransports guarda os transportes base já criados, por configuração de conexão, para que
+// todos os clientes HTTP da sessão reutilizem o mesmo pool de conexões keep-alive
+var (
+	sharedTransportsMu sync.Mutex
+	sharedTransports   = make(map[transportKey]*http.Transport)
+)
+
+// transportKey identifica a configuração de um transporte base
+type transportKey struct {
+	connectTimeout time.Duration
+	caBundle       string
+}
+
+// NewHTTPClient cria um cliente HTTP para chamadas curtas (token, GitHub, webhooks, diagnóstico), com
+// LoggingTransport e o timeout informado valendo para a requisição inteira, inclusive a leitura da
+// resposta. CHATCLI_IDLE_TIMEOUT não se aplica a ele; para os provedores, use NewProviderHTTPClient.
+// O tempo de conexão pode ser sobrescrito por CHATCLI_CONNECT_TIMEOUT. O proxy segue
+// HTTPS_PROXY/HTTP_PROXY/NO_PROXY e CHATCLI_CA_BUNDLE adiciona certificados de CA confiáveis.
+// Com CHATCLI_DEBUG_HTTP=1, os corpos sanitizados são registrados apenas no arquivo de log.
+// Os clientes criados com a mesma configuração de conexão compartilham o transporte base e, com
+// ele, as conexões abertas, de modo que as chamadas seguidas a um provedor não refazem o handshake.
 func NewHTTPClient(logger *zap.Logger, timeout time.Duration) *http.Client {
-	return &http.Client{
-		Transport: &LoggingTransport{
-			Logger:      logger,
-			Transport:   http.DefaultTransport,
-			MaxBodySize: 2048, // Defina o tamanho máximo do corpo (1KB, por exemplo)
-		},
-		Timeout: timeout,
+	httpClient := newHTTPClient(logger, timeout)
+	httpClient.Timeout = timeout
+	return httpClient
+}
+
+// NewProviderHTTPClient cria o cliente HTTP dos provedores de LLM, sem limite para a requisição inteira:
+// ela só é abortada se nenhum byte for recebido por CHATCLI_IDLE_TIMEOUT (padrão de 5 minutos), de modo
+// que respostas longas não sejam interrompidas enquanto continuarem chegando dados. As demais
+// configurações são as de NewHTTPClient.
+func NewProviderHTTPClient(logger *zap.Logger) *http.Client {
+	return newHTTPClient(logger, GetDurationFromEnv("CHATCLI_IDLE_TIMEOUT", defaultIdleTimeout, logger))
+}
+
+// newHTTPClient monta o cliente com o prazo de inatividade informado e sem timeout total
+func newHTTPClient(logger *zap.Logger, idleTimeout time.Duration) *http.Client {
+	connectTimeout := GetDurationFromEnv("CHATCLI_CONNECT_TIMEOUT", defaultConnectTimeout, logger)
+
+	transport := &LoggingTransport{
+		Logger:      logger,
+		Transport:   NewIdleTimeoutTransport(sharedTransport(connectTimeout, os.Getenv("CHATCLI_CA_BUNDLE"), logger), idleTimeout),
+		MaxBodySize: 2048, // Defina o tamanho máximo do corpo (1KB, por exemplo)
+	}
+	if debugHTTPEnabled() {
+		transport.DebugLogger = FileLogger(logger)
+	}
+
+	return &http.Client{Transport: transport}
+}
+
+// debugHTTPEnabled indica se CHATCLI_DEBUG_HTTP pede o registro dos corpos de requisições e respostas
+func debugHTTPEnabled() bool {
+	enabled, err := strconv.ParseBool(os.Getenv("CHATCLI_DEBUG_HTTP"))
+	return err == nil && enabled
+}
+
+// sharedTransport retorna o transporte base da configuração informada, criando-o na primeira vez.
+// Um CHATCLI_CA_BUNDLE inválido é registrado e o transporte usa apenas as CAs do sistema.
+func sharedTransport(connectTimeout time.Duration, caBundle string, logger *zap.Logger) *http.Transport {
+	sharedTransportsMu.Lock()
+	defer sharedTransportsMu.Unlock()
+
+	key := transportKey{connectTimeout: connectTimeout, caBundle: caBundle}
+	if transport, ok := sharedTransports[key]; ok {
+		return transport
+	}
+	transport := newBaseTransport(connectTimeout)
+	if caBundle != "" {
+		pool, err := LoadCABundle(caBundle)
+		if err != nil {
+			logger.Error("Erro ao carregar CHATCLI_CA_BUNDLE, usando apenas as CAs do sistema", zap.Error(err))
+		} else {
+			transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+		}
+	}
+	sharedTransports[key] = transport
+	return transport
+}
+
+// newBaseTransport cria o transporte HTTP com o timeout de conexão aplicado ao dial e ao handshake TLS
+func newBaseTransport(connectTimeout time.Duration) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+	transport.DialContext = (&net.Dialer{
+		Timeout:   connectTimeout,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.TLSHandshakeTimeout = connectTimeout
+	transport.MaxIdleConnsPerHost = maxIdleConnsPerHost
+	return transport
+}
+
+// ValidateBaseURL valida a URL base de um endpoint compatível com um provedor, removendo a barra final.
+// Uma URL vazia é válida e indica o endpoint padrão do provedor.
+func ValidateBaseURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("URL inválida %q: %w", raw, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("URL inválida %q: use http:// ou https:// seguido do host", raw)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return "", fmt.Errorf("URL inválida %q: a URL base não deve ter query string nem fragmento", raw)
+	}
+	return strings.TrimSuffix(raw, "/"), nil
+}
+
+// LoadCABundle lê um arquivo PEM com certificados de CA e os adiciona às CAs do sistema
+func LoadCABundle(path string) (*x509.CertPool, error) {
+	expanded, err := ExpandPath(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(expanded)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler o bundle de CA %s: %w", path, err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("nenhum certificado PEM válido encontrado em %s", path)
 	}
+	return pool, nil
+}
+
+// IsTLSVerificationError indica se o erro foi causado pela falha na verificação do certificado do servidor
+func IsTLSVerificationError(err error) bool {
+	var verifyErr *tls.CertificateVerificationError
+	var unknownAuthority x509.UnknownAuthorityError
+	var invalidCert x509.CertificateInvalidError
+	var hostnameErr x509.HostnameError
+	return errors.As(err, &verifyErr) || errors.As(err, &unknownAuthority) ||
+		errors.As(err, &invalidCert) || errors.As(err, &hostnameErr)
+}
+
+// GetDurationFromEnv lê uma duração de uma variável de ambiente. Aceita o formato do Go
+// ("90s", "2m") ou um número inteiro de segundos. Retorna o valor padrão se a variável
+// não estiver definida ou for inválida.
+func GetDurationFromEnv(key string, defaultValue time.Duration, logger *zap.Logger) time.Duration {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return defaultValue
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		return d
+	}
+	if logger != nil {
+		logger.Warn("Valor de duração inválido, usando padrão",
+			zap.String("variavel", key),
+			zap.String("valor", value),
+			zap.Duration("padrao", defaultValue),
+		)
+	}
+	return defaultValue
+}
+
+// IdleTimeoutTransport é um http.RoundTripper que aborta a requisição quando não há
+// atividade por mais tempo que IdleTimeout. O prazo é renovado a cada leitura do corpo
+// da resposta, permitindo respostas longas enquanto os dados continuarem chegando.
+type IdleTimeoutTransport struct {
+	Transport   http.RoundTripper
+	IdleTimeout time.Duration
+}
+
+// IdleTimeoutError é o erro das requisições abortadas por IdleTimeoutTransport. Implementa net.Error com
+// Timeout verdadeiro, para que a falha seja classificada como timeout e repetida como as demais.
+type IdleTimeoutError struct {
+	Idle time.Duration
+}
+
+func (e *IdleTimeoutError) Error() string {
+	return fmt.Sprintf("nenhum dado recebido em %s", e.Idle)
+}
+
+// Timeout implementa net.Error
+func (e *IdleTimeoutError) Timeout() bool { return true }
+
+// Temporary implementa net.Error
+func (e *IdleTimeoutError) Temporary() bool { return true }
+
+// NewIdleTimeoutTransport cria um IdleTimeoutTransport sobre o transporte informado
+func NewIdleTimeoutTransport(transport http.RoundTripper, idleTimeout time.Duration) *IdleTimeoutTransport {
+	return &IdleTimeoutTransport{Transport: transport, IdleTimeout: idleTimeout}
+}
+
+// RoundTrip implementa a interface http.RoundTripper
+func (t *IdleTimeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.IdleTimeout <= 0 {
+		return t.Transport.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithCancelCause(req.Context())
+	timer := time.AfterFunc(t.IdleTimeout, func() { cancel(&IdleTimeoutError{Idle: t.IdleTimeout}) })
+
+	resp, err := t.Transport.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		timer.Stop()
+		cancel(nil)
+		return resp, idleTimeoutCause(ctx, err)
+	}
+
+	timer.Reset(t.IdleTimeout)
+	resp.Body = &idleTimeoutBody{
+		ReadCloser: resp.Body,
+		ctx:        ctx,
+		timer:      timer,
+		timeout:    t.IdleTimeout,
+		cancel:     cancel,
+	}
+	return resp, nil
+}
+
+// idleTimeoutCause troca o erro de cancelamento pelo IdleTimeoutError quando o prazo de inatividade
+// foi a causa, em vez de context.Canceled
+func idleTimeoutCause(ctx context.Context, err error) error {
+	var idleErr *IdleTimeoutError
+	if errors.As(context.Cause(ctx), &idleErr) {
+		return idleErr
+	}
+	return err
+}
+
+// idleTimeoutBody renova o prazo de inatividade a cada leitura bem-sucedida
+type idleTimeoutBody struct {
+	io.ReadCloser
+	ctx     context.Context
+	timer   *time.Timer
+	timeout time.Duration
+	cancel  context.CancelCauseFunc
+	once    sync.Once
+}
+
+func (b *idleTimeoutBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		b.timer.Reset(b.timeout)
+	}
+	if err != nil && err != io.EOF {
+		err = idleTimeoutCause(b.ctx, err)
+	}
+	return n, err
+}
+
+func (b *idleTimeoutBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() {
+		b.timer.Stop()
+		b.cancel(nil)
+	})
+	return err
 }
@@ -1,7 +1,18 @@
 package utils
 
 import (
+	"encoding/pem"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"go.uber.org/zap"
 )
@@ -13,3 +24,182 @@ func TestNewHTTPClient(t *testing.T) {
 		t.Error("Cliente HTTP é nil")
 	}
 }
+
+func TestIdleTimeoutTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher := w.(http.Flusher)
+		if r.URL.Path == "/lento" {
+			time.Sleep(300 * time.Millisecond)
+			return
+		}
+		// Resposta longa, mas sempre ativa: o total excede o timeout de inatividade
+		for i := 0; i < 6; i++ {
+			w.Write([]byte("dados "))
+			flusher.Flush()
+			time.Sleep(50 * time.Millisecond)
+		}
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewIdleTimeoutTransport(http.DefaultTransport, 150*time.Millisecond)}
+
+	resp, err := client.Get(server.URL + "/stream")
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil || !strings.Contains(string(body), "dados dados") {
+		t.Errorf("Esperado corpo completo, obtido '%s' (%v)", string(body), err)
+	}
+
+	_, err = client.Get(server.URL + "/lento")
+	var idleErr *IdleTimeoutError
+	var netErr net.Error
+	if !errors.As(err, &idleErr) || !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Errorf("Esperado IdleTimeoutError com Timeout(), obtido %v", err)
+	}
+}
+
+func TestNewHTTPClientTimeouts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Envia dados continuamente por mais tempo que o timeout total dos clientes curtos
+		for i := 0; i < 8; i++ {
+			w.Write([]byte("dados "))
+			w.(http.Flusher).Flush()
+			time.Sleep(50 * time.Millisecond)
+		}
+	}))
+	defer server.Close()
+
+	// CHATCLI_IDLE_TIMEOUT não deve substituir o timeout dos clientes curtos
+	t.Setenv("CHATCLI_IDLE_TIMEOUT", "1m")
+	client := NewHTTPClient(zap.NewNop(), 150*time.Millisecond)
+	if client.Timeout != 150*time.Millisecond {
+		t.Errorf("Esperado timeout total de 150ms, obtido %s", client.Timeout)
+	}
+	if resp, err := client.Get(server.URL); err == nil {
+		_, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err == nil {
+			t.Error("Esperado timeout para a resposta que excede o limite total")
+		}
+	}
+
+	// O cliente dos provedores não tem limite total e segue CHATCLI_IDLE_TIMEOUT
+	t.Setenv("CHATCLI_IDLE_TIMEOUT", "150ms")
+	provider := NewProviderHTTPClient(zap.NewNop())
+	if provider.Timeout != 0 {
+		t.Errorf("O cliente dos provedores não deveria ter timeout total, obtido %s", provider.Timeout)
+	}
+	resp, err := provider.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil || strings.Count(string(body), "dados") != 8 {
+		t.Errorf("Esperado corpo completo, obtido '%s' (%v)", string(body), err)
+	}
+}
+
+func TestNewHTTPClientReusesConnections(t *testing.T) {
+	var mu sync.Mutex
+	newConns := 0
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			mu.Lock()
+			newConns++
+			mu.Unlock()
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	// Clientes diferentes com a mesma configuração, como os criados a cada /switch, usam o mesmo pool
+	for i := 0; i < 3; i++ {
+		client := NewHTTPClient(zap.NewNop(), time.Second)
+		for j := 0; j < 2; j++ {
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Fatalf("Erro inesperado: %v", err)
+			}
+			io.ReadAll(resp.Body)
+			resp.Body.Close()
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if newConns != 1 {
+		t.Errorf("Esperada uma única conexão reutilizada, obtidas %d", newConns)
+	}
+}
+
+func TestGetDurationFromEnv(t *testing.T) {
+	t.Setenv("CHATCLI_TEST_TIMEOUT", "45")
+	if d := GetDurationFromEnv("CHATCLI_TEST_TIMEOUT", time.Second, nil); d != 45*time.Second {
+		t.Errorf("Esperado 45s, obtido %s", d)
+	}
+	t.Setenv("CHATCLI_TEST_TIMEOUT", "2m")
+	if d := GetDurationFromEnv("CHATCLI_TEST_TIMEOUT", time.Second, nil); d != 2*time.Minute {
+		t.Errorf("Esperado 2m, obtido %s", d)
+	}
+	t.Setenv("CHATCLI_TEST_TIMEOUT", "abc")
+	if d := GetDurationFromEnv("CHATCLI_TEST_TIMEOUT", time.Second, nil); d != time.Second {
+		t.Errorf("Esperado valor padrão, obtido %s", d)
+	}
+}
+
+func TestNewHTTPClientWithCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	// Sem o bundle, o certificado autoassinado do servidor de teste é rejeitado
+	t.Setenv("CHATCLI_CA_BUNDLE", "")
+	_, err := NewHTTPClient(zap.NewNop(), time.Second).Get(server.URL)
+	if err == nil || !IsTLSVerificationError(err) {
+		t.Fatalf("Esperado erro de verificação TLS, obtido: %v", err)
+	}
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(bundle, certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CHATCLI_CA_BUNDLE", bundle)
+
+	resp, err := NewHTTPClient(zap.NewNop(), time.Second).Get(server.URL)
+	if err != nil {
+		t.Fatalf("Esperado sucesso com CHATCLI_CA_BUNDLE, obtido: %v", err)
+	}
+	resp.Body.Close()
+
+	if _, err := LoadCABundle(filepath.Join(t.TempDir(), "inexistente.pem")); err == nil {
+		t.Error("Esperado erro para bundle inexistente")
+	}
+}
+
+func TestValidateBaseURL(t *testing.T) {
+	valid := map[string]string{
+		"":                                   "",
+		"https://gateway.local/v1/":          "https://gateway.local/v1",
+		"http://localhost:8080/v1":           "http://localhost:8080/v1",
+		" https://example.openai.azure.com ": "https://example.openai.azure.com",
+	}
+	for input, expected := range valid {
+		got, err := ValidateBaseURL(input)
+		if err != nil || got != expected {
+			t.Errorf("ValidateBaseURL(%q) = %q, %v; esperado %q", input, got, err, expected)
+		}
+	}
+	for _, input := range []string{"localhost:8080", "ftp://host/v1", "https://", "https://host/v1?x=1"} {
+		if _, err := ValidateBaseURL(input); err == nil {
+			t.Errorf("Esperado erro para %q", input)
+		}
+	}
+}
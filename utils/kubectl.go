@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// kubectlReadOnlyVerbs são os únicos verbos aceitos por @k8s
+var kubectlReadOnlyVerbs = map[string]bool{"get": true, "describe": true, "logs": true, "top": true}
+
+// kubectlBlockedFlags fazem o comando acompanhar as mudanças sem nunca terminar e por isso são recusadas;
+// -f só é recusada em logs, onde significa --follow
+var kubectlBlockedFlags = []string{"-w", "--watch", "--watch-only", "--follow"}
+
+// ValidateKubectlArgs aceita apenas comandos de leitura: o primeiro argumento deve ser get, describe,
+// logs ou top. Secrets não podem ser lidos com get, que exibiria os valores; describe mostra apenas os tamanhos.
+// --raw é recusada, pois o caminho da API chega a qualquer recurso, inclusive secrets.
+func ValidateKubectlArgs(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("informe um comando do kubectl, como get pods")
+	}
+	verb := strings.ToLower(args[0])
+	if !kubectlReadOnlyVerbs[verb] {
+		return fmt.Errorf("o verbo %s não é permitido; use apenas get, describe, logs ou top", args[0])
+	}
+	for _, arg := range args[1:] {
+		name, _, _ := strings.Cut(arg, "=")
+		for _, blocked := range kubectlBlockedFlags {
+			if name == blocked || verb == "logs" && name == "-f" {
+				return fmt.Errorf("a flag %s não é permitida, pois o comando não terminaria", name)
+			}
+		}
+		if name == "--raw" {
+			return fmt.Errorf("a flag --raw não é permitida, pois daria acesso direto à API, inclusive a secrets")
+		}
+		if verb == "get" && isSecretResource(arg) {
+			return fmt.Errorf("get de secrets não é permitido, pois exibiria os valores; use describe para ver as chaves")
+		}
+	}
+	return nil
+}
+
+// isSecretResource identifica o recurso secrets em formas como secret, secrets/nome ou pods,secrets
+func isSecretResource(arg string) bool {
+	if strings.HasPrefix(arg, "-") {
+		return false
+	}
+	for _, resource := range strings.Split(strings.ToLower(arg), ",") {
+		resource, _, _ = strings.Cut(resource, "/")
+		resource, _, _ = strings.Cut(resource, ".")
+		if resource == "secret" || resource == "secrets" {
+			return true
+		}
+	}
+	return false
+}
+
+// Kubectl executa o kubectl com os argumentos já validados por ValidateKubectlArgs, no contexto atual
+// do kubeconfig ou no informado com --context
+func Kubectl(ctx context.Context, args []string) (string, error) {
+	if err := ValidateKubectlArgs(args); err != nil {
+		return "", err
+	}
+	output, err := exec.CommandContext(ctx, "kubectl", args...).CombinedOutput()
+	if err != nil {
+		return "", kubectlError(string(output), err)
+	}
+	return string(output), nil
+}
+
+// kubectlError identifica o kubectl ausente, o cluster inacessível, a falta de permissão e o recurso inexistente
+func kubectlError(output string, err error) error {
+	lower := strings.ToLower(output)
+	switch {
+	case errors.Is(err, exec.ErrNotFound):
+		return fmt.Errorf("kubectl não encontrado no PATH")
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("o cluster não respondeu a tempo")
+	case strings.Contains(lower, "unable to connect to the server") || strings.Contains(lower, "refused"):
+		return fmt.Errorf("o cluster não está acessível; verifique o contexto com 'kubectl config current-context'")
+	case strings.Contains(lower, "forbidden"):
+		return fmt.Errorf("sem permissão: %s", strings.TrimSpace(output))
+	case strings.TrimSpace(output) != "":
+		return fmt.Errorf("erro do kubectl: %s", strings.TrimSpace(output))
+	default:
+		return fmt.Errorf("erro ao executar o kubectl: %w", err)
+	}
+}
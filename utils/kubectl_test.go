@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestValidateKubectlArgs(t *testing.T) {
+	for _, args := range [][]string{{"get", "pods", "-A"}, {"describe", "secret", "db"}, {"get", "-f", "deploy.yaml"}, {"top", "pods"}} {
+		if err := ValidateKubectlArgs(args); err != nil {
+			t.Errorf("%q deveria ser aceito: %v", args, err)
+		}
+	}
+	for _, args := range [][]string{nil, {"apply", "-f", "x.yaml"}, {"get", "pods", "--watch=true"}, {"get", "pods,secrets"}, {"get", "secrets.v1/db"}, {"logs", "api", "-f"},
+		{"get", "--raw", "/api/v1/namespaces/default/secrets"}, {"get", "--raw=/api/v1/namespaces/default/secrets/db"}} {
+		if err := ValidateKubectlArgs(args); err == nil {
+			t.Errorf("%q deveria ser recusado", args)
+		}
+	}
+}
+
+func TestKubectlError(t *testing.T) {
+	failed := errors.New("exit status 1")
+	if err := kubectlError("The connection to the server localhost:8080 was refused", failed); !strings.Contains(err.Error(), "não está acessível") {
+		t.Errorf("Erro inesperado: %v", err)
+	}
+	if err := kubectlError("", &exec.Error{Name: "kubectl", Err: exec.ErrNotFound}); !strings.Contains(err.Error(), "não encontrado no PATH") {
+		t.Errorf("Erro inesperado: %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package utils
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -20,8 +21,13 @@ import (
 const (
 	defaultMaxBodySize = 3070 // 3KB
 	defaultMaxLogSize  = 50   // 10MB
+
+	debugHTTPMaxBodySize = 64 * 1024
 )
 
+// fileCore é o core do logger que escreve apenas no arquivo de log, usado por FileLogger
+var fileCore zapcore.Core
+
 // InitializeLogger configura e inicializa um logger com base nas variáveis de ambiente.
 func InitializeLogger() (*zap.Logger, error) {
 	// Definir o nível de log via variável de ambiente, default para Info
@@ -97,6 +103,7 @@ func InitializeLogger() (*zap.Logger, error) {
 
 	// Configuração do core com nível de log definido
 	core := zapcore.NewCore(encoder, writeSyncer, level)
+	fileCore = zapcore.NewCore(encoder, zapcore.AddSync(lumberjackLogger), zapcore.DebugLevel)
 
 	// Construir o logger
 	logger := zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))
@@ -104,12 +111,21 @@ func InitializeLogger() (*zap.Logger, error) {
 	return logger, nil
 }
 
+// FileLogger retorna um logger que escreve apenas no arquivo de log configurado por InitializeLogger,
+// nunca no console. Se o logger ainda não foi inicializado, retorna o fallback.
+func FileLogger(fallback *zap.Logger) *zap.Logger {
+	if fileCore == nil {
+		return fallback
+	}
+	return zap.New(fileCore, zap.AddCaller())
+}
+
 // getMaxLogSizeFromEnv lê a variável de ambiente LOG_MAX_SIZE e retorna o valor em MB.
 // Agora aceita valores como "50MB", "100KB", "1GB", etc.
 func getMaxLogSizeFromEnv() int {
 	envValue := os.Getenv("LOG_MAX_SIZE")
 	if envValue != "" {
-		size, err := parseSize(envValue)
+		size, err := ParseSize(envValue)
 		if err == nil && size > 0 {
 			// Convertemos o valor para MB, pois o lumberjack espera o tamanho em MB
 			return int(size / (1024 * 1024))
@@ -118,8 +134,8 @@ func getMaxLogSizeFromEnv() int {
 	return defaultMaxLogSize
 }
 
-// parseSize converte uma string de tamanho legível (como "50MB", "100KB", "1GB") para bytes.
-func parseSize(sizeStr string) (int64, error) {
+// ParseSize converte uma string de tamanho legível (como "50MB", "100KB", "1GB") para bytes.
+func ParseSize(sizeStr string) (int64, error) {
 	sizeStr = strings.TrimSpace(sizeStr)
 	unit := "B" // Padrão para bytes
 	var multiplier int64 = 1
@@ -154,14 +170,19 @@ type LoggingTransport struct {
 	Logger      *zap.Logger
 	Transport   http.RoundTripper
 	MaxBodySize int
+	// DebugLogger, quando definido (CHATCLI_DEBUG_HTTP), recebe os corpos de requisições e respostas
+	// com até debugHTTPMaxBodySize bytes, já sem credenciais
+	DebugLogger *zap.Logger
 }
 
 // RoundTrip implementa a interface http.RoundTripper
 func (t *LoggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqURL := redactURL(req.URL)
+
 	// Log da requisição
 	t.Logger.Info("Enviando Requisição",
 		zap.String("Método", req.Method),
-		zap.String("URL", req.URL.String()),
+		zap.String("URL", reqURL),
 		zap.String("Cabeçalhos", headersToString(req.Header)),
 	)
 
@@ -174,9 +195,7 @@ func (t *LoggingTransport) RoundTrip(req *http.Request) (*http.Response, error)
 			return nil, err
 		}
 		req.Body = io.NopCloser(bytes.NewBuffer(reqBodyBytes)) // Resetar o Body
-		// Remover dados sensíveis do corpo
-		sanitizedBody := t.sanitizeBody(req.Header.Get("Content-Type"), reqBodyBytes)
-		t.Logger.Debug("Corpo da Requisição", zap.ByteString("Body", sanitizedBody))
+		t.logBody("Corpo da Requisição", req.Header.Get("Content-Type"), reqBodyBytes)
 	}
 
 	start := time.Now()
@@ -186,8 +205,8 @@ func (t *LoggingTransport) RoundTrip(req *http.Request) (*http.Response, error)
 	if err != nil {
 		t.Logger.Error("Erro na Requisição",
 			zap.String("Método", req.Method),
-			zap.String("URL", req.URL.String()),
-			zap.Error(err),
+			zap.String("URL", reqURL),
+			zap.String("error", redactError(err)),
 			zap.Duration("Duração", duration),
 		)
 		return resp, err
@@ -196,7 +215,7 @@ func (t *LoggingTransport) RoundTrip(req *http.Request) (*http.Response, error)
 	// Log da resposta
 	t.Logger.Info("Recebendo Resposta",
 		zap.String("Método", req.Method),
-		zap.String("URL", req.URL.String()),
+		zap.String("URL", reqURL),
 		zap.Int("Status", resp.StatusCode),
 		zap.Duration("Duração", duration),
 		zap.String("Cabeçalhos", headersToString(resp.Header)),
@@ -207,24 +226,55 @@ func (t *LoggingTransport) RoundTrip(req *http.Request) (*http.Response, error)
 		var err error
 		respBodyBytes, err = io.ReadAll(resp.Body)
 		if err != nil {
-			t.Logger.Error("Erro ao ler o corpo da resposta", zap.Error(err))
+			t.Logger.Error("Erro ao ler o corpo da resposta", zap.String("error", redactError(err)))
 			return nil, err
 		}
 		resp.Body = io.NopCloser(bytes.NewBuffer(respBodyBytes)) // Resetar o Body
-		// Remover dados sensíveis do corpo
-		sanitizedBody := t.sanitizeBody(resp.Header.Get("Content-Type"), respBodyBytes)
-		t.Logger.Debug("Corpo da Resposta", zap.ByteString("Body", sanitizedBody))
+		t.logBody("Corpo da Resposta", resp.Header.Get("Content-Type"), respBodyBytes)
 	}
 
 	return resp, nil
 }
 
+// logBody registra o corpo já sanitizado: no DebugLogger, quando CHATCLI_DEBUG_HTTP está ativo,
+// ou em nível debug no logger padrão
+func (t *LoggingTransport) logBody(msg, contentType string, body []byte) {
+	if t.DebugLogger != nil {
+		t.DebugLogger.Info(msg, zap.ByteString("Body", sanitizeBody(contentType, body, debugHTTPMaxBodySize)))
+		return
+	}
+	t.Logger.Debug(msg, zap.ByteString("Body", t.sanitizeBody(contentType, body)))
+}
+
+// sensitiveHeaders são os cabeçalhos cujo valor nunca é registrado
+var sensitiveHeaders = map[string]bool{
+	"authorization":       true,
+	"proxy-authorization": true,
+	"api-key":             true,
+	"x-api-key":           true,
+	"cookie":              true,
+	"set-cookie":          true,
+}
+
+// sensitiveFields são os campos de corpo e parâmetros de URL mascarados nos logs (comparação sem diferenciar maiúsculas)
+var sensitiveFields = map[string]bool{
+	"api_key":       true,
+	"apikey":        true,
+	"key":           true,
+	"password":      true,
+	"secret":        true,
+	"client_secret": true,
+	"token":         true,
+	"access_token":  true,
+	"refresh_token": true,
+	"authorization": true,
+}
+
 // headersToString converte os cabeçalhos para uma string legível
 func headersToString(headers http.Header) string {
 	var buf strings.Builder
 	for key, values := range headers {
-		lowerKey := strings.ToLower(key)
-		if lowerKey == "authorization" || lowerKey == "api-key" || lowerKey == "x-api-key" {
+		if sensitiveHeaders[strings.ToLower(key)] {
 			buf.WriteString(fmt.Sprintf("%s: [REDACTED]; ", key))
 			continue
 		}
@@ -237,21 +287,18 @@ func headersToString(headers http.Header) string {
 
 // sanitizeBody remove ou mascara dados sensíveis do corpo da requisição/resposta
 func (t *LoggingTransport) sanitizeBody(contentType string, body []byte) []byte {
-	if len(body) > t.MaxBodySize {
+	return sanitizeBody(contentType, body, t.MaxBodySize)
+}
+
+func sanitizeBody(contentType string, body []byte, maxSize int) []byte {
+	if len(body) > maxSize {
 		return []byte(fmt.Sprintf("[Corpo muito grande para ser logado, tamanho: %d bytes]", len(body)))
 	}
 
 	if strings.Contains(contentType, "application/json") {
-		var data map[string]interface{}
+		var data interface{}
 		if err := json.Unmarshal(body, &data); err == nil {
-			// Exemplo: Mascara campos sensíveis
-			if _, exists := data["api_key"]; exists {
-				data["api_key"] = "[REDACTED]"
-			}
-			if _, exists := data["password"]; exists {
-				data["password"] = "[REDACTED]"
-			}
-			sanitized, _ := json.Marshal(data)
+			sanitized, _ := json.Marshal(redactJSON(data))
 			return sanitized
 		}
 	}
@@ -259,16 +306,65 @@ func (t *LoggingTransport) sanitizeBody(contentType string, body []byte) []byte
 	if strings.Contains(contentType, "application/x-www-form-urlencoded") {
 		values, err := url.ParseQuery(string(body))
 		if err == nil {
-			if _, exists := values["password"]; exists {
-				values.Set("password", "[REDACTED]")
-			}
-			if _, exists := values["api_key"]; exists {
-				values.Set("api_key", "[REDACTED]")
-			}
-			return []byte(values.Encode())
+			return []byte(redactValues(values).Encode())
 		}
 	}
 
 	// Retorna o corpo original se não puder sanitizar
 	return body
 }
+
+// redactJSON mascara recursivamente os campos sensíveis de um documento JSON
+func redactJSON(data interface{}) interface{} {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if sensitiveFields[strings.ToLower(key)] {
+				v[key] = "[REDACTED]"
+				continue
+			}
+			v[key] = redactJSON(value)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redactJSON(value)
+		}
+	}
+	return data
+}
+
+// redactValues mascara os parâmetros sensíveis de um formulário ou query string
+func redactValues(values url.Values) url.Values {
+	for key := range values {
+		if sensitiveFields[strings.ToLower(key)] {
+			values.Set(key, "[REDACTED]")
+		}
+	}
+	return values
+}
+
+// redactURL retorna a URL sem credenciais de usuário e com os parâmetros sensíveis mascarados
+func redactURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	redacted := *u
+	if redacted.User != nil {
+		redacted.User = url.User("[REDACTED]")
+	}
+	if redacted.RawQuery != "" {
+		redacted.RawQuery = redactValues(redacted.Query()).Encode()
+	}
+	return redacted.String()
+}
+
+// redactError retorna a mensagem do erro sem a URL original, que pode conter credenciais
+func redactError(err error) string {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		if u, parseErr := url.Parse(urlErr.URL); parseErr == nil {
+			return fmt.Sprintf("%s %q: %v", urlErr.Op, redactURL(u), urlErr.Err)
+		}
+	}
+	return err.Error()
+}
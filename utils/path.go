@@ -2,6 +2,7 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
 	"net"
 	"os"
@@ -18,32 +19,11 @@ func ReadFileContent(filePath string, maxSize int64) (string, error) {
 		maxSize = 1 * 1024 * 1024 // 1MB
 	}
 
-	// Expandir ~ para o diretório home
-	expandedPath, err := ExpandPath(filePath)
+	absPath, info, err := statRegularFile(filePath)
 	if err != nil {
 		return "", err
 	}
 
-	// Tornar o caminho absoluto
-	absPath, err := filepath.Abs(expandedPath)
-	if err != nil {
-		return "", fmt.Errorf("não foi possível determinar o caminho absoluto: %w", err)
-	}
-
-	// Verificar se o arquivo existe
-	info, err := os.Stat(absPath)
-	if os.IsNotExist(err) {
-		return "", fmt.Errorf("o arquivo não existe: %s", absPath)
-	}
-	if err != nil {
-		return "", fmt.Errorf("erro ao acessar o arquivo: %w", err)
-	}
-
-	// Verificar se é um arquivo regular
-	if !info.Mode().IsRegular() {
-		return "", fmt.Errorf("o caminho não aponta para um arquivo regular: %s", absPath)
-	}
-
 	// Verificar o tamanho do arquivo
 	if info.Size() > maxSize {
 		return "", fmt.Errorf("o arquivo é muito grande (limite de %d bytes)", maxSize)
@@ -64,9 +44,40 @@ func ReadFileContent(filePath string, maxSize int64) (string, error) {
 	return content, nil
 }
 
+// statRegularFile expande ~, torna o caminho absoluto e verifica se ele aponta para um arquivo regular
+func statRegularFile(filePath string) (string, os.FileInfo, error) {
+	// Expandir ~ para o diretório home
+	expandedPath, err := ExpandPath(filePath)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Tornar o caminho absoluto
+	absPath, err := filepath.Abs(expandedPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("não foi possível determinar o caminho absoluto: %w", err)
+	}
+
+	// Verificar se o arquivo existe
+	info, err := os.Stat(absPath)
+	if os.IsNotExist(err) {
+		return "", nil, fmt.Errorf("o arquivo não existe: %s", absPath)
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("erro ao acessar o arquivo: %w", err)
+	}
+
+	// Verificar se é um arquivo regular
+	if !info.Mode().IsRegular() {
+		return "", nil, fmt.Errorf("o caminho não aponta para um arquivo regular: %s", absPath)
+	}
+	return absPath, info, nil
+}
+
 // IsTemporaryError verifica se o erro é temporário e pode ser retryado.
 func IsTemporaryError(err error) bool {
-	if ne, ok := err.(net.Error); ok {
+	var ne net.Error
+	if errors.As(err, &ne) {
 		return ne.Temporary() || ne.Timeout()
 	}
 	return false
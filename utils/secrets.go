@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// secretPattern é um padrão de segredo reconhecido por ScrubSecrets. Quando o padrão tem um grupo de
+// captura, apenas o grupo é ocultado, preservando o nome da variável ou do campo.
+type secretPattern struct {
+	kind    string
+	pattern *regexp.Regexp
+}
+
+var secretPatterns = []secretPattern{
+	{"chave privada", regexp.MustCompile(`-----BEGIN [A-Z0-9 ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z0-9 ]*PRIVATE KEY-----`)},
+	{"AWS access key", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"AWS secret key", regexp.MustCompile(`(?i)aws_secret_access_key["']?\s*[=:]\s*["']?([A-Za-z0-9/+=]{40})`)},
+	{"JWT", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+	{"chave de API", regexp.MustCompile(`\bsk-(?:ant-|proj-)?[A-Za-z0-9_-]{20,}`)},
+	{"token do GitHub", regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{"token do Slack", regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`)},
+	// Variáveis de ambiente e arquivos .env (NOME=valor), como em @env
+	{"senha ou token", regexp.MustCompile(`(?m)^\s*(?:export\s+)?[A-Z0-9_]*(?:SECRET|TOKEN|PASSWORD|PASSWD|API_?KEY)[A-Z0-9_]*=["']?([^\s"']{8,})`)},
+	// Campos de JSON e YAML com valor entre aspas ("password": "valor")
+	{"senha ou token", regexp.MustCompile(`(?i)["']?[a-z0-9_]*(?:secret|token|password|passwd|api_?key)["']?\s*:\s*["']([^\s"']{8,})["']`)},
+}
+
+// ScrubSecrets substitui os segredos encontrados no texto por [REDACTED:<tipo>] e retorna o texto
+// resultante e o tipo de cada segredo ocultado. Valores presentes em allowlist são mantidos.
+func ScrubSecrets(text string, allowlist []string) (string, []string) {
+	allowed := make(map[string]bool, len(allowlist))
+	for _, value := range allowlist {
+		allowed[value] = true
+	}
+
+	var found []string
+	for _, sp := range secretPatterns {
+		var builder strings.Builder
+		last := 0
+		for _, m := range sp.pattern.FindAllStringSubmatchIndex(text, -1) {
+			start, end := m[0], m[1]
+			if len(m) >= 4 && m[2] >= 0 {
+				start, end = m[2], m[3]
+			}
+			secret := text[start:end]
+			if allowed[secret] || strings.HasPrefix(secret, "[REDACTED") {
+				continue
+			}
+			builder.WriteString(text[last:start])
+			builder.WriteString("[REDACTED:" + sp.kind + "]")
+			last = end
+			found = append(found, sp.kind)
+		}
+		if last > 0 {
+			builder.WriteString(text[last:])
+			text = builder.String()
+		}
+	}
+	return text, found
+}
@@ -88,7 +88,7 @@ func CheckProviderEnvVariables(logger *zap.Logger) {
 
 	// Verificar OPENAI
 	openAIKey := os.Getenv("OPENAI_API_KEY")
-	if openAIKey == "" {
+	if openAIKey == "" && os.Getenv("OPENAI_API_KEYS") == "" {
 		fmt.Println("ATENÇÃO: OPENAI_API_KEY não definida, o provedor OPENAI não estará disponível.")
 	}
 	// Verificar OPENAI